@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -35,6 +36,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/mattn/go-colorable"
+	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 )
 
@@ -75,6 +77,8 @@ const (
 	ACTION_UPDATE     = true
 	ACTION_CREATE     = false
 	MAX_JS_INT        = 1<<53 - 1
+
+	DefaultInvocationLog = "~/.wskinvocations"
 )
 
 var actionCmd = &cobra.Command{
@@ -109,6 +113,10 @@ var actionCreateCmd = &cobra.Command{
 			return actionParseError(cmd, args, err)
 		}
 
+		if err = checkActionLimits(action); err != nil {
+			return actionParseError(cmd, args, err)
+		}
+
 		if _, _, err = Client.Actions.Insert(action, false); err != nil {
 			return actionInsertError(action, err)
 		}
@@ -146,6 +154,10 @@ var actionUpdateCmd = &cobra.Command{
 			return actionParseError(cmd, args, err)
 		}
 
+		if err = checkActionLimits(action); err != nil {
+			return actionParseError(cmd, args, err)
+		}
+
 		if _, _, err = Client.Actions.Insert(action, true); err != nil {
 			return actionInsertError(action, err)
 		}
@@ -185,12 +197,16 @@ var actionInvokeCmd = &cobra.Command{
 		resultOnly := Flags.action.result
 		header := !resultOnly
 
-		res, err := invokeAction(
+		res, resp, err := invokeActionWithResponse(
 			*qualifiedName,
 			parameters,
 			blocking,
 			resultOnly)
 
+		if Flags.action.record {
+			recordInvocation(*qualifiedName, parameters, res, resp)
+		}
+
 		return printInvocationResponse(*qualifiedName, blocking, header, res, err)
 	},
 }
@@ -200,14 +216,133 @@ func invokeAction(
 	parameters interface{},
 	blocking bool,
 	result bool) (interface{}, error) {
+	res, _, err := invokeActionWithResponse(qualifiedName, parameters, blocking, result)
+	return res, err
+}
+
+func invokeActionWithResponse(
+	qualifiedName QualifiedName,
+	parameters interface{},
+	blocking bool,
+	result bool) (interface{}, *http.Response, error) {
 	// TODO remove all global modifiers
 	Client.Namespace = qualifiedName.GetNamespace()
-	res, _, err := Client.Actions.Invoke(
+	res, resp, err := Client.Actions.Invoke(
 		qualifiedName.GetEntityName(),
 		parameters,
 		blocking,
 		result)
-	return res, err
+	return res, resp, err
+}
+
+// invocationRecord is one entry of the local invocation log, which keeps the parameters of recent
+// invocations so that `wsk activation rerun` can recover them
+type invocationRecord struct {
+	ActivationID string      `json:"activationId"`
+	Action       string      `json:"action"`
+	Parameters   interface{} `json:"parameters"`
+	Time         int64       `json:"time"`
+}
+
+// getInvocationLogPath() returns the path of the local invocation log. The WSK_INVOCATION_LOG
+// environment variable overrides the default path; an empty value turns the log off.
+func getInvocationLogPath() string {
+	if logPath, envExists := os.LookupEnv("WSK_INVOCATION_LOG"); envExists {
+		return logPath
+	}
+
+	logPath, err := homedir.Expand(DefaultInvocationLog)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "homedir.Expand(%s) failed: %s\n", DefaultInvocationLog, err)
+		return ""
+	}
+
+	return logPath
+}
+
+func readInvocationLog(logPath string) []invocationRecord {
+	var records []invocationRecord
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			whisk.Debug(whisk.DbgWarn, "os.Open(%s) failed: %s\n", logPath, err)
+		}
+		return records
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	for {
+		var record invocationRecord
+		if err = decoder.Decode(&record); err != nil {
+			if err != io.EOF {
+				whisk.Debug(whisk.DbgWarn, "Invocation log %s is malformed: %s\n", logPath, err)
+			}
+			return records
+		}
+		records = append(records, record)
+	}
+}
+
+// recordInvocation(qualifiedName, parameters, res, resp) appends an invocation to the local
+// invocation log. The log is a convenience, so failures are only reported in debug output.
+func recordInvocation(qualifiedName QualifiedName, parameters interface{}, res interface{}, resp *http.Response) {
+	logPath := getInvocationLogPath()
+	if len(logPath) == 0 {
+		return
+	}
+
+	activationID, _ := getValueFromResponse(ACTIVATION_ID, res).(string)
+	if len(activationID) == 0 && resp != nil {
+		activationID = resp.Header.Get("X-Openwhisk-Activation-Id")
+	}
+	if len(activationID) == 0 {
+		whisk.Debug(whisk.DbgInfo, "No activation ID in the response; the invocation is not recorded\n")
+		return
+	}
+
+	record := invocationRecord{
+		ActivationID: activationID,
+		Action:       qualifiedName.GetFullQualifiedName(),
+		Parameters:   parameters,
+		Time:         time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	content, err := json.Marshal(record)
+	if err != nil {
+		whisk.Debug(whisk.DbgWarn, "json.Marshal(%#v) failed: %s\n", record, err)
+		return
+	}
+
+	// The parameters may contain credentials, so the log is only readable by its owner
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		whisk.Debug(whisk.DbgWarn, "os.OpenFile(%s) failed: %s\n", logPath, err)
+		return
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(content, '\n')); err != nil {
+		whisk.Debug(whisk.DbgWarn, "Writing invocation log %s failed: %s\n", logPath, err)
+	}
+}
+
+// findInvocation(activationID) looks up an activation in the local invocation log
+func findInvocation(activationID string) *invocationRecord {
+	logPath := getInvocationLogPath()
+	if len(logPath) == 0 {
+		return nil
+	}
+
+	records := readInvocationLog(logPath)
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].ActivationID == activationID {
+			return &records[i]
+		}
+	}
+
+	return nil
 }
 
 func printInvocationResponse(
@@ -301,13 +436,17 @@ var actionGetCmd = &cobra.Command{
 				return werr
 			}
 			printActionGetWithURL(qualifiedName.GetEntity(), actionURL)
+		} else if len(Flags.common.template) > 0 {
+			return printTemplate(action, Flags.common.template)
 		} else if Flags.common.summary {
 			printSummary(action)
 		} else if cmd.LocalFlags().Changed(SAVE_AS_FLAG) || cmd.LocalFlags().Changed(SAVE_FLAG) {
 			return saveCode(*action, Flags.action.saveAs)
 		} else {
 			if len(field) > 0 {
-				printActionGetWithField(qualifiedName.GetEntityName(), field, action)
+				if !printField(action, field) {
+					return invalidFieldFilterError(field)
+				}
 			} else {
 				printActionGet(qualifiedName.GetEntityName(), action)
 			}
@@ -380,17 +519,50 @@ var actionListCmd = &cobra.Command{
 			Client.Namespace = qualifiedName.GetNamespace()
 		}
 
-		options := &whisk.ActionListOptions{
-			Skip:  Flags.common.skip,
-			Limit: Flags.common.limit,
+		query, err := newListQuery(Flags.common.filter, Flags.common.sortBy)
+		if err != nil {
+			return err
 		}
 
-		if actions, _, err = Client.Actions.List(qualifiedName.GetEntityName(), options); err != nil {
-			return actionListError(qualifiedName.GetEntityName(), options, err)
+		// An explicit --sort-by order takes precedence over --name-sort
+		sortByName := Flags.common.nameSort && !query.sorted()
+
+		if Flags.common.all {
+			// Stream each page as it arrives unless the complete set is needed for sorting
+			stream := !sortByName && !query.sorted()
+			err = listAllPages(Flags.common.skip, func(skip int, limit int) (int, error) {
+				options := &whisk.ActionListOptions{
+					Skip:  skip,
+					Limit: limit,
+				}
+
+				page, _, err := Client.Actions.List(qualifiedName.GetEntityName(), options)
+				if err != nil {
+					return 0, actionListError(qualifiedName.GetEntityName(), options, err)
+				}
+
+				if stream {
+					printListPage(query.apply(page), skip == Flags.common.skip)
+				} else {
+					actions = append(actions, page...)
+				}
+				return len(page), nil
+			})
+			if err != nil || stream {
+				return err
+			}
+		} else {
+			options := &whisk.ActionListOptions{
+				Skip:  Flags.common.skip,
+				Limit: Flags.common.limit,
+			}
+
+			if actions, _, err = Client.Actions.List(qualifiedName.GetEntityName(), options); err != nil {
+				return actionListError(qualifiedName.GetEntityName(), options, err)
+			}
 		}
 
-		sortByName := Flags.common.nameSort
-		printList(actions, sortByName)
+		printList(query.apply(actions), sortByName)
 
 		return nil
 	},
@@ -478,6 +650,11 @@ func parseAction(cmd *cobra.Command, args []string, update bool) (*whisk.Action,
 		if err != nil {
 			return nil, err
 		}
+		if len(Flags.action.kind) > 0 || Flags.action.resolveKind {
+			if err = checkActionKind(action.Exec, Flags.action.resolveKind); err != nil {
+				return nil, err
+			}
+		}
 	} else if !update {
 		return nil, noArtifactError()
 	}
@@ -706,6 +883,16 @@ func getKindExtension(runtime string) (extension string) {
 	return extension
 }
 
+// actionCodeFilename returns the name the code of an action is saved under: the action name with the
+// extension of its kind, which for binary code is that of the archive
+func actionCodeFilename(action *whisk.Action) string {
+	runtime := strings.Split(action.Exec.Kind, ":")[0]
+	if action.Exec.Binary != nil && *action.Exec.Binary {
+		return action.Name + getBinaryKindExtension(runtime)
+	}
+	return action.Name + getKindExtension(runtime)
+}
+
 func saveCode(action whisk.Action, filename string) (err error) {
 	var code string
 	var runtime string
@@ -727,14 +914,10 @@ func saveCode(action whisk.Action, filename string) (err error) {
 	if *exec.Binary {
 		decoded, _ := base64.StdEncoding.DecodeString(code)
 		code = string(decoded)
+	}
 
-		if len(filename) == 0 {
-			filename = action.Name + getBinaryKindExtension(runtime)
-		}
-	} else {
-		if len(filename) == 0 {
-			filename = action.Name + getKindExtension(runtime)
-		}
+	if len(filename) == 0 {
+		filename = actionCodeFilename(&action)
 	}
 
 	if exists, err := FileExists(filename); err != nil {
@@ -1189,20 +1372,6 @@ func printInvocationMsg(
 	}
 }
 
-func printActionGetWithField(entityName string, field string, action *whisk.Action) {
-	fmt.Fprintf(
-		color.Output,
-		wski18n.T(
-			"{{.ok}} got action {{.name}}, displaying field {{.field}}\n",
-			map[string]interface{}{
-				"ok":    color.GreenString("ok:"),
-				"name":  boldString(entityName),
-				"field": boldString(field),
-			}))
-
-	printField(action, field)
-}
-
 func printActionGetWithURL(entityName string, actionURL string) {
 	fmt.Fprintf(
 		color.Output,
@@ -1293,6 +1462,7 @@ func init() {
 	actionCreateCmd.Flags().BoolVar(&Flags.action.copy, "copy", false, wski18n.T("treat ACTION as the name of an existing action"))
 	actionCreateCmd.Flags().BoolVar(&Flags.action.sequence, "sequence", false, wski18n.T("treat ACTION as comma separated sequence of actions to invoke"))
 	actionCreateCmd.Flags().StringVar(&Flags.action.kind, "kind", "", wski18n.T("the `KIND` of the action runtime (example: swift:default, nodejs:default)"))
+	actionCreateCmd.Flags().BoolVar(&Flags.action.resolveKind, "resolve-default", false, wski18n.T("replace a kind ending in :default with the default kind of the API host"))
 	actionCreateCmd.Flags().StringVar(&Flags.action.main, "main", "", wski18n.T("the name of the action entry point (function or fully-qualified method name when applicable)"))
 	actionCreateCmd.Flags().IntVarP(&Flags.action.timeout, TIMEOUT_FLAG, "t", TIMEOUT_LIMIT, wski18n.T("the timeout `LIMIT` in milliseconds after which the action is terminated"))
 	actionCreateCmd.Flags().IntVarP(&Flags.action.memory, MEMORY_FLAG, "m", MEMORY_LIMIT, wski18n.T("the maximum memory `LIMIT` in MB for the action"))
@@ -1310,6 +1480,7 @@ func init() {
 	actionUpdateCmd.Flags().BoolVar(&Flags.action.copy, "copy", false, wski18n.T("treat ACTION as the name of an existing action"))
 	actionUpdateCmd.Flags().BoolVar(&Flags.action.sequence, "sequence", false, wski18n.T("treat ACTION as comma separated sequence of actions to invoke"))
 	actionUpdateCmd.Flags().StringVar(&Flags.action.kind, "kind", "", wski18n.T("the `KIND` of the action runtime (example: swift:default, nodejs:default)"))
+	actionUpdateCmd.Flags().BoolVar(&Flags.action.resolveKind, "resolve-default", false, wski18n.T("replace a kind ending in :default with the default kind of the API host"))
 	actionUpdateCmd.Flags().StringVar(&Flags.action.main, "main", "", wski18n.T("the name of the action entry point (function or fully-qualified method name when applicable)"))
 	actionUpdateCmd.Flags().IntVarP(&Flags.action.timeout, TIMEOUT_FLAG, "t", TIMEOUT_LIMIT, wski18n.T("the timeout `LIMIT` in milliseconds after which the action is terminated"))
 	actionUpdateCmd.Flags().IntVarP(&Flags.action.memory, MEMORY_FLAG, "m", MEMORY_LIMIT, wski18n.T("the maximum memory `LIMIT` in MB for the action"))
@@ -1327,15 +1498,21 @@ func init() {
 	actionInvokeCmd.Flags().StringVarP(&Flags.common.paramFile, "param-file", "P", "", wski18n.T("`FILE` containing parameter values in JSON format"))
 	actionInvokeCmd.Flags().BoolVarP(&Flags.common.blocking, "blocking", "b", false, wski18n.T("blocking invoke"))
 	actionInvokeCmd.Flags().BoolVarP(&Flags.action.result, "result", "r", false, wski18n.T("blocking invoke; show only activation result (unless there is a failure)"))
+	actionInvokeCmd.Flags().BoolVar(&Flags.action.record, "record", false, wski18n.T("record the parameters in the local invocation log so that 'wsk activation rerun' can reuse them"))
 
 	actionGetCmd.Flags().BoolVarP(&Flags.common.summary, "summary", "s", false, wski18n.T("summarize action details; parameters with prefix \"*\" are bound, \"**\" are bound and finalized"))
 	actionGetCmd.Flags().BoolVarP(&Flags.action.url, "url", "r", false, wski18n.T("get action url"))
 	actionGetCmd.Flags().StringVar(&Flags.action.saveAs, SAVE_AS_FLAG, "", wski18n.T("file to save action code to"))
 	actionGetCmd.Flags().BoolVarP(&Flags.action.save, SAVE_FLAG, "", false, wski18n.T("save action code to file corresponding with action name"))
+	actionGetCmd.Flags().StringVar(&Flags.common.template, "template", "", wski18n.T("format the action with the Go `TEMPLATE`, e.g. {{.example}}",
+		map[string]interface{}{"example": "'{{.name}} {{.exec.kind}}'"}))
 
 	actionListCmd.Flags().IntVarP(&Flags.common.skip, "skip", "s", 0, wski18n.T("exclude the first `SKIP` number of actions from the result"))
 	actionListCmd.Flags().IntVarP(&Flags.common.limit, "limit", "l", 30, wski18n.T("only return `LIMIT` number of actions from the collection"))
 	actionListCmd.Flags().BoolVarP(&Flags.common.nameSort, "name-sort", "n", false, wski18n.T("sorts a list alphabetically by entity name; only applicable within the limit/skip returned entity block"))
+	actionListCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("page through all actions in the collection instead of a single LIMIT block; --name-sort then applies to the complete list"))
+	actionListCmd.Flags().StringArrayVar(&Flags.common.filter, "filter", []string{}, wski18n.T("only list actions matching the FIELD OPERATOR VALUE `EXPRESSION`, e.g. 'limits.memory>256'; may be repeated"))
+	actionListCmd.Flags().StringVar(&Flags.common.sortBy, "sort-by", "", wski18n.T("sort the list by `FIELD`, ascending or, with FIELD:desc, descending"))
 
 	actionCmd.AddCommand(
 		actionCreateCmd,
@@ -1344,5 +1521,6 @@ func init() {
 		actionGetCmd,
 		actionDeleteCmd,
 		actionListCmd,
+		actionMoveCmd,
 	)
 }
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var actionMoveCmd = &cobra.Command{
+	Use:           "move SOURCE_ACTION DESTINATION_ACTION",
+	Short:         wski18n.T("move an action to a new name or package, updating the sequences and rules that use it"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 2, 2, "Action move",
+			wski18n.T("A source and a destination action name are required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		source, err := NewQualifiedName(args[0])
+		if err != nil {
+			return NewQualifiedNameError(args[0], err)
+		}
+		destination, err := NewQualifiedName(args[1])
+		if err != nil {
+			return NewQualifiedNameError(args[1], err)
+		}
+
+		count, err := moveAction(source, destination)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} moved action {{.name}} to {{.destination}}; updated {{.count}} sequences and rules that use it\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(source.GetEntityName()),
+				"destination": boldString(destination.GetEntityName()), "count": count}))
+		return nil
+	},
+}
+
+// moveAction creates a copy of an action under the destination name, points the sequences and rules
+// of the source namespace at it and deletes the original, undoing every step if one fails
+func moveAction(source *QualifiedName, destination *QualifiedName) (int, error) {
+	Client.Namespace = source.GetNamespace()
+	action, _, err := Client.Actions.Get(source.GetEntityName(), FETCH_CODE)
+	if err != nil {
+		return 0, actionGetError(source.GetEntityName(), FETCH_CODE, err)
+	}
+
+	moved := &whisk.Action{
+		Name:        destination.GetEntityName(),
+		Namespace:   destination.GetNamespace(),
+		Annotations: action.Annotations,
+		Parameters:  action.Parameters,
+		Limits:      action.Limits,
+	}
+	if action.Exec != nil {
+		exec := *action.Exec
+		exec.Binary = nil
+		moved.Exec = &exec
+	}
+
+	Client.Namespace = destination.GetNamespace()
+	if _, _, err = Client.Actions.Insert(moved, false); err != nil {
+		return 0, actionInsertError(moved, err)
+	}
+
+	changes := new(entityChanges)
+	changes.add(func() error { return deleteAction(destination.GetNamespace(), destination.GetEntityName()) })
+
+	renamed := map[string]string{
+		fmt.Sprintf("/%s/%s", action.Namespace, action.Name): destination.GetFullQualifiedName(),
+	}
+	count, err := rewriteReferences(source.GetNamespace(), renamed, changes)
+	if err == nil {
+		err = deleteAction(source.GetNamespace(), source.GetEntityName())
+	}
+	if err != nil {
+		changes.rollback()
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -18,11 +18,16 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -54,7 +59,6 @@ var activationListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var err error
 		var qualifiedName = new(QualifiedName)
-		var orderedFilteredRow []whisk.ActivationFilteredRow
 
 		if whiskErr := CheckArgs(args, 0, 1, "Activation list",
 			wski18n.T("An optional namespace is the only valid argument.")); whiskErr != nil {
@@ -72,6 +76,53 @@ var activationListCmd = &cobra.Command{
 			Client.Namespace = qualifiedName.GetNamespace()
 		}
 
+		query, err := newListQuery(Flags.common.filter, Flags.common.sortBy)
+		if err != nil {
+			return err
+		}
+
+		var activations []whisk.Activation
+
+		if Flags.common.all {
+			// Activations are always listed by creation time, so each page is printed as it arrives
+			// unless the complete set is needed for sorting
+			stream := !query.sorted()
+			var widths activationColumnWidths
+			err = listAllPages(Flags.common.skip, func(skip int, limit int) (int, error) {
+				options := &whisk.ActivationListOptions{
+					Name:  qualifiedName.GetEntityName(),
+					Limit: limit,
+					Skip:  skip,
+					Upto:  Flags.activation.upto,
+					Since: Flags.activation.since,
+					Docs:  Flags.common.full,
+				}
+
+				page, err := listActivations(options)
+				if err != nil {
+					return 0, err
+				}
+
+				if stream {
+					// The header is printed with the first page, so every page is aligned to its columns
+					if skip == Flags.common.skip {
+						widths = streamedActivationColumnWidths(page)
+					}
+					printActivationList(query.apply(page).([]whisk.Activation), options.Docs, skip == Flags.common.skip, widths)
+				} else {
+					activations = append(activations, page...)
+				}
+				return len(page), nil
+			})
+			if err != nil || stream {
+				return err
+			}
+
+			activations = query.apply(activations).([]whisk.Activation)
+			printActivationList(activations, Flags.common.full, true, getActivationColumnWidths(activations))
+			return nil
+		}
+
 		options := &whisk.ActivationListOptions{
 			Name:  qualifiedName.GetEntityName(),
 			Limit: Flags.common.limit,
@@ -81,36 +132,80 @@ var activationListCmd = &cobra.Command{
 			Docs:  Flags.common.full,
 		}
 
-		activations, _, err := Client.Activations.List(options)
-		if err != nil {
-			whisk.Debug(whisk.DbgError, "Client.Activations.List() error: %s\n", err)
-			errStr := wski18n.T("Unable to obtain the list of activations for namespace '{{.name}}': {{.err}}",
-				map[string]interface{}{"name": getClientNamespace(), "err": err})
-			werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL,
-				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-			return werr
+		if activations, err = listActivations(options); err != nil {
+			return err
 		}
 
-		// When the --full (URL contains "?docs=true") option is specified, display the entire activation details
-		if options.Docs == true {
-			printFullActivationList(activations)
-		} else {
-			maxKindSize := max(len("Kind"), getLargestKindSize(activations))
-			maxStatusSize := max(len("Status"), getLargestStatusSize(activations))
+		activations = query.apply(activations).([]whisk.Activation)
+		printActivationList(activations, options.Docs, true, getActivationColumnWidths(activations))
+
+		return nil
+	},
+}
+
+func listActivations(options *whisk.ActivationListOptions) ([]whisk.Activation, error) {
+	activations, _, err := Client.Activations.List(options)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Activations.List() error: %s\n", err)
+		errStr := wski18n.T("Unable to obtain the list of activations for namespace '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": getClientNamespace(), "err": err})
+		werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL,
+			whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		return nil, werr
+	}
 
-			// Header string should show "Datetime", "Activation ID", "Kind", "Start", "Duration", "Status", "Entity", with Kind and Status being
-			// dynamically sized. The last column Entity will be sized correctly when printed, so no need to calculate size here
-			headerFmt := "%-19s %-32s %-" + strconv.Itoa(maxKindSize) + "s %-6s%-10s %-" + strconv.Itoa(maxStatusSize) + "s %-6s\n"
-			rowFmt := "%d-%02d-%02d %02d:%02d:%02d %-32s %-" + strconv.Itoa(maxKindSize) + "s %-5s %-10v %-" + strconv.Itoa(maxStatusSize) + "s %-"
+	return activations, nil
+}
 
-			for i := 0; i < len(activations); i++ {
-				orderedFilteredRow = append(orderedFilteredRow, whisk.ActivationFilteredRow{Row: activations[i], HeaderFmt: headerFmt, RowFmt: rowFmt})
+// activationColumnWidths are the widths of the dynamically sized Kind and Status columns
+type activationColumnWidths struct {
+	kind   int
+	status int
+}
+
+func getActivationColumnWidths(activations []whisk.Activation) activationColumnWidths {
+	return activationColumnWidths{
+		kind:   max(len("Kind"), getLargestKindSize(activations)),
+		status: max(len("Status"), getLargestStatusSize(activations)),
+	}
+}
+
+// streamedActivationColumnWidths(page) sizes the columns of a list printed page by page from its first
+// page; the Status column fits every status so that only an unusually long kind can break the alignment
+func streamedActivationColumnWidths(page []whisk.Activation) activationColumnWidths {
+	widths := getActivationColumnWidths(page)
+	for _, status := range whisk.StatusCodes {
+		widths.status = max(widths.status, len(status))
+	}
+	return widths
+}
+
+// printActivationList(activations, full, header, widths) prints a list of activations, or a page of
+// one; the header is only printed when requested so that streamed pages form a single list
+func printActivationList(activations []whisk.Activation, full bool, header bool, widths activationColumnWidths) {
+	var orderedFilteredRow []whisk.ActivationFilteredRow
+
+	// When the --full (URL contains "?docs=true") option is specified, display the entire activation details
+	if full {
+		if header {
+			printFullActivationList(activations)
+		} else {
+			for _, activation := range activations {
+				printJSON(activation)
 			}
-			printList(orderedFilteredRow, false) // Default sorting for Activations are by creation time, hence sortByName is always false
 		}
+		return
+	}
 
-		return nil
-	},
+	// Header string should show "Datetime", "Activation ID", "Kind", "Start", "Duration", "Status", "Entity", with Kind and Status being
+	// dynamically sized. The last column Entity will be sized correctly when printed, so no need to calculate size here
+	headerFmt := "%-19s %-32s %-" + strconv.Itoa(widths.kind) + "s %-6s%-10s %-" + strconv.Itoa(widths.status) + "s %-6s\n"
+	rowFmt := "%d-%02d-%02d %02d:%02d:%02d %-32s %-" + strconv.Itoa(widths.kind) + "s %-5s %-10v %-" + strconv.Itoa(widths.status) + "s %-"
+
+	for i := 0; i < len(activations); i++ {
+		orderedFilteredRow = append(orderedFilteredRow, whisk.ActivationFilteredRow{Row: activations[i], HeaderFmt: headerFmt, RowFmt: rowFmt})
+	}
+	printListPage(orderedFilteredRow, header) // Default sorting for Activations are by creation time, hence they are never sorted by name
 }
 
 var activationGetCmd = &cobra.Command{
@@ -156,7 +251,9 @@ var activationGetCmd = &cobra.Command{
 			return werr
 		}
 
-		if Flags.common.summary {
+		if len(Flags.common.template) > 0 {
+			return printTemplate(activation, Flags.common.template)
+		} else if Flags.common.summary {
 			fmt.Printf(
 				wski18n.T("activation result for '/{{.namespace}}/{{.name}}' ({{.status}} at {{.time}})\n",
 					map[string]interface{}{
@@ -169,11 +266,12 @@ var activationGetCmd = &cobra.Command{
 			printStrippedActivationLogs(activation.Logs)
 		} else {
 			if len(field) > 0 {
-				fmt.Fprintf(color.Output,
-					wski18n.T("{{.ok}} got activation {{.id}}, displaying field {{.field}}\n",
-						map[string]interface{}{"ok": color.GreenString("ok:"), "id": boldString(id),
-							"field": boldString(field)}))
-				printField(activation, field)
+				if !printField(activation, field) {
+					errMsg := wski18n.T("Invalid field filter '{{.arg}}'.", map[string]interface{}{"arg": field})
+					whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+						whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+					return whiskErr
+				}
 			} else {
 				fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got activation {{.id}}\n",
 					map[string]interface{}{"ok": color.GreenString("ok:"), "id": boldString(id)}))
@@ -262,6 +360,432 @@ var activationResultCmd = &cobra.Command{
 	},
 }
 
+var activationExportCmd = &cobra.Command{
+	Use:           "export --to (FILE.ndjson | DIR)",
+	Short:         wski18n.T("export activations, including their results and logs, to a file or directory"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 0, 0, "Activation export",
+			wski18n.T("No arguments are required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		if len(Flags.activation.to) == 0 {
+			whisk.Debug(whisk.DbgError, "No --to destination specified\n")
+			errStr := wski18n.T("An export destination is required; specify a file or directory with --to.")
+			return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_USAGE, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+		}
+
+		export, err := newActivationExport(Flags.activation.to)
+		if err != nil {
+			return err
+		}
+
+		return export.run()
+	},
+}
+
+// Suffix of the checkpoint file written next to an NDJSON export, or inside an export directory
+const ACTIVATION_EXPORT_CHECKPOINT = ".checkpoint"
+
+// activationExportCheckpoint records the progress of an activation export. Upto is fixed when the
+// export starts so that paging with skip stays stable while new activations arrive; Offset is the
+// size of the NDJSON file after the last complete page.
+type activationExportCheckpoint struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name,omitempty"`
+	Since     int64  `json:"since,omitempty"`
+	Upto      int64  `json:"upto"`
+	Skip      int    `json:"skip"`
+	Count     int    `json:"count"`
+	Offset    int64  `json:"offset"`
+}
+
+type activationExport struct {
+	destination string
+	directory   bool
+	checkpoint  string
+	resumed     bool
+	progress    activationExportCheckpoint
+}
+
+// newActivationExport(destination) prepares an export to a single NDJSON file or, when the
+// destination is a directory, to one JSON file per activation; an existing checkpoint for the
+// same export is picked up so that the export resumes where it was interrupted
+func newActivationExport(destination string) (*activationExport, error) {
+	export := &activationExport{destination: destination}
+
+	if info, err := os.Stat(destination); err == nil && info.IsDir() {
+		export.directory = true
+	} else if strings.HasSuffix(destination, string(os.PathSeparator)) || strings.HasSuffix(destination, "/") {
+		export.directory = true
+	}
+
+	if export.directory {
+		if err := os.MkdirAll(destination, 0755); err != nil {
+			whisk.Debug(whisk.DbgError, "os.MkdirAll(%s) error: %s\n", destination, err)
+			errStr := wski18n.T("Cannot create directory '{{.name}}': {{.err}}",
+				map[string]interface{}{"name": destination, "err": err})
+			return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+				whisk.NO_DISPLAY_USAGE)
+		}
+		export.checkpoint = filepath.Join(destination, ACTIVATION_EXPORT_CHECKPOINT)
+	} else {
+		export.checkpoint = destination + ACTIVATION_EXPORT_CHECKPOINT
+	}
+
+	export.progress = activationExportCheckpoint{
+		Namespace: getClientNamespace(),
+		Name:      Flags.activation.action,
+		Since:     Flags.activation.since,
+		Upto:      Flags.activation.upto,
+	}
+
+	if exists, err := FileExists(export.checkpoint); err != nil {
+		return nil, err
+	} else if exists {
+		var saved activationExportCheckpoint
+
+		content, err := ReadFile(export.checkpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal([]byte(content), &saved); err != nil ||
+			saved.Namespace != export.progress.Namespace ||
+			saved.Name != export.progress.Name ||
+			saved.Since != export.progress.Since ||
+			(export.progress.Upto != 0 && saved.Upto != export.progress.Upto) {
+			whisk.Debug(whisk.DbgError, "Checkpoint %s does not match the export: %#v (%v)\n", export.checkpoint, saved, err)
+			errStr := wski18n.T("The checkpoint '{{.checkpoint}}' belongs to a different export; remove it or choose another destination.",
+				map[string]interface{}{"checkpoint": export.checkpoint})
+			return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+				whisk.NO_DISPLAY_USAGE)
+		}
+
+		export.progress = saved
+		export.resumed = true
+		fmt.Fprintf(color.Output, wski18n.T("Resuming export to {{.name}} after {{.count}} activations\n",
+			map[string]interface{}{"name": boldString(destination), "count": saved.Count}))
+	} else if export.progress.Upto == 0 {
+		export.progress.Upto = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+
+	return export, nil
+}
+
+// run() pages through the activations, writing each page and then the checkpoint; the checkpoint
+// is removed once the export is complete
+func (export *activationExport) run() error {
+	var file *os.File
+	var err error
+
+	if !export.directory {
+		if file, err = export.openFile(); err != nil {
+			return err
+		}
+		defer file.Close()
+	}
+
+	err = listAllPages(export.progress.Skip, func(skip int, limit int) (int, error) {
+		options := &whisk.ActivationListOptions{
+			Name:  export.progress.Name,
+			Limit: limit,
+			Skip:  skip,
+			Since: export.progress.Since,
+			Upto:  export.progress.Upto,
+			Docs:  true,
+		}
+
+		page, err := listActivations(options)
+		if err != nil {
+			return 0, err
+		}
+
+		for i := range page {
+			if Flags.activation.fetchLogs && activationLogsTruncated(&page[i]) {
+				if err = fetchActivationLogs(&page[i]); err != nil {
+					return 0, err
+				}
+			}
+		}
+
+		if export.directory {
+			err = export.writeDirectoryPage(page)
+		} else {
+			err = export.writeFilePage(file, page)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		export.progress.Skip = skip + len(page)
+		export.progress.Count += len(page)
+		if err = export.saveCheckpoint(); err != nil {
+			return 0, err
+		}
+
+		return len(page), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(export.checkpoint); err != nil {
+		whisk.Debug(whisk.DbgWarn, "os.Remove(%s) error: %s\n", export.checkpoint, err)
+	}
+
+	fmt.Fprintf(color.Output, wski18n.T("{{.ok}} exported {{.count}} activations to {{.name}}\n",
+		map[string]interface{}{
+			"ok":    color.GreenString("ok:"),
+			"count": export.progress.Count,
+			"name":  boldString(export.destination),
+		}))
+
+	return nil
+}
+
+// openFile() opens the NDJSON file, dropping anything written after the last checkpoint; without a
+// checkpoint to resume from, an existing file is left alone
+func (export *activationExport) openFile() (*os.File, error) {
+	if !export.resumed {
+		if exists, err := FileExists(export.destination); err != nil {
+			return nil, err
+		} else if exists {
+			return nil, fileExistsError(export.destination)
+		}
+	}
+
+	file, err := os.OpenFile(export.destination, os.O_CREATE|os.O_WRONLY, 0644)
+	if err == nil {
+		if err = file.Truncate(export.progress.Offset); err == nil {
+			_, err = file.Seek(export.progress.Offset, io.SeekStart)
+		}
+		if err != nil {
+			file.Close()
+		}
+	}
+
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Opening %s at offset %d failed: %s\n", export.destination, export.progress.Offset, err)
+		errStr := wski18n.T("Cannot create file '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": export.destination, "err": err})
+		return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+			whisk.NO_DISPLAY_USAGE)
+	}
+
+	return file, nil
+}
+
+func (export *activationExport) writeFilePage(file *os.File, activations []whisk.Activation) error {
+	var page bytes.Buffer
+
+	encoder := json.NewEncoder(&page)
+	encoder.SetEscapeHTML(false)
+	for _, activation := range activations {
+		if err := encoder.Encode(activation); err != nil {
+			return activationExportWriteError(export.destination, err)
+		}
+	}
+
+	if _, err := file.Write(page.Bytes()); err != nil {
+		return activationExportWriteError(export.destination, err)
+	}
+	if err := file.Sync(); err != nil {
+		return activationExportWriteError(export.destination, err)
+	}
+
+	export.progress.Offset += int64(page.Len())
+	return nil
+}
+
+func (export *activationExport) writeDirectoryPage(activations []whisk.Activation) error {
+	for _, activation := range activations {
+		content, err := json.MarshalIndent(activation, "", "    ")
+		if err != nil {
+			return activationExportWriteError(export.destination, err)
+		}
+
+		if err = writeFile(filepath.Join(export.destination, activation.ActivationID+".json"), string(content)+"\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (export *activationExport) saveCheckpoint() error {
+	content, err := json.Marshal(export.progress)
+	if err != nil {
+		return activationExportWriteError(export.checkpoint, err)
+	}
+
+	return writeFile(export.checkpoint, string(content))
+}
+
+// activationLogsTruncated(activation) tells whether the logs of a listed action activation may be missing.
+// Activation stores that keep logs apart from activations, such as the ElasticSearch log store, list
+// activations without their logs, which are then only available from the logs of each activation.
+func activationLogsTruncated(activation *whisk.Activation) bool {
+	return len(activation.Logs) == 0 && activation.Annotations.GetValue("kind") != nil
+}
+
+// fetchActivationLogs(activation) replaces the logs of a listed activation with the complete logs
+func fetchActivationLogs(activation *whisk.Activation) error {
+	logs, _, err := Client.Activations.Logs(activation.ActivationID)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Activations.Logs(%s) failed: %s\n", activation.ActivationID, err)
+		errStr := wski18n.T("Unable to get logs for activation '{{.id}}': {{.err}}",
+			map[string]interface{}{"id": activation.ActivationID, "err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+			whisk.NO_DISPLAY_USAGE)
+	}
+
+	activation.Logs = logs.Logs
+	return nil
+}
+
+func activationExportWriteError(name string, err error) error {
+	whisk.Debug(whisk.DbgError, "Writing export %s failed: %s\n", name, err)
+	errStr := wski18n.T("Unable to write the export to '{{.name}}': {{.err}}",
+		map[string]interface{}{"name": name, "err": err})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+		whisk.NO_DISPLAY_USAGE)
+}
+
+var activationRerunCmd = &cobra.Command{
+	Use:           "rerun (ACTIVATION_ID | --last)",
+	Short:         wski18n.T("invoke an action again with the input of an activation and compare the results"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		var qualifiedName = new(QualifiedName)
+
+		if args, err = lastFlag(args); err != nil { // Checks if any errors occurred in lastFlag(args)
+			whisk.Debug(whisk.DbgError, "lastFlag(%#v) failed: %s\n", args, err)
+			errStr := wski18n.T("Unable to rerun activation: {{.err}}",
+				map[string]interface{}{"err": err})
+			werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return werr
+		}
+		if whiskErr := CheckArgs(args, 1, 1, "Activation rerun",
+			wski18n.T("An activation ID is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		id := args[0]
+		activation, _, err := Client.Activations.Get(id)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "Client.Activations.Get(%s) failed: %s\n", id, err)
+			errStr := wski18n.T("Unable to get activation '{{.id}}': {{.err}}",
+				map[string]interface{}{"id": id, "err": err})
+			werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return werr
+		}
+
+		actionName, parameters, source := recoverActivationInput(activation)
+		overrides := getParameters(Flags.common.param, false, false).(map[string]interface{})
+
+		if parameters == nil {
+			if len(overrides) == 0 {
+				whisk.Debug(whisk.DbgError, "No input recovered for activation %s\n", id)
+				errStr := wski18n.T("The input of activation '{{.id}}' is not available; invoke the action with --record to keep its input, or specify the parameters with --param or --param-file",
+					map[string]interface{}{"id": id})
+				return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+			}
+			parameters = make(map[string]interface{})
+			source = wski18n.T("--param overrides only")
+		}
+		for key, value := range overrides {
+			parameters[key] = value
+		}
+
+		if len(Flags.activation.action) > 0 {
+			actionName = Flags.activation.action
+		}
+		if qualifiedName, err = NewQualifiedName(actionName); err != nil {
+			return NewQualifiedNameError(actionName, err)
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("Rerunning activation {{.id}} with input from {{.source}}\n",
+			map[string]interface{}{"id": boldString(id), "source": source}))
+
+		res, resp, err := invokeActionWithResponse(*qualifiedName, parameters, true, false)
+		if Flags.action.record {
+			recordInvocation(*qualifiedName, parameters, res, resp)
+		}
+		if err != nil && !isApplicationError(err) {
+			if isBlockingTimeout(err) {
+				printBlockingTimeoutMsg(qualifiedName.GetNamespace(), qualifiedName.GetEntityName(),
+					getValueFromResponse(ACTIVATION_ID, res))
+				return err
+			}
+			return handleInvocationError(err, qualifiedName.GetEntityName())
+		}
+
+		printInvocationMsg(*qualifiedName, false, true, res, color.Output)
+
+		var result interface{}
+		if response, ok := getValueFromResponse("response", res).(map[string]interface{}); ok {
+			result = response["result"]
+		}
+		printJSON(result)
+
+		if diff := diffJSON(activation.Response.Result, result); len(diff) == 0 {
+			fmt.Fprintf(color.Output, wski18n.T("The result is identical to the result of activation {{.id}}\n",
+				map[string]interface{}{"id": boldString(id)}))
+		} else {
+			fmt.Fprintf(color.Output, wski18n.T("Differences from the result of activation {{.id}}:\n",
+				map[string]interface{}{"id": boldString(id)}))
+			for _, line := range diff {
+				if strings.HasPrefix(line, "-") {
+					fmt.Fprintln(color.Output, color.RedString(line))
+				} else {
+					fmt.Fprintln(color.Output, color.GreenString(line))
+				}
+			}
+		}
+
+		return err
+	},
+}
+
+// recoverActivationInput(activation) returns the action of an activation together with the
+// parameters it was invoked with, when they can be recovered, and a description of where they came
+// from. Activations do not record their input, so the local invocation log is consulted first;
+// for an action fired by a rule, the payload of the causing trigger activation is used instead.
+func recoverActivationInput(activation *whisk.Activation) (string, map[string]interface{}, string) {
+	actionName := "/" + activation.Namespace + "/" + activation.Name
+	if path, ok := activation.Annotations.GetValue("path").(string); ok && len(path) > 0 {
+		actionName = "/" + path
+	}
+
+	if record := findInvocation(activation.ActivationID); record != nil {
+		if parameters, ok := record.Parameters.(map[string]interface{}); ok {
+			return record.Action, parameters, wski18n.T("the local invocation log")
+		}
+	}
+
+	if len(activation.Cause) > 0 {
+		cause, _, err := Client.Activations.Get(activation.Cause)
+		if err != nil {
+			whisk.Debug(whisk.DbgWarn, "Client.Activations.Get(%s) failed: %s\n", activation.Cause, err)
+		} else if cause.Annotations.GetValue("kind") == nil && cause.Response.Result != nil {
+			// Trigger activations have no kind; their result is the payload passed to the rule's action
+			if parameters, ok := normalizeJSON(*cause.Response.Result).(map[string]interface{}); ok {
+				return actionName, parameters, wski18n.T("trigger activation {{.id}}",
+					map[string]interface{}{"id": activation.Cause})
+			}
+		}
+	}
+
+	return actionName, nil, ""
+}
+
 // lastFlag(args) retrieves the last activation with flag -l or --last
 // Param: Brings in []strings from args
 // Return: Returns a []string with the latest ID or the original args and any errors
@@ -372,7 +896,7 @@ var activationPollCmd = &cobra.Command{
 				localDuration := time.Since(localStartTime)
 				if int(localDuration.Seconds()) > Flags.activation.exit {
 					whisk.Debug(whisk.DbgInfo, "Poll time (%d seconds) expired; polling loop stopped\n", Flags.activation.exit)
-					return nil
+					break
 				}
 			}
 			whisk.Verbose("Polling for activations since %s\n", time.Unix(pollSince/1000, 0))
@@ -454,15 +978,32 @@ func init() {
 	activationListCmd.Flags().BoolVarP(&Flags.common.full, "full", "f", false, wski18n.T("include full activation description"))
 	activationListCmd.Flags().Int64Var(&Flags.activation.upto, "upto", 0, wski18n.T("return activations with timestamps earlier than `UPTO`; measured in milliseconds since Th, 01, Jan 1970"))
 	activationListCmd.Flags().Int64Var(&Flags.activation.since, "since", 0, wski18n.T("return activations with timestamps later than `SINCE`; measured in milliseconds since Th, 01, Jan 1970"))
+	activationListCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("page through all activations in the collection instead of a single LIMIT block"))
+	activationListCmd.Flags().StringArrayVar(&Flags.common.filter, "filter", []string{}, wski18n.T("only list activations matching the FIELD OPERATOR VALUE `EXPRESSION`, e.g. 'status!=success'; may be repeated"))
+	activationListCmd.Flags().StringVar(&Flags.common.sortBy, "sort-by", "", wski18n.T("sort the list by `FIELD`, ascending or, with FIELD:desc, descending"))
 
 	activationGetCmd.Flags().BoolVarP(&Flags.common.summary, "summary", "s", false, wski18n.T("summarize activation details"))
 	activationGetCmd.Flags().BoolVarP(&Flags.activation.last, "last", "l", false, wski18n.T("retrieves the last activation"))
 	activationGetCmd.Flags().BoolVarP(&Flags.activation.logs, "logs", "g", false, wski18n.T("emit only the logs, stripped of time stamps and stream identifier"))
+	activationGetCmd.Flags().StringVar(&Flags.common.template, "template", "", wski18n.T("format the activation with the Go `TEMPLATE`, e.g. {{.example}}",
+		map[string]interface{}{"example": "'{{.activationId}} {{.response.status}}'"}))
 
 	activationLogsCmd.Flags().BoolVarP(&Flags.activation.last, "last", "l", false, wski18n.T("retrieves the last activation"))
 	activationLogsCmd.Flags().BoolVarP(&Flags.activation.strip, "strip", "r", false, wski18n.T("strip timestamp and stream information"))
 	activationResultCmd.Flags().BoolVarP(&Flags.activation.last, "last", "l", false, wski18n.T("retrieves the last activation"))
 
+	activationExportCmd.Flags().StringVar(&Flags.activation.to, "to", "", wski18n.T("write the activations to `DESTINATION`, either an NDJSON file or a directory for one JSON file per activation"))
+	activationExportCmd.Flags().StringVar(&Flags.activation.action, "name", "", wski18n.T("only export activations of the action or trigger `NAME`"))
+	activationExportCmd.Flags().Int64Var(&Flags.activation.upto, "upto", 0, wski18n.T("export activations with timestamps earlier than `UPTO`; measured in milliseconds since Th, 01, Jan 1970"))
+	activationExportCmd.Flags().Int64Var(&Flags.activation.since, "since", 0, wski18n.T("export activations with timestamps later than `SINCE`; measured in milliseconds since Th, 01, Jan 1970"))
+	activationExportCmd.Flags().BoolVar(&Flags.activation.fetchLogs, "fetch-logs", false, wski18n.T("retrieve the logs of action activations that are listed without them"))
+
+	activationRerunCmd.Flags().BoolVarP(&Flags.activation.last, "last", "l", false, wski18n.T("reruns the last activation"))
+	activationRerunCmd.Flags().StringVar(&Flags.activation.action, "action", "", wski18n.T("invoke the action `ACTION_NAME` instead of the action of the activation"))
+	activationRerunCmd.Flags().StringSliceVarP(&Flags.common.param, "param", "p", []string{}, wski18n.T("parameter values in `KEY VALUE` format that override the recovered input"))
+	activationRerunCmd.Flags().StringVarP(&Flags.common.paramFile, "param-file", "P", "", wski18n.T("`FILE` containing parameter values in JSON format that override the recovered input"))
+	activationRerunCmd.Flags().BoolVar(&Flags.action.record, "record", false, wski18n.T("record the parameters in the local invocation log so that 'wsk activation rerun' can reuse them"))
+
 	activationPollCmd.Flags().IntVarP(&Flags.activation.exit, "exit", "e", 0, wski18n.T("stop polling after `SECONDS` seconds"))
 	activationPollCmd.Flags().IntVar(&Flags.activation.sinceSeconds, "since-seconds", 0, wski18n.T("start polling for activations `SECONDS` seconds ago"))
 	activationPollCmd.Flags().IntVar(&Flags.activation.sinceMinutes, "since-minutes", 0, wski18n.T("start polling for activations `MINUTES` minutes ago"))
@@ -475,5 +1016,7 @@ func init() {
 		activationLogsCmd,
 		activationResultCmd,
 		activationPollCmd,
+		activationExportCmd,
+		activationRerunCmd,
 	)
 }
@@ -0,0 +1,224 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// activationStore is a stand-in controller that lists activations newest first and serves their logs
+type activationStore struct {
+	activations []map[string]interface{}
+	requests    []string // the query of every list request
+	logRequests []string // the activation IDs whose logs were requested
+	failSkip    int      // a skip whose list request fails once, when not 0
+}
+
+// newActivationStore holds count activations started at 1..count, newest first; every third one is listed
+// without its logs
+func newActivationStore(t *testing.T, count int) *activationStore {
+	store := &activationStore{}
+	for start := count; start > 0; start-- {
+		activation := map[string]interface{}{
+			"namespace":    "guest",
+			"name":         "hello",
+			"activationId": fmt.Sprintf("id-%03d", start),
+			"start":        start,
+			"annotations":  []interface{}{map[string]interface{}{"key": "kind", "value": "nodejs:14"}},
+			"response":     map[string]interface{}{"status": "success", "result": map[string]interface{}{"n": start}},
+		}
+		if start%3 != 0 {
+			activation["logs"] = []interface{}{fmt.Sprintf("log %d", start)}
+		}
+		store.activations = append(store.activations, activation)
+	}
+	newTestClient(t, store.serve)
+
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+	return store
+}
+
+func (store *activationStore) serve(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/logs") {
+		id := path.Base(path.Dir(r.URL.Path))
+		store.logRequests = append(store.logRequests, id)
+		json.NewEncoder(w).Encode(map[string]interface{}{"logs": []string{"full log of " + id}})
+		return
+	}
+
+	query := r.URL.Query()
+	store.requests = append(store.requests, r.URL.RawQuery)
+	skip, _ := strconv.Atoi(query.Get("skip"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	since, _ := strconv.Atoi(query.Get("since"))
+	upto, _ := strconv.Atoi(query.Get("upto"))
+	if store.failSkip != 0 && skip == store.failSkip {
+		store.failSkip = 0
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	var page []map[string]interface{}
+	for _, activation := range store.activations {
+		start := activation["start"].(int)
+		if (since == 0 || start >= since) && (upto == 0 || start <= upto) {
+			page = append(page, activation)
+		}
+	}
+	page = page[min(skip, len(page)):min(skip+limit, len(page))]
+	json.NewEncoder(w).Encode(page)
+}
+
+func readActivationExport(t *testing.T, name string) []map[string]interface{} {
+	content, err := ioutil.ReadFile(name)
+	assert.Nil(t, err)
+
+	var activations []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		activation := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal([]byte(line), &activation))
+		activations = append(activations, activation)
+	}
+	return activations
+}
+
+func TestActivationExportResume(t *testing.T) {
+	store := newActivationStore(t, 450)
+	destination := filepath.Join(t.TempDir(), "activations.ndjson")
+	Flags.activation.since = 11
+	Flags.activation.upto = 420
+
+	// The second page fails, which leaves the checkpoint after the first one
+	store.failSkip = MAX_LIST_LIMIT
+	export, err := newActivationExport(destination)
+	assert.Nil(t, err)
+	assert.NotNil(t, export.run())
+
+	exists, err := FileExists(export.checkpoint)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.Len(t, readActivationExport(t, destination), MAX_LIST_LIMIT)
+
+	// A later run is a new process, which starts from the namespace of the properties again
+	Client.Namespace = "guest"
+	export, err = newActivationExport(destination)
+	assert.Nil(t, err)
+	assert.Equal(t, MAX_LIST_LIMIT, export.progress.Skip)
+	assert.Nil(t, export.run())
+
+	activations := readActivationExport(t, destination)
+	assert.Len(t, activations, 410)
+	assert.Equal(t, "id-420", activations[0]["activationId"])
+	assert.Equal(t, "id-221", activations[199]["activationId"])
+	assert.Equal(t, "id-220", activations[200]["activationId"])
+	assert.Equal(t, "id-011", activations[409]["activationId"])
+	for _, query := range store.requests {
+		assert.Contains(t, query, "since=11")
+		assert.Contains(t, query, "upto=420")
+	}
+
+	exists, err = FileExists(export.checkpoint)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	// A checkpoint is not picked up by a different export
+	assert.Nil(t, export.saveCheckpoint())
+	Client.Namespace = "guest"
+	Flags.activation.action = "other"
+	_, err = newActivationExport(destination)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "belongs to a different export")
+}
+
+func TestActivationExportCutoff(t *testing.T) {
+	store := newActivationStore(t, 5)
+	destination := filepath.Join(t.TempDir(), "activations.ndjson")
+
+	// Without --upto, the end of the export is fixed when it starts
+	export, err := newActivationExport(destination)
+	assert.Nil(t, err)
+	assert.NotZero(t, export.progress.Upto)
+	export.progress.Upto = 3
+	assert.Nil(t, export.run())
+
+	activations := readActivationExport(t, destination)
+	assert.Len(t, activations, 3)
+	assert.Equal(t, "id-003", activations[0]["activationId"])
+	assert.Equal(t, []string{"docs=true&limit=200&skip=0&upto=3"}, store.requests)
+}
+
+func TestActivationExportExistingFile(t *testing.T) {
+	store := newActivationStore(t, 3)
+	destination := filepath.Join(t.TempDir(), "activations.ndjson")
+	assert.Nil(t, ioutil.WriteFile(destination, []byte("{}\n"), 0644))
+
+	// Without a checkpoint, the file is not an interrupted export of this one
+	export, err := newActivationExport(destination)
+	assert.Nil(t, err)
+	err = export.run()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+	assert.Empty(t, store.requests)
+
+	content, err := ioutil.ReadFile(destination)
+	assert.Nil(t, err)
+	assert.Equal(t, "{}\n", string(content))
+}
+
+func TestActivationExportDirectory(t *testing.T) {
+	store := newActivationStore(t, 6)
+	destination := t.TempDir()
+	Flags.activation.fetchLogs = true
+
+	export, err := newActivationExport(destination)
+	assert.Nil(t, err)
+	assert.True(t, export.directory)
+	assert.Nil(t, export.run())
+
+	// Only the activations listed without their logs have them fetched
+	assert.Equal(t, []string{"id-006", "id-003"}, store.logRequests)
+
+	files, err := filepath.Glob(filepath.Join(destination, "*"))
+	assert.Nil(t, err)
+	assert.Len(t, files, 6)
+
+	activation := make(map[string]interface{})
+	content, err := ioutil.ReadFile(filepath.Join(destination, "id-003.json"))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(content, &activation))
+	assert.Equal(t, []interface{}{"full log of id-003"}, activation["logs"])
+
+	content, err = ioutil.ReadFile(filepath.Join(destination, "id-005.json"))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(content, &activation))
+	assert.Equal(t, []interface{}{"log 5"}, activation["logs"])
+	assert.Equal(t, map[string]interface{}{"n": 5.0}, activation["response"].(map[string]interface{})["result"])
+}
@@ -0,0 +1,122 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+// newInvocationTestLog points the local invocation log to a temporary file for the test
+func newInvocationTestLog(t *testing.T) string {
+	logPath := filepath.Join(t.TempDir(), "invocations")
+	savedLog, envExists := os.LookupEnv("WSK_INVOCATION_LOG")
+	os.Setenv("WSK_INVOCATION_LOG", logPath)
+	t.Cleanup(func() {
+		if envExists {
+			os.Setenv("WSK_INVOCATION_LOG", savedLog)
+		} else {
+			os.Unsetenv("WSK_INVOCATION_LOG")
+		}
+	})
+	return logPath
+}
+
+func TestRecoverActivationInputFromInvocationLog(t *testing.T) {
+	logPath := newInvocationTestLog(t)
+	qualifiedName, _ := NewQualifiedName("/guest/hello")
+
+	recordInvocation(*qualifiedName, map[string]interface{}{"name": "first"}, map[string]interface{}{"activationId": "id-1"}, nil)
+	recordInvocation(*qualifiedName, map[string]interface{}{"name": "second"}, map[string]interface{}{"activationId": "id-2"}, nil)
+
+	// Every invocation is appended as one line
+	content, err := ioutil.ReadFile(logPath)
+	assert.Nil(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 2)
+
+	activation := &whisk.Activation{Namespace: "guest", Name: "other", ActivationID: "id-1"}
+	actionName, parameters, source := recoverActivationInput(activation)
+	assert.Equal(t, "/guest/hello", actionName)
+	assert.Equal(t, map[string]interface{}{"name": "first"}, parameters)
+	assert.Equal(t, "the local invocation log", source)
+
+	// An activation missing from the log and without a cause has no input to recover
+	activation.ActivationID = "id-3"
+	actionName, parameters, _ = recoverActivationInput(activation)
+	assert.Equal(t, "/guest/other", actionName)
+	assert.Nil(t, parameters)
+}
+
+func TestRecoverActivationInputFromTrigger(t *testing.T) {
+	newInvocationTestLog(t)
+	causes := map[string]map[string]interface{}{
+		"trigger-1": {
+			"namespace":    "guest",
+			"name":         "ticks",
+			"activationId": "trigger-1",
+			"response":     map[string]interface{}{"result": map[string]interface{}{"name": "tick"}},
+		},
+		"sequence-1": {
+			"namespace":    "guest",
+			"name":         "pipeline",
+			"activationId": "sequence-1",
+			"annotations":  []interface{}{map[string]interface{}{"key": "kind", "value": "sequence"}},
+			"response":     map[string]interface{}{"result": map[string]interface{}{"name": "step"}},
+		},
+	}
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cause, ok := causes[path.Base(r.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(cause)
+	})
+
+	activation := &whisk.Activation{
+		Namespace:    "guest",
+		Name:         "hello",
+		ActivationID: "id-1",
+		Cause:        "trigger-1",
+		Annotations:  whisk.KeyValueArr{{Key: "path", Value: "guest/demo/hello"}},
+	}
+	actionName, parameters, source := recoverActivationInput(activation)
+	assert.Equal(t, "/guest/demo/hello", actionName)
+	assert.Equal(t, map[string]interface{}{"name": "tick"}, parameters)
+	assert.Equal(t, "trigger activation trigger-1", source)
+
+	// Only a trigger activation's result is the input of the action it caused
+	activation.Cause = "sequence-1"
+	_, parameters, _ = recoverActivationInput(activation)
+	assert.Nil(t, parameters)
+
+	activation.Cause = "missing"
+	_, parameters, _ = recoverActivationInput(activation)
+	assert.Nil(t, parameters)
+}
@@ -20,11 +20,19 @@ package commands
 import (
 	"bufio"
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/openwhisk-cli/wski18n"
 	"github.com/apache/openwhisk-client-go/whisk"
@@ -190,7 +198,8 @@ var apiCreateCmd = &cobra.Command{
 				whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
 			return whiskErr
 		} else if len(args) == 0 && Flags.api.configfile != "" {
-			api, err = parseSwaggerApi(Flags.api.configfile, Client.Config.Namespace)
+			var config *apiConfig
+			api, config, err = parseSwaggerApi(Flags.api.configfile, Client.Config.Namespace)
 			if err != nil {
 				whisk.Debug(whisk.DbgError, "parseSwaggerApi() error: %s\n", err)
 				errMsg := wski18n.T("Unable to parse swagger file: {{.err}}", map[string]interface{}{"err": err})
@@ -198,6 +207,16 @@ var apiCreateCmd = &cobra.Command{
 					whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
 				return whiskErr
 			}
+
+			// Confirm that the actions referenced by an OpenAPI 3 configuration are web-actions; swagger 2.0
+			// files are left to the API gateway as they always were
+			if config.isOpenApi() {
+				config.validateActions(Client, Client.Config.Namespace)
+				if err = config.err(); err != nil {
+					whisk.Debug(whisk.DbgError, "API configuration action validation error: %s\n", err)
+					return err
+				}
+			}
 		} else {
 			if whiskErr := CheckArgs(args, 3, 4, "Api create",
 				wski18n.T("Specify a swagger file or specify an API base path with an API path, an API verb, and an action name.")); whiskErr != nil {
@@ -409,6 +428,133 @@ var apiGetCmd = &cobra.Command{
 	},
 }
 
+var apiExportCmd = &cobra.Command{
+	Use:           "export (BASE_PATH | API_NAME | --all)",
+	Short:         wski18n.T("export APIs as OpenAPI documents that can be used with api create --config-file"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		var apis []map[string]interface{}
+
+		if Flags.common.all {
+			if whiskErr := CheckArgs(args, 0, 0, "Api export",
+				wski18n.T("An API base path or API name cannot be specified with --all.")); whiskErr != nil {
+				return whiskErr
+			}
+		} else if whiskErr := CheckArgs(args, 1, 1, "Api export",
+			wski18n.T("An API base path or API name is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		format := apiExportFormatOpenApi3
+		if cmd.LocalFlags().Changed("format") {
+			format = strings.ToLower(Flags.common.format)
+		}
+		if format != apiExportFormatOpenApi2 && format != apiExportFormatOpenApi3 {
+			errMsg := wski18n.T("Invalid format type: {{.type}}", map[string]interface{}{"type": Flags.common.format})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		apiOptions := new(whisk.ApiOptions)
+		if apiOptions.SpaceGuid, err = getUserContextId(); err != nil {
+			return err
+		}
+		if apiOptions.AccessToken, err = getAccessToken(); err != nil {
+			return err
+		}
+
+		// The documents are read from the raw responses, as the typed swagger of the client drops the
+		// definitions and most operation fields
+		if Flags.common.all {
+			err = listAllPages(0, func(skip int, limit int) (int, error) {
+				apiListReqOptions := new(whisk.ApiListRequestOptions)
+				apiListReqOptions.ApiOptions = *apiOptions
+				apiListReqOptions.Limit = limit
+				apiListReqOptions.Skip = skip
+
+				retApiList, resp, err := Client.Apis.List(apiListReqOptions)
+				if err != nil {
+					whisk.Debug(whisk.DbgError, "Client.Apis.List(%#v) error: %s\n", apiListReqOptions, err)
+					return 0, err
+				}
+				documents, err := readApiDocuments(resp)
+				if err != nil {
+					return 0, err
+				}
+				apis = append(apis, documents...)
+				return len(retApiList.Apis), nil
+			})
+			if err != nil {
+				errMsg := wski18n.T("Unable to obtain the API list: {{.err}}", map[string]interface{}{"err": err})
+				whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+					whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+				return whiskErr
+			}
+		} else {
+			apiGetReq := new(whisk.ApiGetRequest)
+			apiGetReqOptions := (*whisk.ApiGetRequestOptions)(apiOptions)
+			apiGetReqOptions.ApiBasePath = args[0]
+
+			_, resp, err := Client.Apis.Get(apiGetReq, apiGetReqOptions)
+			if err == nil {
+				apis, err = readApiDocuments(resp)
+			}
+			if err != nil {
+				whisk.Debug(whisk.DbgError, "Client.Apis.Get(%#v, %#v) error: %s\n", apiGetReq, apiGetReqOptions, err)
+				errMsg := wski18n.T("Unable to get API '{{.name}}': {{.err}}", map[string]interface{}{"name": args[0], "err": err})
+				whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+					whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+				return whiskErr
+			}
+		}
+
+		sort.SliceStable(apis, func(i, j int) bool {
+			return fmt.Sprint(apis[i]["basePath"]) < fmt.Sprint(apis[j]["basePath"])
+		})
+
+		if len(apis) == 0 && !Flags.common.all {
+			errMsg := wski18n.T("API does not exist for basepath {{.basepath}}", map[string]interface{}{"basepath": args[0]})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		for i, api := range apis {
+			document := exportApiDocument(api, format)
+
+			if !Flags.api.yaml {
+				printJSON(document)
+				continue
+			}
+
+			// Multiple APIs are written as a YAML stream of documents
+			jsonbytes, err := json.Marshal(document)
+			if err == nil {
+				var yamlbytes []byte
+				if yamlbytes, err = yaml.JSONToYAML(jsonbytes); err == nil {
+					if i > 0 {
+						fmt.Println("---")
+					}
+					fmt.Print(string(yamlbytes))
+				}
+			}
+			if err != nil {
+				whisk.Debug(whisk.DbgError, "yaml.JSONToYAML() error: %s\n", err)
+				errMsg := wski18n.T("Unable to convert API into YAML: {{.err}}", map[string]interface{}{"err": err})
+				whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+					whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+				return whiskErr
+			}
+		}
+
+		return nil
+	},
+}
+
 var apiDeleteCmd = &cobra.Command{
 	Use:           "delete BASE_PATH | API_NAME [API_PATH [API_VERB]]",
 	Short:         wski18n.T("delete an API"),
@@ -625,6 +771,382 @@ var apiListCmd = &cobra.Command{
 	},
 }
 
+var apiTestCmd = &cobra.Command{
+	Use:           "test BASE_PATH | API_NAME [API_PATH [API_VERB]]",
+	Short:         wski18n.T("send test requests to the routes of an API"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		var apiPath string
+		var apiVerb string
+		var samples map[string]*apiTestSample
+
+		if whiskErr := CheckArgs(args, 1, 3, "Api test",
+			wski18n.T("An API base path or API name is required.  An optional API relative path and operation may also be provided.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		apiGetReq := new(whisk.ApiGetRequest)
+		apiGetReq.Namespace = Client.Config.Namespace
+		apiGetReqOptions := new(whisk.ApiGetRequestOptions)
+		if apiGetReqOptions.SpaceGuid, err = getUserContextId(); err != nil {
+			return err
+		}
+		if apiGetReqOptions.AccessToken, err = getAccessToken(); err != nil {
+			return err
+		}
+
+		apiGetReqOptions.ApiBasePath = args[0]
+		if len(args) > 1 {
+			if whiskErr, ok := isValidRelpath(args[1]); !ok {
+				return whiskErr
+			}
+			apiPath = args[1]
+			apiGetReqOptions.ApiRelPath = apiPath
+		}
+		if len(args) > 2 {
+			if whiskErr, ok := IsValidApiVerb(args[2]); !ok {
+				return whiskErr
+			}
+			apiVerb = strings.ToUpper(args[2])
+			apiGetReqOptions.ApiVerb = apiVerb
+		}
+
+		if len(Flags.api.samples) > 0 {
+			if samples, err = loadApiTestSamples(Flags.api.samples); err != nil {
+				return err
+			}
+		}
+
+		retApi, _, err := Client.Apis.Get(apiGetReq, apiGetReqOptions)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "Client.Apis.Get(%#v, %#v) error: %s\n", apiGetReq, apiGetReqOptions, err)
+			errMsg := wski18n.T("Unable to get API '{{.name}}': {{.err}}", map[string]interface{}{"name": args[0], "err": err})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		var routes []whisk.ApiFilteredList
+		for _, api := range retApi.Apis {
+			if api.ApiValue == nil || api.ApiValue.Swagger == nil {
+				continue
+			}
+			resultApi := *api.ApiValue
+			if len(Flags.api.baseUrl) > 0 {
+				resultApi.BaseUrl = Flags.api.baseUrl
+			}
+			routes = append(routes, genFilteredList(&resultApi, apiPath, apiVerb)...)
+		}
+		sort.SliceStable(routes, func(i, j int) bool {
+			if routes[i].RelPath != routes[j].RelPath {
+				return routes[i].RelPath < routes[j].RelPath
+			}
+			return routes[i].Verb < routes[j].Verb
+		})
+
+		if len(routes) == 0 {
+			errMsg := wski18n.T("No API routes match {{.api}}", map[string]interface{}{"api": strings.Join(args, " ")})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		httpClient := newApiTestHttpClient()
+		failed := 0
+		for _, route := range routes {
+			result := runApiTest(httpClient, route, apiTestSampleFor(samples, route.Verb, route.RelPath))
+			printApiTestResult(result)
+			if !result.passed() {
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			errMsg := wski18n.T("{{.failed}} of {{.total}} API routes failed",
+				map[string]interface{}{"failed": failed, "total": len(routes)})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} {{.total}} API routes passed\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "total": len(routes)}))
+
+		return nil
+	},
+}
+
+var apiServeCmd = &cobra.Command{
+	Use:           "serve --config-file CFG_FILE",
+	Short:         wski18n.T("serve an API locally, forwarding its routes to web actions"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 0, 0, "Api serve",
+			wski18n.T("No arguments are required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		api, _, err := parseSwaggerApi(Flags.api.configfile, Client.Config.Namespace)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "parseSwaggerApi() error: %s\n", err)
+			errMsg := wski18n.T("Unable to parse swagger file: {{.err}}", map[string]interface{}{"err": err})
+			whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		swagger := new(whisk.ApiSwagger)
+		if err = json.Unmarshal([]byte(api.Swagger), swagger); err != nil {
+			whisk.Debug(whisk.DbgError, "JSON parse of '%s' error: %s\n", Flags.api.configfile, err)
+			errMsg := wski18n.T("Error parsing swagger file '{{.name}}': {{.err}}",
+				map[string]interface{}{"name": Flags.api.configfile, "err": err})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		emulator, err := newApiEmulator(swagger, Client.Config.Namespace, Client.Config.Host,
+			strings.ToLower(Flags.api.resptype), Flags.api.runtime)
+		if err != nil {
+			return err
+		}
+
+		address := fmt.Sprintf("localhost:%d", Flags.api.port)
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "net.Listen(%s) error: %s\n", address, err)
+			errMsg := wski18n.T("Unable to listen on {{.address}}: {{.err}}", map[string]interface{}{"address": address, "err": err})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} serving API {{.name}} at {{.url}}\n",
+			map[string]interface{}{
+				"ok":   color.GreenString("ok:"),
+				"name": boldString(swagger.Info.Title),
+				"url":  "http://" + listener.Addr().String() + emulator.basePath,
+			}))
+		for _, route := range emulator.routes {
+			target := route.action
+			if runtime, ok := emulator.runtimeFor(route.action); ok && len(route.action) > 0 {
+				target = wski18n.T("{{.action}} on {{.runtime}}", map[string]interface{}{"action": route.action, "runtime": runtime})
+			}
+			fmt.Fprintf(color.Output, "  %-7s %s  %s\n", route.verb, emulator.basePath+route.path, target)
+		}
+
+		if err = http.Serve(listener, emulator); err != nil {
+			whisk.Debug(whisk.DbgError, "http.Serve() error: %s\n", err)
+			errMsg := wski18n.T("Unable to serve API: {{.err}}", map[string]interface{}{"err": err})
+			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			return whiskErr
+		}
+
+		return nil
+	},
+}
+
+// Time allowed for each test request to complete
+const API_TEST_TIMEOUT = 30 * time.Second
+
+// Length of the response body excerpt shown for each tested route
+const API_TEST_SNIPPET_LENGTH = 80
+
+// apiTestSample holds the request data used when testing a route. Samples are keyed by
+// "VERB API_PATH", by "API_PATH" for every verb, or by "*" for every route.
+type apiTestSample struct {
+	Path    map[string]interface{} `json:"path,omitempty"`
+	Query   map[string]interface{} `json:"query,omitempty"`
+	Headers map[string]interface{} `json:"headers,omitempty"`
+	Body    interface{}            `json:"body,omitempty"`
+	Status  int                    `json:"status,omitempty"`
+}
+
+type apiTestResult struct {
+	route   whisk.ApiFilteredList
+	url     string
+	status  int
+	expect  int
+	latency time.Duration
+	snippet string
+	err     error
+}
+
+// passed() is true when the route responded with the expected status, or with any non-error
+// status when no status is expected
+func (result apiTestResult) passed() bool {
+	if result.err != nil {
+		return false
+	}
+	if result.expect > 0 {
+		return result.status == result.expect
+	}
+	return result.status < http.StatusBadRequest
+}
+
+// loadApiTestSamples(file) reads the JSON or YAML samples file given with --samples
+func loadApiTestSamples(file string) (map[string]*apiTestSample, error) {
+	content, err := ReadFile(file)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "ReadFile(%s) error: %s\n", file, err)
+		return nil, err
+	}
+
+	samples := make(map[string]*apiTestSample)
+	err = yaml.Unmarshal([]byte(content), &samples)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "yaml.Unmarshal(%s) error: %s\n", file, err)
+		errMsg := wski18n.T("Unable to parse samples file '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": file, "err": err})
+		whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+			whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		return nil, whiskErr
+	}
+
+	return samples, nil
+}
+
+// apiTestSampleFor(samples, verb, path) merges the "*", "API_PATH" and "VERB API_PATH" samples, with
+// the more specific samples taking precedence
+func apiTestSampleFor(samples map[string]*apiTestSample, verb string, path string) apiTestSample {
+	merged := apiTestSample{
+		Path:    make(map[string]interface{}),
+		Query:   make(map[string]interface{}),
+		Headers: make(map[string]interface{}),
+	}
+
+	for _, key := range []string{"*", path, strings.ToUpper(verb) + " " + path} {
+		sample, ok := samples[key]
+		if !ok || sample == nil {
+			continue
+		}
+		for name, value := range sample.Path {
+			merged.Path[name] = value
+		}
+		for name, value := range sample.Query {
+			merged.Query[name] = value
+		}
+		for name, value := range sample.Headers {
+			merged.Headers[name] = value
+		}
+		if sample.Body != nil {
+			merged.Body = sample.Body
+		}
+		if sample.Status > 0 {
+			merged.Status = sample.Status
+		}
+	}
+
+	return merged
+}
+
+func newApiTestHttpClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: Flags.Global.Insecure}
+	return &http.Client{Transport: transport, Timeout: API_TEST_TIMEOUT}
+}
+
+// runApiTest(httpClient, route, sample) sends one request to the managed URL of route
+func runApiTest(httpClient *http.Client, route whisk.ApiFilteredList, sample apiTestSample) apiTestResult {
+	result := apiTestResult{route: route, url: route.Url, expect: sample.Status}
+
+	pathParams, _ := getPathParameterNames(route.RelPath)
+	for _, name := range pathParams {
+		value, ok := sample.Path[name]
+		if !ok {
+			result.err = errors.New(wski18n.T("no sample value for path parameter '{{.name}}'",
+				map[string]interface{}{"name": name}))
+			return result
+		}
+		result.url = strings.Replace(result.url, "{"+name+"}", url.PathEscape(fmt.Sprint(value)), -1)
+	}
+
+	if len(sample.Query) > 0 {
+		query := url.Values{}
+		for name, value := range sample.Query {
+			query.Set(name, fmt.Sprint(value))
+		}
+		result.url += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	contentType := ""
+	switch sampleBody := sample.Body.(type) {
+	case nil:
+	case string:
+		body = strings.NewReader(sampleBody)
+	default:
+		content, err := json.Marshal(sampleBody)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		body = bytes.NewReader(content)
+		contentType = "application/json"
+	}
+
+	request, err := http.NewRequest(strings.ToUpper(route.Verb), result.url, body)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if len(contentType) > 0 {
+		request.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range sample.Headers {
+		request.Header.Set(name, fmt.Sprint(value))
+	}
+
+	whisk.Debug(whisk.DbgInfo, "Testing API route %s %s\n", request.Method, result.url)
+	start := time.Now()
+	response, err := httpClient.Do(request)
+	if err != nil {
+		result.latency = time.Since(start)
+		result.err = err
+		return result
+	}
+	defer response.Body.Close()
+
+	content, err := ioutil.ReadAll(io.LimitReader(response.Body, 4*API_TEST_SNIPPET_LENGTH))
+	result.latency = time.Since(start)
+	result.status = response.StatusCode
+	result.err = err
+	result.snippet = truncateSnippet(strings.Join(strings.Fields(string(content)), " "), API_TEST_SNIPPET_LENGTH)
+
+	return result
+}
+
+func printApiTestResult(result apiTestResult) {
+	outcome := color.GreenString("ok:")
+	if !result.passed() {
+		outcome = color.RedString("error:")
+	}
+
+	latency := result.latency.Round(time.Millisecond)
+	if result.err != nil {
+		fmt.Fprintf(color.Output, "%s %s %s %s\n    %s\n", outcome, strings.ToUpper(result.route.Verb), result.url,
+			latency, result.err)
+		return
+	}
+
+	statusCode := strconv.Itoa(result.status)
+	if result.expect > 0 && result.status != result.expect {
+		statusCode = wski18n.T("{{.status}} (expected {{.expect}})",
+			map[string]interface{}{"status": result.status, "expect": result.expect})
+	}
+	fmt.Fprintf(color.Output, "%s %s %s %s %s\n", outcome, strings.ToUpper(result.route.Verb), result.url,
+		statusCode, latency)
+	if len(result.snippet) > 0 {
+		fmt.Fprintf(color.Output, "    %s\n", result.snippet)
+	}
+}
+
 // genFilteredList(resultApi, api) generates an array of
 //      ApiFilteredLists for the purpose of ordering and printing in a list form.
 //      NOTE: genFilteredRow() generates entries with one line per configuration
@@ -939,14 +1461,14 @@ func parseApi(cmd *cobra.Command, args []string) (*whisk.Api, *QualifiedName, er
 	return api, qName, err
 }
 
-func parseSwaggerApi(configfile string, namespace string) (*whisk.Api, error) {
+func parseSwaggerApi(configfile string, namespace string) (*whisk.Api, *apiConfig, error) {
 	// Test is for completeness, but this situation should only arise due to an internal error
 	if len(configfile) == 0 {
 		whisk.Debug(whisk.DbgError, "No swagger file is specified\n")
 		errMsg := wski18n.T("A configuration file was not specified.")
 		whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
 			whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-		return nil, whiskErr
+		return nil, nil, whiskErr
 	}
 
 	swagger, err := ReadFile(configfile)
@@ -956,8 +1478,9 @@ func parseSwaggerApi(configfile string, namespace string) (*whisk.Api, error) {
 			map[string]interface{}{"name": configfile, "err": err})
 		whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXIT_CODE_ERR_GENERAL,
 			whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-		return nil, whiskErr
+		return nil, nil, whiskErr
 	}
+	source := []byte(swagger)
 
 	// Check if this swagger is in JSON or YAML format
 	isYaml := strings.HasSuffix(configfile, yamlFileExtension) || strings.HasSuffix(configfile, ymlFileExtension)
@@ -969,11 +1492,36 @@ func parseSwaggerApi(configfile string, namespace string) (*whisk.Api, error) {
 			errMsg := wski18n.T("Unable to parse YAML configuration file: {{.err}}", map[string]interface{}{"err": err})
 			whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
 				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-			return nil, whiskErr
+			return nil, nil, whiskErr
 		}
 		swagger = string(jsonbytes)
 	}
 
+	// Parse the JSON into a generic document, converting OpenAPI 3 documents into swagger 2.0
+	var document map[string]interface{}
+	err = json.Unmarshal([]byte(swagger), &document)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "JSON parse of '%s' error: %s\n", configfile, err)
+		errMsg := wski18n.T("Error parsing swagger file '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": configfile, "err": err})
+		whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXIT_CODE_ERR_GENERAL,
+			whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+		return nil, nil, whiskErr
+	}
+
+	config, err := newApiConfig(configfile, source, document)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = config.err(); err != nil {
+		return nil, nil, err
+	}
+	if _, isOpenApi := document["openapi"]; isOpenApi {
+		if swagger, err = config.swagger(); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Parse the JSON into a swagger object
 	swaggerObj := new(whisk.ApiSwagger)
 	err = json.Unmarshal([]byte(swagger), swaggerObj)
@@ -983,7 +1531,7 @@ func parseSwaggerApi(configfile string, namespace string) (*whisk.Api, error) {
 			map[string]interface{}{"name": configfile, "err": err})
 		whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXIT_CODE_ERR_GENERAL,
 			whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-		return nil, whiskErr
+		return nil, nil, whiskErr
 	}
 
 	if swaggerObj.BasePath == "" || swaggerObj.SwaggerName == "" || swaggerObj.Info == nil || swaggerObj.Paths == nil {
@@ -991,7 +1539,7 @@ func parseSwaggerApi(configfile string, namespace string) (*whisk.Api, error) {
 		errMsg := wski18n.T("Swagger file is invalid (missing basePath, info, paths, or swagger fields)")
 		whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
 			whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-		return nil, whiskErr
+		return nil, nil, whiskErr
 	}
 
 	if _, ok := isValidBasepath(swaggerObj.BasePath); !ok {
@@ -999,14 +1547,25 @@ func parseSwaggerApi(configfile string, namespace string) (*whisk.Api, error) {
 		errMsg := wski18n.T("Swagger file basePath must start with a leading slash (/)")
 		whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
 			whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-		return nil, whiskErr
+		return nil, nil, whiskErr
+	}
+
+	// Swagger 2.0 files are held to the checks above as they always were, so that the files accepted
+	// before OpenAPI 3 support keep working
+	if config.isOpenApi() {
+		config.validate()
+	} else {
+		config.warnDuplicateOperationIds()
+	}
+	if err = config.err(); err != nil {
+		return nil, nil, err
 	}
 
 	api := new(whisk.Api)
 	api.Namespace = namespace
 	api.Swagger = swagger
 
-	return api, nil
+	return api, config, nil
 }
 
 func getAccessToken() (string, error) {
@@ -1067,10 +1626,19 @@ func getUserContextId() (string, error) {
 func init() {
 
 	apiCreateCmd.Flags().StringVarP(&Flags.api.apiname, "apiname", "n", "", wski18n.T("Friendly name of the API; ignored when CFG_FILE is specified (default BASE_PATH)"))
-	apiCreateCmd.Flags().StringVarP(&Flags.api.configfile, "config-file", "c", "", wski18n.T("`CFG_FILE` containing API configuration in swagger JSON format"))
+	apiCreateCmd.Flags().StringVarP(&Flags.api.configfile, "config-file", "c", "", wski18n.T("`CFG_FILE` containing API configuration in OpenAPI 3 or swagger 2.0 format (JSON or YAML)"))
 	apiCreateCmd.Flags().StringVar(&Flags.api.resptype, "response-type", "json", wski18n.T("Set the web action response `TYPE`. Possible values are html, http, json, text, svg"))
 	apiGetCmd.Flags().BoolVarP(&Flags.common.detail, "full", "f", false, wski18n.T("display full API configuration details"))
 	apiGetCmd.Flags().StringVarP(&Flags.common.format, "format", "", formatOptionJson, wski18n.T("Specify the API output `TYPE`, either json or yaml"))
+	apiExportCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("export every API in the namespace"))
+	apiExportCmd.Flags().StringVar(&Flags.common.format, "format", apiExportFormatOpenApi3, wski18n.T("the document `FORMAT`, either openapi2 (swagger 2.0) or openapi3"))
+	apiExportCmd.Flags().BoolVar(&Flags.api.yaml, "yaml", false, wski18n.T("write the documents as YAML instead of JSON"))
+	apiTestCmd.Flags().StringVar(&Flags.api.samples, "samples", "", wski18n.T("`FILE` of sample path parameters, query parameters, headers, bodies and expected statuses per route, in JSON or YAML"))
+	apiTestCmd.Flags().StringVar(&Flags.api.baseUrl, "base-url", "", wski18n.T("send the requests to `URL` instead of the managed API URL"))
+	apiServeCmd.Flags().StringVarP(&Flags.api.configfile, "config-file", "c", "", wski18n.T("`CFG_FILE` containing API configuration in OpenAPI 3 or swagger 2.0 format (JSON or YAML)"))
+	apiServeCmd.Flags().StringVar(&Flags.api.resptype, "response-type", "json", wski18n.T("Set the web action response `TYPE`. Possible values are html, http, json, text, svg"))
+	apiServeCmd.Flags().IntVar(&Flags.api.port, "port", 9090, wski18n.T("the local `PORT` to serve the API on"))
+	apiServeCmd.Flags().StringArrayVar(&Flags.api.runtime, "runtime", []string{}, wski18n.T("run actions on the initialized local action runtime at `URL`, or at ACTION=URL for a single action, instead of forwarding to the deployed web actions"))
 	apiListCmd.Flags().IntVarP(&Flags.common.skip, "skip", "s", 0, wski18n.T("exclude the first `SKIP` number of actions from the result"))
 	apiListCmd.Flags().IntVarP(&Flags.common.limit, "limit", "l", 30, wski18n.T("only return `LIMIT` number of actions from the collection"))
 	apiListCmd.Flags().BoolVarP(&Flags.common.nameSort, "name-sort", "n", false, wski18n.T("sorts a list alphabetically by order of [BASE_PATH | API_NAME], API_PATH, then API_VERB; only applicable within the limit/skip returned entity block"))
@@ -1078,7 +1646,10 @@ func init() {
 	apiCmd.AddCommand(
 		apiCreateCmd,
 		apiGetCmd,
+		apiExportCmd,
 		apiDeleteCmd,
 		apiListCmd,
+		apiTestCmd,
+		apiServeCmd,
 	)
 }
@@ -0,0 +1,433 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+)
+
+// Time allowed for a web action to respond, the default action timeout limit
+const API_SERVE_TIMEOUT = time.Duration(TIMEOUT_LIMIT) * time.Millisecond
+
+// Web action response types accepted by --response-type
+var webActionResponseTypes = []string{"json", "http", "html", "svg", "text"}
+
+// apiEmulatorRoute maps one path and verb of an API configuration onto a web action
+type apiEmulatorRoute struct {
+	path    string
+	verb    string
+	pattern *regexp.Regexp
+	params  []string
+	action  string
+	url     string
+}
+
+// apiEmulator is a local stand-in for the API gateway. Requests are matched against the routes of a swagger
+// document and forwarded either to the deployed web action or to a local action runtime.
+type apiEmulator struct {
+	basePath     string
+	routes       []*apiEmulatorRoute
+	responseType string
+	host         string
+	runtimes     map[string]string
+	httpClient   *http.Client
+}
+
+// newApiEmulator(swagger, namespace, host, responseType, runtimes) builds the routes of swagger. Each runtime
+// is a runtime URL used for every action, or ACTION=URL for a single action.
+func newApiEmulator(swagger *whisk.ApiSwagger, namespace string, host string, responseType string, runtimes []string) (*apiEmulator, error) {
+	if !contains(webActionResponseTypes, responseType) {
+		errMsg := wski18n.T("Invalid response type '{{.type}}'; valid types are {{.types}}",
+			map[string]interface{}{"type": responseType, "types": strings.Join(webActionResponseTypes, ", ")})
+		return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+			whisk.DISPLAY_USAGE)
+	}
+
+	emulator := &apiEmulator{
+		basePath:     strings.TrimSuffix(swagger.BasePath, "/"),
+		responseType: responseType,
+		host:         host,
+		runtimes:     make(map[string]string),
+		httpClient:   &http.Client{Timeout: API_SERVE_TIMEOUT},
+	}
+
+	for _, runtime := range runtimes {
+		if parts := strings.SplitN(runtime, "=", 2); len(parts) == 2 && !strings.Contains(parts[0], "://") {
+			emulator.runtimes[parts[0]] = strings.TrimSuffix(parts[1], "/")
+		} else {
+			emulator.runtimes[""] = strings.TrimSuffix(runtime, "/")
+		}
+	}
+
+	for relPath, item := range swagger.Paths {
+		params, _ := getPathParameterNames(relPath)
+		pattern := regexp.QuoteMeta(relPath)
+		for _, param := range params {
+			pattern = strings.Replace(pattern, regexp.QuoteMeta("{"+param+"}"), "([^/]+)", 1)
+		}
+
+		for verb, operation := range item.MakeOperationMap() {
+			route := &apiEmulatorRoute{
+				path:    relPath,
+				verb:    strings.ToUpper(verb),
+				pattern: regexp.MustCompile("^" + pattern + "$"),
+				params:  params,
+			}
+			if operation.XOpenWhisk != nil && len(operation.XOpenWhisk.ActionName) > 0 {
+				xOpenWhisk := map[string]interface{}{
+					"namespace": operation.XOpenWhisk.Namespace,
+					"package":   operation.XOpenWhisk.Package,
+					"action":    operation.XOpenWhisk.ActionName,
+				}
+				if xOpenWhisk["namespace"] == "_" {
+					xOpenWhisk["namespace"] = ""
+				}
+				route.action = xOpenWhiskActionName(xOpenWhisk, namespace)
+				route.url = operation.XOpenWhisk.ApiUrl
+			}
+			emulator.routes = append(emulator.routes, route)
+		}
+	}
+
+	sort.SliceStable(emulator.routes, func(i, j int) bool {
+		if emulator.routes[i].path != emulator.routes[j].path {
+			return emulator.routes[i].path < emulator.routes[j].path
+		}
+		return emulator.routes[i].verb < emulator.routes[j].verb
+	})
+
+	return emulator, nil
+}
+
+// match(method, requestPath) finds the route for a request and the values of its path parameters. The
+// returned status is http.StatusNotFound or http.StatusMethodNotAllowed when no route matches.
+func (emulator *apiEmulator) match(method string, requestPath string) (*apiEmulatorRoute, map[string]string, int) {
+	if requestPath != emulator.basePath && !strings.HasPrefix(requestPath, emulator.basePath+"/") {
+		return nil, nil, http.StatusNotFound
+	}
+	relPath := strings.TrimPrefix(requestPath, emulator.basePath)
+
+	status := http.StatusNotFound
+	for _, route := range emulator.routes {
+		matches := route.pattern.FindStringSubmatch(relPath)
+		if matches == nil && relPath == "" {
+			matches = route.pattern.FindStringSubmatch("/")
+		}
+		if matches == nil {
+			continue
+		}
+		if route.verb != method {
+			status = http.StatusMethodNotAllowed
+			continue
+		}
+
+		params := make(map[string]string)
+		for i, name := range route.params {
+			params[name], _ = url.PathUnescape(matches[i+1])
+		}
+		return route, params, http.StatusOK
+	}
+
+	return nil, nil, status
+}
+
+func (emulator *apiEmulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	// Like the API gateway, allow browsers to call the API from any origin
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS")
+
+	route, params, status := emulator.match(r.Method, r.URL.Path)
+	if route == nil && r.Method == http.MethodOptions && status == http.StatusMethodNotAllowed {
+		status = http.StatusOK
+		w.WriteHeader(status)
+	} else if route == nil {
+		writeApiEmulatorError(w, status, http.StatusText(status))
+	} else if len(route.action) == 0 {
+		status = writeApiEmulatorError(w, http.StatusNotImplemented,
+			wski18n.T("{{.verb}} {{.path}} is not mapped to an action", map[string]interface{}{"verb": route.verb, "path": route.path}))
+	} else if runtime, ok := emulator.runtimeFor(route.action); ok {
+		status = emulator.invokeRuntime(w, r, route, params, runtime)
+	} else {
+		status = emulator.forward(w, r, route, params)
+	}
+
+	target := ""
+	if route != nil {
+		target = route.action
+	}
+	fmt.Fprintf(color.Output, "%s %s %s %d %s\n", r.Method, r.URL.Path, target, status,
+		time.Since(start).Round(time.Millisecond))
+}
+
+// runtimeFor(action) returns the local runtime given for the fully qualified action name, the name
+// without its namespace, or the bare action name, falling back to the runtime given for every action
+func (emulator *apiEmulator) runtimeFor(action string) (string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(action, "/"), "/", 2)
+	for _, name := range []string{action, parts[len(parts)-1], path.Base(action), ""} {
+		if runtime, ok := emulator.runtimes[name]; ok {
+			return runtime, true
+		}
+	}
+	return "", false
+}
+
+// webActionUrl(route) returns the URL of the deployed web action of route using the emulated response type
+func (emulator *apiEmulator) webActionUrl(route *apiEmulatorRoute) string {
+	if len(route.url) > 0 {
+		webUrl := strings.TrimSuffix(route.url, path.Ext(route.url))
+		return webUrl + "." + emulator.responseType
+	}
+
+	// The web action URL always names a package; actions outside of one are in "default"
+	parts := strings.Split(strings.TrimPrefix(route.action, "/"), "/")
+	if len(parts) == 2 {
+		parts = []string{parts[0], "default", parts[1]}
+	}
+	webUrl := emulator.host + "/api/v1/web/" + strings.Join(parts, "/") + "." + emulator.responseType
+	if !strings.HasPrefix(webUrl, "http") {
+		webUrl = "https://" + webUrl
+	}
+	return webUrl
+}
+
+// forward(w, r, route, params) proxies a request to the deployed web action, passing path parameters as
+// query parameters like the API gateway does
+func (emulator *apiEmulator) forward(w http.ResponseWriter, r *http.Request, route *apiEmulatorRoute, params map[string]string) int {
+	query := r.URL.Query()
+	for name, value := range params {
+		query.Set(name, value)
+	}
+	target := emulator.webActionUrl(route)
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequest(r.Method, target, r.Body)
+	if err != nil {
+		return writeApiEmulatorError(w, http.StatusBadGateway, err.Error())
+	}
+	for name, values := range r.Header {
+		request.Header[name] = values
+	}
+
+	whisk.Debug(whisk.DbgInfo, "Forwarding %s %s to %s\n", r.Method, r.URL.Path, target)
+	response, err := emulator.httpClient.Do(request)
+	if err != nil {
+		return writeApiEmulatorError(w, http.StatusBadGateway, err.Error())
+	}
+	defer response.Body.Close()
+
+	for name, values := range response.Header {
+		if !strings.HasPrefix(name, "Access-Control-") {
+			w.Header()[name] = values
+		}
+	}
+	w.WriteHeader(response.StatusCode)
+	io.Copy(w, response.Body)
+
+	return response.StatusCode
+}
+
+// invokeRuntime(w, r, route, params, runtime) runs the action of route on a local action runtime that has
+// already been initialized with the action code, then renders the result as the web action would
+func (emulator *apiEmulator) invokeRuntime(w http.ResponseWriter, r *http.Request, route *apiEmulatorRoute, params map[string]string, runtime string) int {
+	value, err := webActionParameters(r, params)
+	if err != nil {
+		return writeApiEmulatorError(w, http.StatusBadRequest, err.Error())
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"value":       value,
+		"action_name": route.action,
+		"deadline":    fmt.Sprint(time.Now().Add(emulator.httpClient.Timeout).UnixNano() / int64(time.Millisecond)),
+	})
+
+	whisk.Debug(whisk.DbgInfo, "Running %s on local runtime %s\n", route.action, runtime)
+	response, err := emulator.httpClient.Post(runtime+"/run", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return writeApiEmulatorError(w, http.StatusBadGateway, err.Error())
+	}
+	defer response.Body.Close()
+
+	var result interface{}
+	if err = json.NewDecoder(response.Body).Decode(&result); err != nil || response.StatusCode != http.StatusOK {
+		errMsg := wski18n.T("The action did not produce a valid response (status {{.status}}).",
+			map[string]interface{}{"status": response.StatusCode})
+		return writeApiEmulatorError(w, http.StatusBadGateway, errMsg)
+	}
+
+	return writeWebActionResult(w, result, emulator.responseType)
+}
+
+// webActionParameters(r, params) builds the parameters a web action receives for a request: the path and
+// query parameters, the properties of a JSON or form body and the __ow_ request properties
+func webActionParameters(r *http.Request, params map[string]string) (map[string]interface{}, error) {
+	value := make(map[string]interface{})
+
+	for name, values := range r.URL.Query() {
+		value[name] = values[0]
+	}
+	for name, param := range params {
+		value[name] = param
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 0 {
+		contentType := r.Header.Get("Content-Type")
+		switch {
+		case strings.HasPrefix(contentType, "application/json"):
+			var properties map[string]interface{}
+			if err = json.Unmarshal(body, &properties); err != nil {
+				return nil, err
+			}
+			for name, property := range properties {
+				value[name] = property
+			}
+		case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+			form, err := url.ParseQuery(string(body))
+			if err != nil {
+				return nil, err
+			}
+			for name, values := range form {
+				value[name] = values[0]
+			}
+		default:
+			value["__ow_body"] = base64.StdEncoding.EncodeToString(body)
+		}
+	}
+
+	headers := make(map[string]interface{})
+	for name, values := range r.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	value["__ow_method"] = strings.ToLower(r.Method)
+	value["__ow_headers"] = headers
+	value["__ow_path"] = ""
+
+	return value, nil
+}
+
+// writeWebActionResult(w, result, responseType) renders an action result with the semantics of the web
+// action extension responseType
+func writeWebActionResult(w http.ResponseWriter, result interface{}, responseType string) int {
+	resultObj, _ := result.(map[string]interface{})
+
+	var property string
+	var contentType string
+	switch responseType {
+	case "json":
+		content, _ := json.Marshal(result)
+		return writeApiEmulatorResponse(w, http.StatusOK, "application/json", content)
+	case "http":
+		return writeWebActionHttpResult(w, resultObj)
+	case "html":
+		property, contentType = "html", "text/html; charset=UTF-8"
+	case "svg":
+		property, contentType = "svg", "image/svg+xml"
+	case "text":
+		property, contentType = "text", "text/plain; charset=UTF-8"
+	}
+
+	content, ok := resultObj[property].(string)
+	if !ok {
+		errMsg := wski18n.T("The action did not return a '{{.property}}' property.", map[string]interface{}{"property": property})
+		return writeApiEmulatorError(w, http.StatusBadRequest, errMsg)
+	}
+	return writeApiEmulatorResponse(w, http.StatusOK, contentType, []byte(content))
+}
+
+// writeWebActionHttpResult(w, result) renders a result with statusCode, headers and body properties
+func writeWebActionHttpResult(w http.ResponseWriter, result map[string]interface{}) int {
+	status := http.StatusOK
+	if statusCode, ok := result["statusCode"].(float64); ok {
+		status = int(statusCode)
+	}
+
+	headers, _ := result["headers"].(map[string]interface{})
+	contentType := ""
+	for name, value := range headers {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = fmt.Sprint(value)
+			continue
+		}
+		w.Header().Set(name, fmt.Sprint(value))
+	}
+
+	var content []byte
+	switch body := result["body"].(type) {
+	case nil:
+	case string:
+		content = []byte(body)
+		// Binary content types are returned base64 encoded by the action
+		if len(contentType) > 0 && !isTextContentType(contentType) {
+			if decoded, err := base64.StdEncoding.DecodeString(body); err == nil {
+				content = decoded
+			}
+		}
+	default:
+		content, _ = json.Marshal(body)
+		if len(contentType) == 0 {
+			contentType = "application/json"
+		}
+	}
+
+	return writeApiEmulatorResponse(w, status, contentType, content)
+}
+
+func isTextContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") || strings.Contains(contentType, "json") ||
+		strings.Contains(contentType, "xml") || strings.Contains(contentType, "javascript")
+}
+
+func writeApiEmulatorResponse(w http.ResponseWriter, status int, contentType string, content []byte) int {
+	if len(contentType) > 0 {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	w.Write(content)
+	return status
+}
+
+func writeApiEmulatorError(w http.ResponseWriter, status int, message string) int {
+	content, _ := json.Marshal(map[string]interface{}{"error": message})
+	return writeApiEmulatorResponse(w, status, "application/json", content)
+}
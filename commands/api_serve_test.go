@@ -0,0 +1,153 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func apiServeTestSwagger() *whisk.ApiSwagger {
+	return &whisk.ApiSwagger{
+		BasePath: "/books",
+		Info:     &whisk.ApiSwaggerInfo{Title: "books"},
+		Paths: map[string]*whisk.ApiSwaggerPath{
+			"/{id}": {
+				Get: &whisk.ApiSwaggerOperation{
+					XOpenWhisk: &whisk.ApiSwaggerOpXOpenWhisk{Namespace: "guest", Package: "library", ActionName: "get-book"},
+				},
+			},
+			"/": {
+				Post: &whisk.ApiSwaggerOperation{
+					XOpenWhisk: &whisk.ApiSwaggerOpXOpenWhisk{ActionName: "add-book"},
+				},
+				Get: &whisk.ApiSwaggerOperation{},
+			},
+		},
+	}
+}
+
+func apiServeTestRequest(emulator *apiEmulator, method string, target string, body string) (*http.Response, string) {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if len(body) > 0 {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	emulator.ServeHTTP(recorder, request)
+
+	response := recorder.Result()
+	content, _ := ioutil.ReadAll(response.Body)
+	return response, string(content)
+}
+
+func TestApiEmulatorMatch(t *testing.T) {
+	emulator, err := newApiEmulator(apiServeTestSwagger(), "guest", "localhost", "json", nil)
+	assert.Nil(t, err)
+
+	route, params, status := emulator.match("GET", "/books/7")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/guest/library/get-book", route.action)
+	assert.Equal(t, map[string]string{"id": "7"}, params)
+
+	route, _, status = emulator.match("POST", "/books")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "/guest/add-book", route.action)
+
+	_, _, status = emulator.match("DELETE", "/books/7")
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+
+	_, _, status = emulator.match("GET", "/authors/7")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	_, err = newApiEmulator(apiServeTestSwagger(), "guest", "localhost", "xml", nil)
+	assert.NotNil(t, err)
+}
+
+func TestApiEmulatorRuntime(t *testing.T) {
+	var value map[string]interface{}
+	runtime := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var run map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&run)
+		value = run["value"].(map[string]interface{})
+		w.Write([]byte(`{"statusCode": 201, "headers": {"Location": "/books/8"}, "body": {"id": 8}, "html": "<b>8</b>"}`))
+	}))
+	defer runtime.Close()
+
+	emulator, err := newApiEmulator(apiServeTestSwagger(), "guest", "localhost", "http", []string{"add-book=" + runtime.URL})
+	assert.Nil(t, err)
+
+	response, content := apiServeTestRequest(emulator, "POST", "/books?source=test", `{"title": "Dune"}`)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "/books/8", response.Header.Get("Location"))
+	assert.Equal(t, `{"id":8}`, content)
+	assert.Equal(t, "Dune", value["title"])
+	assert.Equal(t, "test", value["source"])
+	assert.Equal(t, "post", value["__ow_method"])
+
+	emulator.responseType = "html"
+	response, content = apiServeTestRequest(emulator, "POST", "/books", `{}`)
+	assert.Equal(t, "text/html; charset=UTF-8", response.Header.Get("Content-Type"))
+	assert.Equal(t, "<b>8</b>", content)
+
+	emulator.responseType = "text"
+	response, _ = apiServeTestRequest(emulator, "POST", "/books", `{}`)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, _ = apiServeTestRequest(emulator, "GET", "/books", "")
+	assert.Equal(t, http.StatusNotImplemented, response.StatusCode)
+
+	response, _ = apiServeTestRequest(emulator, "OPTIONS", "/books", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "*", response.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestApiEmulatorForward(t *testing.T) {
+	var requested string
+	host := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "7"}`))
+	}))
+	defer host.Close()
+
+	emulator, err := newApiEmulator(apiServeTestSwagger(), "guest", host.URL, "json", nil)
+	assert.Nil(t, err)
+
+	response, content := apiServeTestRequest(emulator, "GET", "/books/7", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, `{"id": "7"}`, content)
+	assert.Equal(t, "/api/v1/web/guest/library/get-book.json?id=7", requested)
+
+	emulator.responseType = "text"
+	for _, route := range emulator.routes {
+		if route.path == "/{id}" {
+			route.url = host.URL + "/custom/get-book.http"
+		}
+	}
+	apiServeTestRequest(emulator, "GET", "/books/7", "")
+	assert.Equal(t, "/custom/get-book.text?id=7", requested)
+}
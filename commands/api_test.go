@@ -0,0 +1,114 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiTestSampleFor(t *testing.T) {
+	samples, err := loadApiTestSamples(writeApiTestFile(t, "samples.yaml", `
+"*":
+  headers:
+    X-Test: all
+"/books/{id}":
+  path:
+    id: 7
+  status: 200
+"DELETE /books/{id}":
+  headers:
+    X-Test: delete
+  status: 204
+`))
+	assert.Nil(t, err)
+
+	sample := apiTestSampleFor(samples, "delete", "/books/{id}")
+	assert.Equal(t, map[string]interface{}{"id": float64(7)}, sample.Path)
+	assert.Equal(t, "delete", sample.Headers["X-Test"])
+	assert.Equal(t, 204, sample.Status)
+
+	sample = apiTestSampleFor(samples, "get", "/books/{id}")
+	assert.Equal(t, "all", sample.Headers["X-Test"])
+	assert.Equal(t, 200, sample.Status)
+
+	sample = apiTestSampleFor(nil, "get", "/authors")
+	assert.Equal(t, 0, sample.Status)
+	assert.Empty(t, sample.Headers)
+}
+
+func TestRunApiTest(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/books/7" && r.Method == "GET":
+			w.Write([]byte(`{"id": 7, "q": "` + r.URL.Query().Get("q") + `"}`))
+		case r.URL.Path == "/books" && r.Method == "POST":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(r.Header.Get("Content-Type") + " " + string(body)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer gateway.Close()
+
+	swagger := &whisk.ApiSwagger{
+		BasePath: "/books",
+		Info:     &whisk.ApiSwaggerInfo{Title: "books"},
+		Paths: map[string]*whisk.ApiSwaggerPath{
+			"/{id}": {Get: &whisk.ApiSwaggerOperation{}, Delete: &whisk.ApiSwaggerOperation{}},
+			"":      {Post: &whisk.ApiSwaggerOperation{}},
+		},
+	}
+	routes := genFilteredList(&whisk.RetApi{BaseUrl: gateway.URL + "/books/", Swagger: swagger}, "/{id}", "get")
+	assert.Equal(t, 1, len(routes))
+
+	httpClient := newApiTestHttpClient()
+	result := runApiTest(httpClient, routes[0], apiTestSample{
+		Path:  map[string]interface{}{"id": 7},
+		Query: map[string]interface{}{"q": "title"},
+	})
+	assert.True(t, result.passed())
+	assert.Equal(t, gateway.URL+"/books/7?q=title", result.url)
+	assert.Equal(t, `{"id": 7, "q": "title"}`, result.snippet)
+
+	result = runApiTest(httpClient, routes[0], apiTestSample{})
+	assert.False(t, result.passed())
+	assert.Contains(t, result.err.Error(), "path parameter 'id'")
+
+	result = runApiTest(httpClient, routes[0], apiTestSample{Path: map[string]interface{}{"id": 7}, Status: http.StatusAccepted})
+	assert.False(t, result.passed())
+	assert.Equal(t, http.StatusOK, result.status)
+
+	routes = genFilteredList(&whisk.RetApi{BaseUrl: gateway.URL + "/books", Swagger: swagger}, "", "post")
+	result = runApiTest(httpClient, routes[0], apiTestSample{Body: map[string]interface{}{"title": "Dune"}})
+	assert.True(t, result.passed())
+	assert.Equal(t, `application/json {"title":"Dune"}`, result.snippet)
+
+	routes = genFilteredList(&whisk.RetApi{BaseUrl: gateway.URL + "/authors", Swagger: swagger}, "", "post")
+	result = runApiTest(httpClient, routes[0], apiTestSample{})
+	assert.False(t, result.passed())
+	assert.Equal(t, http.StatusNotFound, result.status)
+}
@@ -0,0 +1,548 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+)
+
+// Filter operators, longest first so that "!=" is not read as "!" followed by "="
+var filterOperators = []string{"!=", ">=", "<=", "=", ">", "<", "~"}
+
+// Relative ages such as 30m, 12h, 7d or 2w
+var filterAgePattern = regexp.MustCompile(`^(\d+)([smhdw])$`)
+
+var filterAgeUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// Shorthand field names accepted in filter and sort expressions
+var fieldAliases = map[string]string{
+	"annotation": "annotations",
+	"param":      "parameters",
+	"parameter":  "parameters",
+	"params":     "parameters",
+}
+
+// listFilter is a single FIELD OPERATOR VALUE expression given with --filter
+type listFilter struct {
+	field    string
+	operator string
+	value    string
+	regex    *regexp.Regexp // the pattern of ~, or of a = or != value with '*' in it
+}
+
+// listQuery holds the client-side filters and sort order applied to the entities of a list command
+type listQuery struct {
+	filters    []listFilter
+	sortBy     string
+	descending bool
+}
+
+// newListQuery(filters, sortBy) parses the --filter expressions and the --sort-by FIELD[:asc|:desc]
+// value of a list command
+func newListQuery(filters []string, sortBy string) (*listQuery, error) {
+	query := &listQuery{}
+
+	for _, expression := range filters {
+		filter, err := parseListFilter(expression)
+		if err != nil {
+			return nil, err
+		}
+		query.filters = append(query.filters, *filter)
+	}
+
+	if len(sortBy) > 0 {
+		field := sortBy
+		if i := strings.LastIndex(sortBy, ":"); i >= 0 {
+			field = sortBy[:i]
+			switch strings.ToLower(sortBy[i+1:]) {
+			case "asc":
+			case "desc":
+				query.descending = true
+			default:
+				errStr := wski18n.T("Invalid sort order '{{.sort}}': expected FIELD, FIELD:asc or FIELD:desc",
+					map[string]interface{}{"sort": sortBy})
+				return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+					whisk.DISPLAY_USAGE)
+			}
+		}
+		if len(field) == 0 {
+			errStr := wski18n.T("Invalid sort order '{{.sort}}': expected FIELD, FIELD:asc or FIELD:desc",
+				map[string]interface{}{"sort": sortBy})
+			return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+				whisk.DISPLAY_USAGE)
+		}
+		query.sortBy = field
+	}
+
+	return query, nil
+}
+
+func parseListFilter(expression string) (*listFilter, error) {
+	var filter *listFilter
+
+	if i := strings.IndexAny(expression, "=!<>~"); i > 0 {
+		for _, operator := range filterOperators {
+			if strings.HasPrefix(expression[i:], operator) {
+				filter = &listFilter{
+					field:    strings.TrimSpace(expression[:i]),
+					operator: operator,
+					value:    strings.TrimSpace(expression[i+len(operator):]),
+				}
+				break
+			}
+		}
+	}
+
+	if filter == nil || len(filter.field) == 0 {
+		errStr := wski18n.T("Invalid filter expression '{{.filter}}': expected FIELD OPERATOR VALUE where OPERATOR is one of =, !=, <, <=, >, >= or ~",
+			map[string]interface{}{"filter": expression})
+		return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+			whisk.DISPLAY_USAGE)
+	}
+
+	if filter.operator == "~" {
+		regex, err := regexp.Compile(filter.value)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "regexp.Compile(%s) error: %s\n", filter.value, err)
+			errStr := wski18n.T("Invalid filter expression '{{.filter}}': {{.err}}",
+				map[string]interface{}{"filter": expression, "err": err})
+			return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+				whisk.DISPLAY_USAGE)
+		}
+		filter.regex = regex
+	} else if (filter.operator == "=" || filter.operator == "!=") && strings.Contains(filter.value, "*") {
+		filter.regex = globPattern(filter.value)
+	}
+
+	return filter, nil
+}
+
+// sorted() reports whether a --sort-by order was requested; when it is, the complete list
+// has to be retrieved before anything can be printed
+func (query *listQuery) sorted() bool {
+	return len(query.sortBy) > 0
+}
+
+// apply(collection) returns the entities of a collection (Ex: []whisk.Action) that match every
+// filter, in the requested sort order. The returned value has the same type as the collection.
+func (query *listQuery) apply(collection interface{}) interface{} {
+	if len(query.filters) == 0 && !query.sorted() {
+		return collection
+	}
+
+	entities := reflect.ValueOf(collection)
+	if entities.Kind() != reflect.Slice {
+		return collection
+	}
+
+	var fields []map[string]interface{}
+	matched := reflect.MakeSlice(entities.Type(), 0, entities.Len())
+
+	for i := 0; i < entities.Len(); i++ {
+		entity := entities.Index(i).Interface()
+		entityFields := getEntityFields(entity)
+
+		if query.matches(entityFields) {
+			matched = reflect.Append(matched, entities.Index(i))
+			fields = append(fields, entityFields)
+		}
+	}
+
+	if query.sorted() {
+		swap := reflect.Swapper(matched.Interface())
+		sort.Stable(&fieldSorter{
+			fields:     fields,
+			field:      query.sortBy,
+			descending: query.descending,
+			swap: func(i, j int) {
+				swap(i, j)
+				fields[i], fields[j] = fields[j], fields[i]
+			},
+		})
+	}
+
+	return matched.Interface()
+}
+
+func (query *listQuery) matches(fields map[string]interface{}) bool {
+	for _, filter := range query.filters {
+		if !filter.matches(fields) {
+			return false
+		}
+	}
+	return true
+}
+
+func (filter *listFilter) matches(fields map[string]interface{}) bool {
+	value, found := getFieldValue(fields, filter.field)
+	if !found || value == nil {
+		return filter.operator == "!="
+	}
+
+	if filter.operator == "~" {
+		return filter.regex.MatchString(fieldString(value))
+	}
+
+	// Timestamps (milliseconds since the epoch) can be compared against a relative age, where
+	// updated<7d reads "updated less than 7 days ago", or against a date
+	if timestamp, ok := fieldNumber(value); ok {
+		if match := filterAgePattern.FindStringSubmatch(filter.value); match != nil {
+			count, _ := strconv.ParseInt(match[1], 10, 64)
+			age := float64(time.Now().UnixNano()/int64(time.Millisecond)) - timestamp
+			return compareNumbers(age, filter.operator,
+				float64(time.Duration(count)*filterAgeUnits[match[2]]/time.Millisecond))
+		}
+		if date, ok := parseFilterDate(filter.value); ok {
+			return compareNumbers(timestamp, filter.operator, float64(date.UnixNano()/int64(time.Millisecond)))
+		}
+		if number, err := strconv.ParseFloat(filter.value, 64); err == nil {
+			return compareNumbers(timestamp, filter.operator, number)
+		}
+	}
+
+	text := fieldString(value)
+	switch filter.operator {
+	case "=":
+		return filter.matchesValue(text)
+	case "!=":
+		return !filter.matchesValue(text)
+	case "<":
+		return text < filter.value
+	case "<=":
+		return text <= filter.value
+	case ">":
+		return text > filter.value
+	case ">=":
+		return text >= filter.value
+	}
+
+	return false
+}
+
+func compareNumbers(left float64, operator string, right float64) bool {
+	switch operator {
+	case "=":
+		return left == right
+	case "!=":
+		return left != right
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	}
+
+	return false
+}
+
+// matchesValue(text) compares text with the value of a = or != filter, as a glob when it has '*' in it
+func (filter *listFilter) matchesValue(text string) bool {
+	if filter.regex != nil {
+		return filter.regex.MatchString(text)
+	}
+
+	return text == filter.value
+}
+
+// globPattern(pattern) compiles a pattern where '*' matches any sequence of characters, including
+// '/', so that kind=nodejs:* and name=/ns/pkg/* work as expected
+func globPattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+func parseFilterDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// getEntityFields(entity) returns the JSON representation of an entity as generic maps and
+// arrays so that any of its fields can be addressed by name. A few computed fields are added
+// where the information is not directly available in the entity: the kind of an action or
+// activation and the status text of an activation.
+func getEntityFields(entity interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	entityJSON, err := json.Marshal(entity)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "json.Marshal(%#v) error: %s\n", entity, err)
+		return fields
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(entityJSON))
+	decoder.UseNumber()
+	if err = decoder.Decode(&fields); err != nil {
+		whisk.Debug(whisk.DbgError, "json.Decode(%s) error: %s\n", entityJSON, err)
+		return fields
+	}
+
+	addField := func(name string, value interface{}) {
+		if _, exists := fields[name]; !exists && value != nil {
+			fields[name] = value
+		}
+	}
+
+	switch entity := reflect.Indirect(reflect.ValueOf(entity)).Interface().(type) {
+	case whisk.Action:
+		if entity.Exec != nil {
+			addField("kind", entity.Exec.Kind)
+		}
+	case whisk.Activation:
+		addField("kind", entity.Annotations.GetValue("kind"))
+		if entity.StatusCode >= 0 && entity.StatusCode < len(whisk.StatusCodes) {
+			addField("status", whisk.StatusCodes[entity.StatusCode])
+		}
+	}
+
+	return fields
+}
+
+// fieldPathSegment is one step of a field path: a field name, an array position written as
+// [N], or an array element selected by one of its fields written as [FIELD=VALUE]
+type fieldPathSegment struct {
+	name        string
+	index       int
+	selectField string
+	selectValue string
+}
+
+// parseFieldPath(path) splits a field path such as exec.kind, limits.timeout or
+// annotations[key=web-export].value into its segments
+func parseFieldPath(path string) ([]fieldPathSegment, error) {
+	var segments []fieldPathSegment
+	var name strings.Builder
+
+	addName := func() {
+		if name.Len() > 0 {
+			segments = append(segments, fieldPathSegment{name: name.String(), index: -1})
+			name.Reset()
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			addName()
+		case '[':
+			addName()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, errors.New(wski18n.T("missing ']' in field path '{{.path}}'",
+					map[string]interface{}{"path": path}))
+			}
+			selector := strings.TrimSpace(path[i+1 : i+end])
+			if index, err := strconv.Atoi(selector); err == nil && index >= 0 {
+				segments = append(segments, fieldPathSegment{index: index})
+			} else if eq := strings.IndexByte(selector, '='); eq > 0 {
+				segments = append(segments, fieldPathSegment{
+					index:       -1,
+					selectField: strings.TrimSpace(selector[:eq]),
+					selectValue: strings.Trim(strings.TrimSpace(selector[eq+1:]), `"'`),
+				})
+			} else {
+				return nil, errors.New(wski18n.T("invalid selector '[{{.selector}}]' in field path '{{.path}}'",
+					map[string]interface{}{"selector": selector, "path": path}))
+			}
+			i += end
+		default:
+			name.WriteByte(path[i])
+		}
+	}
+	addName()
+
+	if len(segments) == 0 || len(segments[0].name) == 0 {
+		return nil, errors.New(wski18n.T("field path '{{.path}}' must start with a field name",
+			map[string]interface{}{"path": path}))
+	}
+
+	return segments, nil
+}
+
+// getFieldValue(fields, path) resolves a field path such as limits.memory, response.result.body
+// or annotations[key=web-export].value. Key/value arrays like annotations and parameters can
+// also be indexed by key directly, e.g. annotations.web-export.
+func getFieldValue(fields map[string]interface{}, path string) (interface{}, bool) {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var value interface{} = fields
+
+	for i, segment := range segments {
+		switch current := value.(type) {
+		case map[string]interface{}:
+			if len(segment.name) == 0 {
+				return nil, false
+			}
+			name := segment.name
+			if i == 0 {
+				if alias, ok := fieldAliases[strings.ToLower(name)]; ok {
+					name = alias
+				}
+			}
+			next, ok := current[name]
+			if !ok {
+				for key := range current {
+					if strings.EqualFold(key, name) {
+						next, ok = current[key], true
+						break
+					}
+				}
+			}
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			index := segment.index
+			if len(segment.name) > 0 {
+				if position, err := strconv.Atoi(segment.name); err == nil {
+					index = position
+				}
+			}
+			if index >= 0 {
+				if index >= len(current) {
+					return nil, false
+				}
+				value = current[index]
+				continue
+			}
+
+			selectField, selectValue, keyValue := segment.selectField, segment.selectValue, false
+			if len(segment.name) > 0 {
+				selectField, selectValue, keyValue = "key", segment.name, true
+			}
+
+			found := false
+			for _, element := range current {
+				if object, ok := element.(map[string]interface{}); ok && fieldString(object[selectField]) == selectValue {
+					value, found = element, true
+					if keyValue {
+						value = object["value"]
+					}
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+func fieldNumber(value interface{}) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		if f, err := number.Float64(); err == nil {
+			return f, true
+		}
+	}
+
+	return 0, false
+}
+
+func fieldString(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	case nil:
+		return ""
+	}
+
+	valueJSON, _ := json.Marshal(value)
+	return string(valueJSON)
+}
+
+// fieldSorter orders entities by the value of one of their fields; numbers are compared
+// numerically, everything else as text. Entities without the field are always listed last.
+type fieldSorter struct {
+	fields     []map[string]interface{}
+	field      string
+	descending bool
+	swap       func(i, j int)
+}
+
+func (sorter *fieldSorter) Len() int {
+	return len(sorter.fields)
+}
+
+func (sorter *fieldSorter) Swap(i, j int) {
+	sorter.swap(i, j)
+}
+
+func (sorter *fieldSorter) Less(i, j int) bool {
+	left, leftFound := getFieldValue(sorter.fields[i], sorter.field)
+	right, rightFound := getFieldValue(sorter.fields[j], sorter.field)
+
+	if !leftFound || !rightFound {
+		return leftFound && !rightFound
+	}
+
+	var less, greater bool
+	leftNumber, leftIsNumber := fieldNumber(left)
+	rightNumber, rightIsNumber := fieldNumber(right)
+
+	if leftIsNumber && rightIsNumber {
+		less, greater = leftNumber < rightNumber, leftNumber > rightNumber
+	} else {
+		leftString, rightString := fieldString(left), fieldString(right)
+		less, greater = leftString < rightString, leftString > rightString
+	}
+
+	if sorter.descending {
+		return greater
+	}
+	return less
+}
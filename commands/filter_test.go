@@ -0,0 +1,193 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func filterTestActions() []whisk.Action {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	day := int64(24 * time.Hour / time.Millisecond)
+
+	return []whisk.Action{
+		{
+			Name:        "hello",
+			Exec:        &whisk.Exec{Kind: "nodejs:14"},
+			Annotations: whisk.KeyValueArr{{Key: "web-export", Value: true}},
+			Limits:      &whisk.Limits{Memory: &[]int{1024}[0]},
+			Updated:     now - 2*day,
+		},
+		{
+			Name:    "report",
+			Exec:    &whisk.Exec{Kind: "python:3"},
+			Limits:  &whisk.Limits{Memory: &[]int{256}[0]},
+			Updated: now - 30*day,
+		},
+		{
+			Name:        "upload",
+			Exec:        &whisk.Exec{Kind: "nodejs:12"},
+			Annotations: whisk.KeyValueArr{{Key: "web-export", Value: true}},
+			Limits:      &whisk.Limits{Memory: &[]int{512}[0]},
+			Updated:     now - 10*day,
+		},
+	}
+}
+
+func filterTestNames(actions interface{}) []string {
+	var names []string
+	for _, action := range actions.([]whisk.Action) {
+		names = append(names, action.Name)
+	}
+	return names
+}
+
+func TestListQueryFilters(t *testing.T) {
+	filters := map[string][]string{
+		"kind=nodejs:*":               {"hello", "upload"},
+		"kind!=nodejs:*":              {"report"},
+		"kind=*:3":                    {"report"},
+		"name=re.*":                   nil,
+		"annotation.web-export=true":  {"hello", "upload"},
+		"limits.memory>256":           {"hello", "upload"},
+		"limits.memory<=256":          {"report"},
+		"updated<7d":                  {"hello"},
+		"updated>=7d":                 {"report", "upload"},
+		"name~^(re|up)":               {"report", "upload"},
+		"name!=hello":                 {"report", "upload"},
+		"annotation.web-export!=true": {"report"},
+		"missing=anything":            nil,
+	}
+
+	for filter, expected := range filters {
+		query, err := newListQuery([]string{filter}, "")
+		assert.Nil(t, err, filter)
+		assert.Equal(t, expected, filterTestNames(query.apply(filterTestActions())), filter)
+	}
+
+	query, err := newListQuery([]string{"annotation.web-export=true", "limits.memory>512"}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"hello"}, filterTestNames(query.apply(filterTestActions())))
+}
+
+func TestListQuerySort(t *testing.T) {
+	sorts := map[string][]string{
+		"limits.memory":      {"report", "upload", "hello"},
+		"limits.memory:desc": {"hello", "upload", "report"},
+		"kind:asc":           {"upload", "hello", "report"},
+		"updated:desc":       {"hello", "upload", "report"},
+	}
+
+	for sortBy, expected := range sorts {
+		query, err := newListQuery(nil, sortBy)
+		assert.Nil(t, err, sortBy)
+		assert.True(t, query.sorted())
+		assert.Equal(t, expected, filterTestNames(query.apply(filterTestActions())), sortBy)
+	}
+}
+
+func TestListQueryInvalid(t *testing.T) {
+	for _, filter := range []string{"name", "=value", "name~(", ""} {
+		_, err := newListQuery([]string{filter}, "")
+		assert.NotNil(t, err, filter)
+	}
+
+	for _, sortBy := range []string{"name:up", ":desc"} {
+		_, err := newListQuery(nil, sortBy)
+		assert.NotNil(t, err, sortBy)
+	}
+}
+
+func TestGetEntityFieldsActivation(t *testing.T) {
+	activation := whisk.Activation{
+		Name:        "hello",
+		StatusCode:  2,
+		Annotations: whisk.KeyValueArr{{Key: "kind", Value: "nodejs:14"}},
+	}
+	fields := getEntityFields(activation)
+
+	status, found := getFieldValue(fields, "status")
+	assert.True(t, found)
+	assert.Equal(t, "developer error", status)
+
+	kind, found := getFieldValue(fields, "kind")
+	assert.True(t, found)
+	assert.Equal(t, "nodejs:14", kind)
+}
+
+func TestGetFieldValuePaths(t *testing.T) {
+	action := whisk.Action{
+		Name:        "hello",
+		Exec:        &whisk.Exec{Kind: "nodejs:14"},
+		Annotations: whisk.KeyValueArr{{Key: "exec", Value: "nodejs:14"}, {Key: "web-export", Value: true}},
+		Limits:      &whisk.Limits{Timeout: &[]int{60000}[0]},
+	}
+	fields := getEntityFields(&action)
+
+	paths := map[string]interface{}{
+		"exec.kind":                           "nodejs:14",
+		"limits.timeout":                      "60000",
+		"annotations[key=web-export].value":   "true",
+		"annotations[1].key":                  "web-export",
+		"annotations.web-export":              "true",
+		"annotations[key='web-export'].value": "true",
+	}
+
+	for path, expected := range paths {
+		value, found := getFieldValue(fields, path)
+		assert.True(t, found, path)
+		assert.Equal(t, expected, fieldString(value), path)
+	}
+
+	for _, path := range []string{"exec.image", "annotations[key=raw-http].value", "annotations[5]", "name.first"} {
+		_, found := getFieldValue(fields, path)
+		assert.False(t, found, path)
+	}
+
+	var result whisk.Result = map[string]interface{}{"body": "hi"}
+	activation := whisk.Activation{Response: whisk.Response{Result: &result}}
+	body, found := getFieldValue(getEntityFields(activation), "response.result.body")
+	assert.True(t, found)
+	assert.Equal(t, "hi", body)
+}
+
+func TestFieldExistsPaths(t *testing.T) {
+	assert.True(t, fieldExists(&whisk.Action{}, "exec"))
+	assert.True(t, fieldExists(&whisk.Action{}, "exec.kind"))
+	assert.True(t, fieldExists(&whisk.Action{}, "annotations[key=web-export].value"))
+	assert.True(t, fieldExists(&whisk.Activation{}, "response.result.body"))
+	assert.False(t, fieldExists(&whisk.Action{}, "bogus.kind"))
+	assert.False(t, fieldExists(&whisk.Action{}, "annotations[key=web-export.value"))
+	assert.False(t, fieldExists(&whisk.Action{}, "[0].name"))
+}
+
+func TestPrintFieldPaths(t *testing.T) {
+	action := &whisk.Action{Name: "hello", Exec: &whisk.Exec{Kind: "nodejs:14"}}
+	assert.True(t, printField(action, "name"))
+	assert.True(t, printField(action, "exec.kind"))
+
+	// A mistyped nested path is not found rather than displayed as null
+	assert.False(t, printField(action, "exec.knd"))
+	assert.False(t, printField(action, "limits.memory"))
+}
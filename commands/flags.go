@@ -66,7 +66,11 @@ type FlagsStruct struct {
 		feed       string // name of feed
 		detail     bool
 		format     string
-		nameSort   bool // sorts list alphabetically by entity name
+		nameSort   bool     // sorts list alphabetically by entity name
+		all        bool     // page through the entire collection
+		filter     []string // client-side FIELD OPERATOR VALUE list filters
+		sortBy     string   // client-side list sort order, FIELD[:asc|:desc]
+		template   string   // Go template used to format the output of get commands
 		overwrite  bool
 	}
 
@@ -103,6 +107,8 @@ type FlagsStruct struct {
 		last         bool
 		strip        bool
 		logs         bool
+		to           string // export destination, an NDJSON file or a directory
+		fetchLogs    bool   // retrieve the logs of activations listed without them while exporting
 	}
 
 	// rule
@@ -116,6 +122,26 @@ type FlagsStruct struct {
 		summary      bool
 		feedParam    []string
 		triggerParam []string
+		schedule     string
+		every        string
+		timezone     string
+		start        string
+		stop         string
+		next         int
+		payloadFile  string
+		batch        string
+		rate         float64
+		wait         bool
+		waitTimeout  int
+	}
+
+	// package
+	pkg struct {
+		to          string   // export destination, a bundle file
+		as          string   // name of the package created by an import
+		namespace   string   // namespace an import creates the package in
+		namespaces  []string // namespaces a search looks in besides the accessible and system ones
+		showSecrets bool     // show parameter values that hold secrets
 	}
 
 	//sdk
@@ -132,6 +158,19 @@ type FlagsStruct struct {
 		apiname    string
 		configfile string
 		resptype   string
+		yaml       bool
+		samples    string
+		baseUrl    string
+		port       int
+		runtime    []string
+	}
+
+	// project
+	project struct {
+		out      string // file a plan is saved to
+		template string // name or directory of the template a project is created from
+		kind     string // runtime kind of the sample actions of a new project
+		all      bool   // export every entity of the namespace, not only those of a project
 	}
 }
 
@@ -148,10 +187,12 @@ type ActionFlags struct {
 	concurrency   int
 	result        bool
 	kind          string
+	resolveKind   bool
 	main          string
 	url           bool
 	save          bool
 	saveAs        string
+	record        bool
 	delAnnotation []string
 }
 
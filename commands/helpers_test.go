@@ -0,0 +1,165 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestClient points the global Client at a stand-in controller for the duration of a test
+func newTestClient(t *testing.T, handler http.HandlerFunc) {
+	server := httptest.NewServer(handler)
+	savedClient := Client
+	t.Cleanup(func() {
+		server.Close()
+		Client = savedClient
+	})
+
+	baseURL, _ := url.Parse(server.URL + "/api/")
+	client, err := whisk.NewClient(http.DefaultClient, &whisk.Config{
+		Host: server.URL, BaseURL: baseURL, Namespace: "guest", AuthToken: "user:pass", Version: "v1"})
+	assert.Nil(t, err)
+	Client = client
+}
+
+// entityStore is a stand-in controller that keeps the packages, actions, triggers and rules of the guest
+// namespace
+type entityStore struct {
+	entities map[string]map[string]interface{} // keyed by collection/name, e.g. actions/pkg/a
+	failing  string                            // a "METHOD collection/name" request that fails
+	updates  int64                             // the update time given to the next entity stored
+}
+
+func newEntityStore(t *testing.T, entities map[string]string) *entityStore {
+	store := &entityStore{entities: make(map[string]map[string]interface{})}
+	for key, body := range entities {
+		entity := make(map[string]interface{})
+		assert.Nil(t, json.Unmarshal([]byte(body), &entity))
+		store.put(key, entity)
+	}
+	newTestClient(t, store.serve)
+
+	savedCache := ruleStatusCache
+	ruleStatusCache = &ruleCache{entries: make(map[string]ruleCacheEntry)}
+	t.Cleanup(func() { ruleStatusCache = savedCache })
+	return store
+}
+
+func (store *entityStore) put(key string, entity map[string]interface{}) {
+	parts := strings.Split(key, "/")
+	entity["namespace"] = strings.Join(append([]string{"guest"}, parts[1:len(parts)-1]...), "/")
+	entity["name"] = parts[len(parts)-1]
+	store.updates++
+	entity["updated"] = store.updates
+	if parts[0] == "rules" {
+		for _, field := range []string{"trigger", "action"} {
+			if name, ok := entity[field].(string); ok {
+				i := strings.LastIndex(name, "/")
+				entity[field] = map[string]interface{}{"path": strings.Trim(name[:i], "/"), "name": name[i+1:]}
+			}
+		}
+		if status, _ := entity["status"].(string); len(status) == 0 {
+			entity["status"] = "active"
+		}
+	}
+	store.entities[key] = entity
+}
+
+func (store *entityStore) names(collection string) []string {
+	var names []string
+	for key := range store.entities {
+		if strings.HasPrefix(key, collection+"/") {
+			names = append(names, strings.TrimPrefix(key, collection+"/"))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (store *entityStore) serve(w http.ResponseWriter, r *http.Request) {
+	key := strings.Trim(r.URL.Path[strings.Index(r.URL.Path, "/namespaces/guest/")+len("/namespaces/guest/"):], "/")
+	w.Header().Set("Content-Type", "application/json")
+	if store.failing == r.Method+" "+key {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error": "The server encountered an error."}`))
+		return
+	}
+
+	entity, exists := store.entities[key]
+	switch {
+	case r.Method == http.MethodGet && (!strings.Contains(key, "/") || strings.HasSuffix(r.URL.Path, "/")):
+		list := []interface{}{}
+		if r.URL.Query().Get("skip") == "0" {
+			for _, name := range store.names(key) {
+				list = append(list, store.entities[key+"/"+name])
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodGet && exists:
+		if strings.HasPrefix(key, "packages/") {
+			var actions []map[string]interface{}
+			for _, name := range store.names("actions/" + strings.TrimPrefix(key, "packages/")) {
+				actions = append(actions, map[string]interface{}{"name": name})
+			}
+			entity["actions"] = actions
+		}
+		json.NewEncoder(w).Encode(entity)
+	case r.Method == http.MethodPut && (!exists || r.URL.Query().Get("overwrite") == "true"):
+		entity = make(map[string]interface{})
+		json.NewDecoder(r.Body).Decode(&entity)
+		store.put(key, entity)
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodPost && exists:
+		var state map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&state)
+		entity["status"] = state["status"]
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodDelete && exists:
+		delete(store.entities, key)
+		w.Write([]byte(`{}`))
+	case exists:
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error": "resource already exists"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "The requested resource does not exist."}`))
+	}
+}
+
+func entityStoreTestEntities() map[string]string {
+	return map[string]string{
+		"packages/tools":      `{"publish": false}`,
+		"actions/tools/hello": `{"exec": {"kind": "nodejs:default", "code": "function main() {}"}}`,
+		"actions/tools/steps": `{"exec": {"kind": "sequence", "components": ["/guest/tools/hello"]}, "annotations": [{"key": "exec", "value": "sequence"}]}`,
+		"actions/pipeline":    `{"exec": {"kind": "sequence", "components": ["/guest/tools/hello", "/guest/other"]}, "annotations": [{"key": "exec", "value": "sequence"}]}`,
+		"actions/other":       `{"exec": {"kind": "nodejs:default", "code": "function main() {}"}}`,
+		"rules/greet":         `{"trigger": "/guest/ticks", "action": "/guest/tools/hello", "status": "inactive"}`,
+		"rules/unrelated":     `{"trigger": "/guest/ticks", "action": "/guest/other"}`,
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+)
+
+const MEGABYTE = 1024 * 1024
+
+// hostLimits are the system limits the API host advertises. Memory, log and code sizes are in bytes
+// and durations in milliseconds; a limit the API host does not advertise is not checked.
+type hostLimits struct {
+	MinActionMemory      *int64 `json:"min_action_memory,omitempty"`
+	MaxActionMemory      *int64 `json:"max_action_memory,omitempty"`
+	MinActionDuration    *int64 `json:"min_action_duration,omitempty"`
+	MaxActionDuration    *int64 `json:"max_action_duration,omitempty"`
+	MinActionLogs        *int64 `json:"min_action_logs,omitempty"`
+	MaxActionLogs        *int64 `json:"max_action_logs,omitempty"`
+	MinActionConcurrency *int64 `json:"min_action_concurrency,omitempty"`
+	MaxActionConcurrency *int64 `json:"max_action_concurrency,omitempty"`
+	SequenceLength       *int64 `json:"sequence_length,omitempty"`
+	MaxActionCodeSize    *int64 `json:"max_action_code_size,omitempty"`
+}
+
+// checkActionLimits checks the limits and sequence length of an action against the limits of the API
+// host, and warns when its code is larger than the API host accepts. The code is measured as it is
+// sent, so binary code includes the overhead of its base64 encoding. The action is left to the API
+// host to check when its limits are unknown. Cached limits may be out of date, so an action they
+// reject is checked again against limits fetched from the API host, or only warned about when the
+// API host cannot be reached.
+func checkActionLimits(action *whisk.Action) error {
+	var exec *whisk.Exec
+	if action.Exec != nil && (action.Exec.Code != nil || len(action.Exec.Components) > 0) {
+		exec = action.Exec
+	}
+	if action.Limits == nil && exec == nil {
+		return nil
+	}
+
+	info, err := getHostInfo()
+	if err != nil || info.Limits == nil {
+		whisk.Debug(whisk.DbgWarn, "The limits of action '%s' are not checked: %v\n", action.Name, err)
+		return nil
+	}
+	limits := info.Limits
+
+	if err = checkActionLimitRanges(action, exec, limits); err != nil && !info.fetched {
+		whisk.Debug(whisk.DbgInfo, "The cached limits reject action '%s'; fetching them again\n", action.Name)
+		fresh, refreshErr := refreshHostInfo()
+		if refreshErr != nil || fresh.Limits == nil {
+			printWarning(err.Error())
+			return nil
+		}
+		limits = fresh.Limits
+		err = checkActionLimitRanges(action, exec, limits)
+	}
+	if err != nil {
+		return err
+	}
+
+	if exec != nil && exec.Code != nil && limits.MaxActionCodeSize != nil && int64(len(*exec.Code)) > *limits.MaxActionCodeSize {
+		printWarning(wski18n.T("The code of action '{{.name}}' is {{.size}} bytes, more than the {{.max}} bytes the API host accepts",
+			map[string]interface{}{"name": action.Name, "size": len(*exec.Code), "max": *limits.MaxActionCodeSize}))
+	}
+
+	return nil
+}
+
+// checkActionLimitRanges checks the limits and sequence length of an action, which the API host rejects
+// when they are out of range
+func checkActionLimitRanges(action *whisk.Action, exec *whisk.Exec, limits *hostLimits) error {
+	if action.Limits != nil {
+		if err := checkLimitRange(MEMORY_FLAG, action.Limits.Memory, limits.MinActionMemory, limits.MaxActionMemory, MEGABYTE); err != nil {
+			return err
+		}
+		if err := checkLimitRange(TIMEOUT_FLAG, action.Limits.Timeout, limits.MinActionDuration, limits.MaxActionDuration, 1); err != nil {
+			return err
+		}
+		if err := checkLimitRange(LOG_SIZE_FLAG, action.Limits.Logsize, limits.MinActionLogs, limits.MaxActionLogs, MEGABYTE); err != nil {
+			return err
+		}
+		if err := checkLimitRange(CONCURRENCY_FLAG, action.Limits.Concurrency, limits.MinActionConcurrency, limits.MaxActionConcurrency, 1); err != nil {
+			return err
+		}
+	}
+
+	if exec != nil && limits.SequenceLength != nil && int64(len(exec.Components)) > *limits.SequenceLength {
+		errStr := wski18n.T("The sequence has {{.length}} actions, more than the {{.max}} the API host allows.",
+			map[string]interface{}{"length": len(exec.Components), "max": *limits.SequenceLength})
+		return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return nil
+}
+
+// checkLimitRange checks a limit set by a flag, in units of unit, against the minimum and maximum the
+// API host allows
+func checkLimitRange(flag string, value *int, min *int64, max *int64, unit int64) error {
+	if value == nil || min == nil || max == nil {
+		return nil
+	}
+
+	if limit := int64(*value) * unit; limit < *min || limit > *max {
+		errStr := wski18n.T("The --{{.flag}} value {{.value}} is not within the range of {{.min}} to {{.max}} the API host allows.",
+			map[string]interface{}{"flag": flag, "value": *value, "min": *min / unit, "max": *max / unit})
+		return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return nil
+}
+
+// limitRangeString formats the range of a limit in units of unit, or returns an empty string when the
+// API host does not advertise it
+func limitRangeString(min *int64, max *int64, unit int64) string {
+	if min == nil || max == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d - %d", *min/unit, *max/unit)
+}
+
+// limitValueString formats a limit in units of unit, or returns an empty string when the API host does
+// not advertise it
+func limitValueString(value *int64, unit int64) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *value/unit)
+}
+
+// printHostLimits prints the limits of the API host among the properties 'wsk property get --all' shows
+func printHostLimits(limits *hostLimits) {
+	properties := []struct {
+		name  string
+		value string
+	}{
+		{propDisplayMemoryLimit, limitRangeString(limits.MinActionMemory, limits.MaxActionMemory, MEGABYTE)},
+		{propDisplayTimeoutLimit, limitRangeString(limits.MinActionDuration, limits.MaxActionDuration, 1)},
+		{propDisplayLogSizeLimit, limitRangeString(limits.MinActionLogs, limits.MaxActionLogs, MEGABYTE)},
+		{propDisplayConcurrencyLimit, limitRangeString(limits.MinActionConcurrency, limits.MaxActionConcurrency, 1)},
+		{propDisplaySequenceLimit, limitValueString(limits.SequenceLength, 1)},
+		{propDisplayCodeSizeLimit, limitValueString(limits.MaxActionCodeSize, MEGABYTE)},
+	}
+
+	for _, property := range properties {
+		if len(property.value) > 0 {
+			fmt.Fprintf(color.Output, "%s\t%s\n", wski18n.T(property.name), boldString(property.value))
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+const limitsTestHostInfo = `{
+	"runtimes": {},
+	"limits": {
+		"min_action_memory": 134217728,
+		"max_action_memory": 536870912,
+		"min_action_duration": 100,
+		"max_action_duration": 300000,
+		"min_action_logs": 0,
+		"max_action_logs": 10485760,
+		"sequence_length": 2,
+		"max_action_code_size": 16
+	}
+}`
+
+func TestCheckActionLimits(t *testing.T) {
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(limitsTestHostInfo))
+	})
+	newHostInfoTestCache(t)
+
+	memory, timeout, concurrency := 512, 60000, 100
+	action := &whisk.Action{Name: "hello", Limits: &whisk.Limits{Memory: &memory, Timeout: &timeout, Concurrency: &concurrency}}
+	assert.Nil(t, checkActionLimits(action))
+
+	memory = 1024
+	err := checkActionLimits(action)
+	assert.NotNil(t, err)
+	assert.Equal(t, "The --memory value 1024 is not within the range of 128 to 512 the API host allows.", err.Error())
+
+	memory, timeout = 256, 50
+	err = checkActionLimits(action)
+	assert.NotNil(t, err)
+	assert.Equal(t, "The --timeout value 50 is not within the range of 100 to 300000 the API host allows.", err.Error())
+
+	sequence := &whisk.Action{Name: "steps", Exec: &whisk.Exec{Kind: SEQUENCE, Components: []string{"/_/a", "/_/b", "/_/c"}}}
+	err = checkActionLimits(sequence)
+	assert.NotNil(t, err)
+	assert.Equal(t, "The sequence has 3 actions, more than the 2 the API host allows.", err.Error())
+
+	// Code over the maximum size is only warned about
+	code := strings.Repeat("x", 32)
+	assert.Nil(t, checkActionLimits(&whisk.Action{Name: "big", Exec: &whisk.Exec{Kind: "nodejs:default", Code: &code}}))
+
+	info, err := getHostInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, "128 - 512", limitRangeString(info.Limits.MinActionMemory, info.Limits.MaxActionMemory, MEGABYTE))
+	assert.Equal(t, "", limitRangeString(info.Limits.MinActionConcurrency, info.Limits.MaxActionConcurrency, 1))
+	assert.Equal(t, "2", limitValueString(info.Limits.SequenceLength, 1))
+}
+
+func TestCheckActionLimitsCached(t *testing.T) {
+	requests := 0
+	failing := false
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(limitsTestHostInfo))
+	})
+	cachePath := newHostInfoTestCache(t)
+
+	// The cache holds a lower maximum memory than the API host now allows
+	cacheLimits := func() {
+		minMemory, maxMemory := int64(128*MEGABYTE), int64(256*MEGABYTE)
+		writeHostInfoCache(cachePath, map[string]hostInfoCacheEntry{Client.BaseURL.Host: {
+			Fetched: time.Now().Unix(),
+			Info:    &hostInfo{Limits: &hostLimits{MinActionMemory: &minMemory, MaxActionMemory: &maxMemory}},
+		}})
+		fetchedHostInfo = make(map[string]*hostInfo)
+	}
+
+	memory := 512
+	action := &whisk.Action{Name: "hello", Limits: &whisk.Limits{Memory: &memory}}
+	cacheLimits()
+	assert.Nil(t, checkActionLimits(action))
+	assert.Equal(t, 1, requests)
+
+	// Limits fetched by this process are not fetched again
+	memory = 1024
+	assert.NotNil(t, checkActionLimits(action))
+	assert.Equal(t, 1, requests)
+
+	// When the API host cannot be reached, the cached limits only give a warning
+	cacheLimits()
+	failing = true
+	assert.Nil(t, checkActionLimits(action))
+	assert.Equal(t, 2, requests)
+}
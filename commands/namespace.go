@@ -77,38 +77,72 @@ var namespaceGetCmd = &cobra.Command{
 			}
 		}
 
-		actions, _, err := Client.Actions.List("", &whisk.ActionListOptions{Skip: 0, Limit: 0})
-		if err != nil {
-			return entityListError(err, namespace, "Actions")
-		}
+		var actions []whisk.Action
+		var packages []whisk.Package
+		var triggers []whisk.Trigger
+		var rules []whisk.Rule
+
+		if Flags.common.all {
+			err = listAllPages(0, func(skip int, limit int) (int, error) {
+				page, _, err := Client.Actions.List("", &whisk.ActionListOptions{Skip: skip, Limit: limit})
+				actions = append(actions, page...)
+				return len(page), err
+			})
+			if err != nil {
+				return entityListError(err, namespace, "Actions")
+			}
 
-		packages, _, err := Client.Packages.List(&whisk.PackageListOptions{Skip: 0, Limit: 0})
-		if err != nil {
-			return entityListError(err, namespace, "Packages")
-		}
+			err = listAllPages(0, func(skip int, limit int) (int, error) {
+				page, _, err := Client.Packages.List(&whisk.PackageListOptions{Skip: skip, Limit: limit})
+				packages = append(packages, page...)
+				return len(page), err
+			})
+			if err != nil {
+				return entityListError(err, namespace, "Packages")
+			}
 
-		triggers, _, err := Client.Triggers.List(&whisk.TriggerListOptions{Skip: 0, Limit: 0})
-		if err != nil {
-			return entityListError(err, namespace, "Triggers")
-		}
+			err = listAllPages(0, func(skip int, limit int) (int, error) {
+				page, _, err := Client.Triggers.List(&whisk.TriggerListOptions{Skip: skip, Limit: limit})
+				triggers = append(triggers, page...)
+				return len(page), err
+			})
+			if err != nil {
+				return entityListError(err, namespace, "Triggers")
+			}
 
-		rules, _, err := Client.Rules.List(&whisk.RuleListOptions{Skip: 0, Limit: 0})
-		if err != nil {
-			return entityListError(err, namespace, "Rules")
-		}
-		//No errors, lets attempt to retrieve the status of each rule
-		for index, rule := range rules {
-			ruleStatus, _, err := Client.Rules.Get(rule.Name)
+			err = listAllPages(0, func(skip int, limit int) (int, error) {
+				page, _, err := Client.Rules.List(&whisk.RuleListOptions{Skip: skip, Limit: limit})
+				rules = append(rules, page...)
+				return len(page), err
+			})
+			if err != nil {
+				return entityListError(err, namespace, "Rules")
+			}
+		} else {
+			actions, _, err = Client.Actions.List("", &whisk.ActionListOptions{Skip: 0, Limit: 0})
+			if err != nil {
+				return entityListError(err, namespace, "Actions")
+			}
+
+			packages, _, err = Client.Packages.List(&whisk.PackageListOptions{Skip: 0, Limit: 0})
+			if err != nil {
+				return entityListError(err, namespace, "Packages")
+			}
+
+			triggers, _, err = Client.Triggers.List(&whisk.TriggerListOptions{Skip: 0, Limit: 0})
 			if err != nil {
-				errStr := wski18n.T("Unable to get status of rule '{{.name}}': {{.err}}",
-					map[string]interface{}{"name": rule.Name, "err": err})
-				fmt.Println(errStr)
-				werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-				return werr
+				return entityListError(err, namespace, "Triggers")
+			}
+
+			rules, _, err = Client.Rules.List(&whisk.RuleListOptions{Skip: 0, Limit: 0})
+			if err != nil {
+				return entityListError(err, namespace, "Rules")
 			}
-			rules[index].Status = ruleStatus.Status
 		}
 
+		//No errors, lets attempt to retrieve the status of each rule
+		getRuleStatuses(rules)
+
 		fmt.Fprintf(color.Output, wski18n.T("Entities in namespace: {{.namespace}}\n",
 			map[string]interface{}{"namespace": boldString(getClientNamespace())}))
 		sortByName := Flags.common.nameSort
@@ -123,6 +157,7 @@ var namespaceGetCmd = &cobra.Command{
 
 func init() {
 	namespaceGetCmd.Flags().BoolVarP(&Flags.common.nameSort, "name-sort", "n", false, wski18n.T("sorts a list alphabetically by entity name; only applicable within the limit/skip returned entity block"))
+	namespaceGetCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("page through all entities in the namespace instead of the first block of each entity type"))
 
 	namespaceCmd.AddCommand(
 		namespaceListCmd,
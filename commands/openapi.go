@@ -0,0 +1,1091 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	swaggerVersion2       = "2.0"
+	openApiVersion3Prefix = "3."
+	openApiExportVersion  = "3.0.3"
+
+	apiExportFormatOpenApi2 = "openapi2"
+	apiExportFormatOpenApi3 = "openapi3"
+
+	apiGatewayExtensionPrefix = "x-ibm-"
+
+	openApiSchemaRefPrefix     = "#/components/schemas/"
+	openApiResponseRefPrefix   = "#/components/responses/"
+	swaggerParamRefPrefix      = "#/parameters/"
+	swaggerDefinitionRefPrefix = "#/definitions/"
+	swaggerResponseRefPrefix   = "#/responses/"
+)
+
+// Path item keys holding operations, in the order the gateway lists them
+var apiOperationVerbs = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// OpenAPI 3 parameter schema keywords that swagger 2.0 carries on the parameter itself
+var openApiParamSchemaKeys = []string{"type", "format", "items", "default", "enum", "maximum", "exclusiveMaximum",
+	"minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems",
+	"multipleOf"}
+
+// OpenAPI 3 OAuth2 flows and their swagger 2.0 equivalents, in order of preference
+var openApiOAuthFlows = [][2]string{
+	{"authorizationCode", "accessCode"},
+	{"implicit", "implicit"},
+	{"password", "password"},
+	{"clientCredentials", "application"},
+}
+
+// apiConfigProblem is a structural problem found in an API configuration file
+type apiConfigProblem struct {
+	line    int
+	message string
+}
+
+func (problem apiConfigProblem) String() string {
+	if problem.line > 0 {
+		return wski18n.T("line {{.line}}: {{.msg}}", map[string]interface{}{"line": problem.line, "msg": problem.message})
+	}
+	return problem.message
+}
+
+// apiConfig is an API configuration file normalized into the swagger 2.0 document expected by the API gateway.
+// The source node tree of the original file is kept so problems can be reported against its line numbers.
+type apiConfig struct {
+	name     string
+	version  string
+	source   *yaml.Node
+	document map[string]interface{}
+	problems []apiConfigProblem
+}
+
+// newApiConfig(name, content, document) wraps the decoded JSON document of the API configuration file name,
+// converting OpenAPI 3.0 and 3.1 documents into swagger 2.0
+func newApiConfig(name string, content []byte, document map[string]interface{}) (*apiConfig, error) {
+	config := &apiConfig{name: name, document: document}
+
+	// YAML is a superset of JSON, so one parser gives line numbers for both formats
+	var source yaml.Node
+	if err := yaml.Unmarshal(content, &source); err != nil {
+		whisk.Debug(whisk.DbgWarn, "Unable to map '%s' to source lines: %s\n", name, err)
+	} else {
+		config.source = &source
+	}
+
+	if version, ok := document["openapi"]; ok {
+		config.version = fmt.Sprint(version)
+		if !strings.HasPrefix(config.version, openApiVersion3Prefix) {
+			whisk.Debug(whisk.DbgError, "Unsupported OpenAPI version '%s'\n", config.version)
+			errMsg := wski18n.T("OpenAPI version '{{.version}}' is not supported; specify an OpenAPI 3.0, OpenAPI 3.1 or swagger 2.0 document",
+				map[string]interface{}{"version": config.version})
+			return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+				whisk.NO_DISPLAY_USAGE)
+		}
+		whisk.Debug(whisk.DbgInfo, "Converting OpenAPI %s document into swagger %s\n", config.version, swaggerVersion2)
+		config.document = config.convertOpenApi3(document)
+	} else {
+		config.version = fmt.Sprint(document["swagger"])
+	}
+
+	return config, nil
+}
+
+// isOpenApi() reports whether the configuration file is an OpenAPI 3 document rather than swagger 2.0
+func (config *apiConfig) isOpenApi() bool {
+	return strings.HasPrefix(config.version, openApiVersion3Prefix)
+}
+
+// line(path...) returns the source line of the deepest node found along path, or 0 when unknown
+func (config *apiConfig) line(path ...interface{}) int {
+	if config.source == nil || len(config.source.Content) == 0 {
+		return 0
+	}
+
+	node := config.source.Content[0]
+	line := node.Line
+	for _, segment := range path {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == fmt.Sprint(segment) {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, ok := segment.(int); ok && index < len(node.Content) {
+				next = node.Content[index]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+
+	return line
+}
+
+func (config *apiConfig) addProblem(line int, message string) {
+	config.problems = append(config.problems, apiConfigProblem{line: line, message: message})
+}
+
+// err() combines the problems found so far into a single error, or returns nil when there are none
+func (config *apiConfig) err() error {
+	if len(config.problems) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(config.problems, func(i, j int) bool {
+		return config.problems[i].line < config.problems[j].line
+	})
+
+	var problems []string
+	for _, problem := range config.problems {
+		problems = append(problems, "  "+problem.String())
+	}
+
+	whisk.Debug(whisk.DbgError, "API configuration '%s' has %d problem(s)\n", config.name, len(problems))
+	errMsg := wski18n.T("API configuration file '{{.name}}' is invalid:\n{{.problems}}",
+		map[string]interface{}{"name": config.name, "problems": strings.Join(problems, "\n")})
+	return whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+		whisk.NO_DISPLAY_USAGE)
+}
+
+// swagger() returns the JSON encoding of the normalized swagger 2.0 document
+func (config *apiConfig) swagger() (string, error) {
+	swagger, err := json.Marshal(config.document)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "json.Marshal() error: %s\n", err)
+		errMsg := wski18n.T("Unable to convert '{{.name}}' into a swagger document: {{.err}}",
+			map[string]interface{}{"name": config.name, "err": err})
+		return "", whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+			whisk.NO_DISPLAY_USAGE)
+	}
+	return string(swagger), nil
+}
+
+///////////////////////////
+// OpenAPI 3 Conversion  //
+///////////////////////////
+
+// convertOpenApi3(document) maps servers, paths, components and x-openwhisk extensions of an OpenAPI 3
+// document onto their swagger 2.0 equivalents
+func (config *apiConfig) convertOpenApi3(document map[string]interface{}) map[string]interface{} {
+	swagger := map[string]interface{}{"swagger": swaggerVersion2}
+
+	for key, value := range document {
+		if key == "info" || key == "security" || key == "tags" || key == "externalDocs" || strings.HasPrefix(key, "x-") {
+			swagger[key] = rewriteOpenApiRefs(value)
+		}
+	}
+
+	if basePath, ok := config.openApiBasePath(document); ok {
+		swagger["basePath"] = basePath
+	}
+
+	components, _ := document["components"].(map[string]interface{})
+	if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+		swagger["definitions"] = rewriteOpenApiRefs(schemas)
+	}
+	if responses, ok := components["responses"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{})
+		for name, response := range responses {
+			converted[name] = convertOpenApiResponse(response, nil)
+		}
+		swagger["responses"] = converted
+	}
+	if schemes, ok := components["securitySchemes"].(map[string]interface{}); ok {
+		swagger["securityDefinitions"] = config.convertOpenApiSecuritySchemes(schemes)
+	}
+
+	if paths, ok := document["paths"].(map[string]interface{}); ok {
+		converted := make(map[string]interface{})
+		for path, item := range paths {
+			if item, ok := item.(map[string]interface{}); ok {
+				converted[path] = config.convertOpenApiPathItem(path, item, components)
+			}
+		}
+		swagger["paths"] = converted
+	}
+
+	return swagger
+}
+
+// openApiBasePath(document) derives the gateway base path from the first server URL, substituting server
+// variables with their default values
+func (config *apiConfig) openApiBasePath(document map[string]interface{}) (string, bool) {
+	servers, _ := document["servers"].([]interface{})
+	if len(servers) == 0 {
+		config.addProblem(config.line("openapi"), wski18n.T("OpenAPI documents must declare a server URL from which to take the API base path"))
+		return "", false
+	}
+
+	server, _ := servers[0].(map[string]interface{})
+	serverUrl, _ := server["url"].(string)
+	variables, _ := server["variables"].(map[string]interface{})
+	for name, variable := range variables {
+		if variable, ok := variable.(map[string]interface{}); ok {
+			serverUrl = strings.Replace(serverUrl, "{"+name+"}", fmt.Sprint(variable["default"]), -1)
+		}
+	}
+
+	parsedUrl, err := url.Parse(serverUrl)
+	if err != nil || serverUrl == "" {
+		config.addProblem(config.line("servers", 0, "url"),
+			wski18n.T("server URL '{{.url}}' is not a valid URL", map[string]interface{}{"url": serverUrl}))
+		return "", false
+	}
+
+	basePath := parsedUrl.Path
+	if basePath == "" {
+		basePath = "/"
+	}
+	return basePath, true
+}
+
+func (config *apiConfig) convertOpenApiPathItem(path string, item map[string]interface{}, components map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+
+	for key, value := range item {
+		switch {
+		case key == "parameters":
+			converted[key] = convertOpenApiParameters(value, components)
+		case isApiOperationVerb(key):
+			if operation, ok := value.(map[string]interface{}); ok {
+				converted[key] = convertOpenApiOperation(operation, components)
+			}
+		case key == "trace":
+			config.addProblem(config.line("paths", path, key),
+				wski18n.T("operation '{{.verb}}' is not supported by the API gateway", map[string]interface{}{"verb": key}))
+		case strings.HasPrefix(key, "x-"):
+			converted[key] = rewriteOpenApiRefs(value)
+		}
+	}
+
+	return converted
+}
+
+func convertOpenApiOperation(operation map[string]interface{}, components map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+
+	for key, value := range operation {
+		if key == "operationId" || key == "summary" || key == "description" || key == "tags" ||
+			key == "deprecated" || key == "security" || key == "externalDocs" || strings.HasPrefix(key, "x-") {
+			converted[key] = rewriteOpenApiRefs(value)
+		}
+	}
+
+	parameters := convertOpenApiParameters(operation["parameters"], components)
+
+	// A request body becomes the single "body" parameter of swagger 2.0
+	if requestBody, ok := resolveOpenApiRef(operation["requestBody"], components, "requestBodies").(map[string]interface{}); ok {
+		schema, mediaTypes := openApiContentSchema(requestBody["content"])
+		parameter := map[string]interface{}{"name": "body", "in": "body", "required": requestBody["required"] == true}
+		if description, ok := requestBody["description"]; ok {
+			parameter["description"] = description
+		}
+		if schema != nil {
+			parameter["schema"] = schema
+		}
+		parameters = append(parameters, parameter)
+		if len(mediaTypes) > 0 {
+			converted["consumes"] = mediaTypes
+		}
+	}
+
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+
+	var produces []string
+	responses := make(map[string]interface{})
+	if operationResponses, ok := operation["responses"].(map[string]interface{}); ok {
+		for status, response := range operationResponses {
+			var mediaTypes []string
+			responses[status] = convertOpenApiResponse(response, &mediaTypes)
+			for _, mediaType := range mediaTypes {
+				if !contains(produces, mediaType) {
+					produces = append(produces, mediaType)
+				}
+			}
+		}
+	}
+	converted["responses"] = responses
+	if len(produces) > 0 {
+		sort.Strings(produces)
+		converted["produces"] = produces
+	}
+
+	return converted
+}
+
+// convertOpenApiParameters(parameters, components) resolves parameter references and moves schema keywords
+// onto the parameters
+func convertOpenApiParameters(parameters interface{}, components map[string]interface{}) []interface{} {
+	converted := []interface{}{}
+
+	list, _ := parameters.([]interface{})
+	for _, parameter := range list {
+		parameter, ok := resolveOpenApiRef(parameter, components, "parameters").(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		convertedParameter := make(map[string]interface{})
+		for _, key := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+			if value, ok := parameter[key]; ok {
+				convertedParameter[key] = value
+			}
+		}
+		if schema, ok := parameter["schema"].(map[string]interface{}); ok {
+			for _, key := range openApiParamSchemaKeys {
+				if value, ok := schema[key]; ok {
+					convertedParameter[key] = rewriteOpenApiRefs(value)
+				}
+			}
+		}
+		if _, ok := convertedParameter["type"]; !ok {
+			convertedParameter["type"] = "string"
+		}
+		converted = append(converted, convertedParameter)
+	}
+
+	return converted
+}
+
+// convertOpenApiResponse(response, mediaTypes) converts a response object, appending the media types of its
+// content to mediaTypes when given
+func convertOpenApiResponse(response interface{}, mediaTypes *[]string) interface{} {
+	responseObj, ok := response.(map[string]interface{})
+	if !ok {
+		return response
+	}
+	if ref, ok := responseObj["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": strings.Replace(ref, openApiResponseRefPrefix, swaggerResponseRefPrefix, 1)}
+	}
+
+	converted := map[string]interface{}{"description": ""}
+	if description, ok := responseObj["description"]; ok {
+		converted["description"] = description
+	}
+
+	schema, types := openApiContentSchema(responseObj["content"])
+	if schema != nil {
+		converted["schema"] = schema
+	}
+	if mediaTypes != nil {
+		*mediaTypes = append(*mediaTypes, types...)
+	}
+
+	if headers, ok := responseObj["headers"].(map[string]interface{}); ok {
+		convertedHeaders := make(map[string]interface{})
+		for name, header := range headers {
+			convertedHeader := make(map[string]interface{})
+			if header, ok := header.(map[string]interface{}); ok {
+				if description, ok := header["description"]; ok {
+					convertedHeader["description"] = description
+				}
+				if schema, ok := header["schema"].(map[string]interface{}); ok {
+					for _, key := range openApiParamSchemaKeys {
+						if value, ok := schema[key]; ok {
+							convertedHeader[key] = value
+						}
+					}
+				}
+			}
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+
+	return converted
+}
+
+// openApiContentSchema(content) returns the schema of the preferred media type of a content map, favouring
+// JSON, along with all of its media types
+func openApiContentSchema(content interface{}) (interface{}, []string) {
+	contentObj, ok := content.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var mediaTypes []string
+	for mediaType := range contentObj {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	if len(mediaTypes) == 0 {
+		return nil, nil
+	}
+
+	preferred := mediaTypes[0]
+	if _, ok := contentObj["application/json"]; ok {
+		preferred = "application/json"
+	}
+
+	var schema interface{}
+	if media, ok := contentObj[preferred].(map[string]interface{}); ok {
+		schema = rewriteOpenApiRefs(media["schema"])
+	}
+	return schema, mediaTypes
+}
+
+func (config *apiConfig) convertOpenApiSecuritySchemes(schemes map[string]interface{}) map[string]interface{} {
+	definitions := make(map[string]interface{})
+
+	for name, scheme := range schemes {
+		schemeObj, _ := scheme.(map[string]interface{})
+		schemeType, _ := schemeObj["type"].(string)
+		definition := make(map[string]interface{})
+
+		switch {
+		case schemeType == "apiKey":
+			for key, value := range schemeObj {
+				definition[key] = value
+			}
+		case schemeType == "http" && strings.EqualFold(fmt.Sprint(schemeObj["scheme"]), "basic"):
+			definition["type"] = "basic"
+		case schemeType == "oauth2":
+			flows, _ := schemeObj["flows"].(map[string]interface{})
+			for _, flow := range openApiOAuthFlows {
+				if flowObj, ok := flows[flow[0]].(map[string]interface{}); ok {
+					definition["type"] = "oauth2"
+					definition["flow"] = flow[1]
+					for _, key := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+						if value, ok := flowObj[key]; ok {
+							definition[key] = value
+						}
+					}
+					break
+				}
+			}
+		}
+
+		if len(definition) == 0 {
+			config.addProblem(config.line("components", "securitySchemes", name),
+				wski18n.T("security scheme '{{.name}}' of type '{{.type}}' cannot be converted for the API gateway",
+					map[string]interface{}{"name": name, "type": schemeType}))
+			continue
+		}
+		if description, ok := schemeObj["description"]; ok {
+			definition["description"] = description
+		}
+		definitions[name] = definition
+	}
+
+	return definitions
+}
+
+// resolveOpenApiRef(value, components, section) replaces a local reference into the given components section
+// with the referenced object
+func resolveOpenApiRef(value interface{}, components map[string]interface{}, section string) interface{} {
+	valueObj, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	ref, ok := valueObj["$ref"].(string)
+	prefix := "#/components/" + section + "/"
+	if !ok || !strings.HasPrefix(ref, prefix) {
+		return value
+	}
+
+	sectionObj, _ := components[section].(map[string]interface{})
+	if resolved, ok := sectionObj[strings.TrimPrefix(ref, prefix)]; ok {
+		return resolved
+	}
+	return value
+}
+
+// rewriteOpenApiRefs(value) copies value, pointing schema references at swagger 2.0 definitions
+func rewriteOpenApiRefs(value interface{}) interface{} {
+	return rewriteRefs(value, openApiSchemaRefPrefix, swaggerDefinitionRefPrefix)
+}
+
+// rewriteSwaggerRefs(value) copies value, pointing definition references at OpenAPI 3 component schemas
+func rewriteSwaggerRefs(value interface{}) interface{} {
+	return rewriteRefs(value, swaggerDefinitionRefPrefix, openApiSchemaRefPrefix)
+}
+
+func rewriteRefs(value interface{}, from string, to string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				copied[key] = strings.Replace(ref, from, to, 1)
+			} else {
+				copied[key] = rewriteRefs(item, from, to)
+			}
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(value))
+		for i, item := range value {
+			copied[i] = rewriteRefs(item, from, to)
+		}
+		return copied
+	}
+	return value
+}
+
+func isApiOperationVerb(key string) bool {
+	return contains(apiOperationVerbs, key)
+}
+
+////////////
+// Export //
+////////////
+
+// readApiDocuments(resp) returns the swagger documents of the APIs in the raw body of an API list or get
+// response, keeping every field the gateway stored
+func readApiDocuments(resp *http.Response) ([]map[string]interface{}, error) {
+	var apis struct {
+		Apis []struct {
+			Value struct {
+				Apidoc map[string]interface{} `json:"apidoc"`
+			} `json:"value"`
+		} `json:"apis"`
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&apis); err != nil {
+		whisk.Debug(whisk.DbgError, "json.Decode() of the API response error: %s\n", err)
+		return nil, err
+	}
+
+	var documents []map[string]interface{}
+	for _, api := range apis.Apis {
+		if api.Value.Apidoc != nil {
+			documents = append(documents, api.Value.Apidoc)
+		}
+	}
+	return documents, nil
+}
+
+// exportApiDocument(swagger, format) strips the gateway generated x-ibm extensions from the swagger document
+// of a deployed API and returns it as a swagger 2.0 or OpenAPI 3 document that can be given back to api create
+// --config-file
+func exportApiDocument(swagger map[string]interface{}, format string) map[string]interface{} {
+	document := stripGatewayExtensions(swagger).(map[string]interface{})
+	if format == apiExportFormatOpenApi2 {
+		return document
+	}
+	return convertSwaggerToOpenApi3(document)
+}
+
+// stripGatewayExtensions(value) returns a copy of value without the x-ibm extensions the gateway adds at
+// any level, such as x-ibm-configuration and x-ibm-rate-limit
+func stripGatewayExtensions(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		stripped := make(map[string]interface{})
+		for key, item := range value {
+			if !strings.HasPrefix(key, apiGatewayExtensionPrefix) {
+				stripped[key] = stripGatewayExtensions(item)
+			}
+		}
+		return stripped
+	case []interface{}:
+		stripped := make([]interface{}, len(value))
+		for i, item := range value {
+			stripped[i] = stripGatewayExtensions(item)
+		}
+		return stripped
+	}
+	return value
+}
+
+// convertSwaggerToOpenApi3(document) maps the base path, paths, definitions and security definitions of a
+// swagger 2.0 document onto their OpenAPI 3 equivalents
+func convertSwaggerToOpenApi3(document map[string]interface{}) map[string]interface{} {
+	openApi := map[string]interface{}{"openapi": openApiExportVersion}
+
+	for key, value := range document {
+		if key == "info" || key == "security" || key == "tags" || key == "externalDocs" || strings.HasPrefix(key, "x-") {
+			openApi[key] = rewriteSwaggerRefs(value)
+		}
+	}
+
+	// A relative server URL keeps the base path without tying the document to one gateway host
+	if basePath, ok := document["basePath"].(string); ok {
+		openApi["servers"] = []interface{}{map[string]interface{}{"url": basePath}}
+	}
+
+	components := make(map[string]interface{})
+	if definitions, ok := document["definitions"].(map[string]interface{}); ok {
+		components["schemas"] = rewriteSwaggerRefs(definitions)
+	}
+	if definitions, ok := document["securityDefinitions"].(map[string]interface{}); ok {
+		components["securitySchemes"] = convertSwaggerSecurityDefinitions(definitions)
+	}
+	if len(components) > 0 {
+		openApi["components"] = components
+	}
+
+	paths := make(map[string]interface{})
+	if swaggerPaths, ok := document["paths"].(map[string]interface{}); ok {
+		for path, item := range swaggerPaths {
+			itemObj, _ := item.(map[string]interface{})
+			converted := make(map[string]interface{})
+			for key, value := range itemObj {
+				switch {
+				case key == "parameters":
+					parameters, _ := convertSwaggerParameters(value, nil)
+					converted[key] = parameters
+				case isApiOperationVerb(key):
+					if operation, ok := value.(map[string]interface{}); ok {
+						converted[key] = convertSwaggerOperation(operation)
+					}
+				case strings.HasPrefix(key, "x-"):
+					converted[key] = rewriteSwaggerRefs(value)
+				}
+			}
+			paths[path] = converted
+		}
+	}
+	openApi["paths"] = paths
+
+	return openApi
+}
+
+func convertSwaggerOperation(operation map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{})
+
+	for key, value := range operation {
+		if key == "operationId" || key == "summary" || key == "description" || key == "tags" ||
+			key == "deprecated" || key == "security" || key == "externalDocs" || strings.HasPrefix(key, "x-") {
+			converted[key] = rewriteSwaggerRefs(value)
+		}
+	}
+
+	consumes := swaggerMediaTypes(operation["consumes"])
+	parameters, requestBody := convertSwaggerParameters(operation["parameters"], consumes)
+	if len(parameters) > 0 {
+		converted["parameters"] = parameters
+	}
+	if requestBody != nil {
+		converted["requestBody"] = requestBody
+	}
+
+	produces := swaggerMediaTypes(operation["produces"])
+	responses := make(map[string]interface{})
+	if swaggerResponses, ok := operation["responses"].(map[string]interface{}); ok {
+		for status, response := range swaggerResponses {
+			responses[status] = convertSwaggerResponse(response, produces)
+		}
+	}
+	if len(responses) == 0 {
+		responses["default"] = map[string]interface{}{"description": ""}
+	}
+	converted["responses"] = responses
+
+	return converted
+}
+
+// convertSwaggerParameters(parameters, consumes) moves parameter schema keywords into a schema object and
+// folds body and form parameters into a request body
+func convertSwaggerParameters(parameters interface{}, consumes []string) ([]interface{}, map[string]interface{}) {
+	converted := []interface{}{}
+	var requestBody map[string]interface{}
+	formSchema := map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	var formRequired []interface{}
+
+	list, _ := parameters.([]interface{})
+	for _, parameter := range list {
+		parameterObj, ok := parameter.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		schema := make(map[string]interface{})
+		for _, key := range openApiParamSchemaKeys {
+			if value, ok := parameterObj[key]; ok {
+				schema[key] = rewriteSwaggerRefs(value)
+			}
+		}
+
+		switch parameterObj["in"] {
+		case "body":
+			requestBody = map[string]interface{}{"content": openApiContent(parameterObj["schema"], consumes)}
+			if description, ok := parameterObj["description"]; ok {
+				requestBody["description"] = description
+			}
+			if parameterObj["required"] == true {
+				requestBody["required"] = true
+			}
+		case "formData":
+			name := fmt.Sprint(parameterObj["name"])
+			formSchema["properties"].(map[string]interface{})[name] = schema
+			if parameterObj["required"] == true {
+				formRequired = append(formRequired, name)
+			}
+		default:
+			convertedParameter := make(map[string]interface{})
+			for _, key := range []string{"name", "in", "description", "required", "allowEmptyValue"} {
+				if value, ok := parameterObj[key]; ok {
+					convertedParameter[key] = value
+				}
+			}
+			if len(schema) > 0 {
+				convertedParameter["schema"] = schema
+			}
+			converted = append(converted, convertedParameter)
+		}
+	}
+
+	if len(formSchema["properties"].(map[string]interface{})) > 0 && requestBody == nil {
+		if len(formRequired) > 0 {
+			formSchema["required"] = formRequired
+		}
+		requestBody = map[string]interface{}{
+			"content": map[string]interface{}{"application/x-www-form-urlencoded": map[string]interface{}{"schema": formSchema}},
+		}
+	}
+
+	return converted, requestBody
+}
+
+func convertSwaggerResponse(response interface{}, produces []string) interface{} {
+	responseObj, ok := response.(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{"description": ""}
+	}
+	if ref, ok := responseObj["$ref"].(string); ok {
+		return map[string]interface{}{"$ref": strings.Replace(ref, swaggerResponseRefPrefix, openApiResponseRefPrefix, 1)}
+	}
+
+	converted := map[string]interface{}{"description": ""}
+	if description, ok := responseObj["description"]; ok {
+		converted["description"] = description
+	}
+	if schema, ok := responseObj["schema"]; ok {
+		converted["content"] = openApiContent(schema, produces)
+	}
+
+	if headers, ok := responseObj["headers"].(map[string]interface{}); ok {
+		convertedHeaders := make(map[string]interface{})
+		for name, header := range headers {
+			convertedHeader := make(map[string]interface{})
+			if headerObj, ok := header.(map[string]interface{}); ok {
+				schema := make(map[string]interface{})
+				for key, value := range headerObj {
+					if key == "description" {
+						convertedHeader[key] = value
+					} else {
+						schema[key] = value
+					}
+				}
+				convertedHeader["schema"] = schema
+			}
+			convertedHeaders[name] = convertedHeader
+		}
+		converted["headers"] = convertedHeaders
+	}
+
+	return converted
+}
+
+func convertSwaggerSecurityDefinitions(definitions map[string]interface{}) map[string]interface{} {
+	schemes := make(map[string]interface{})
+
+	for name, definition := range definitions {
+		definitionObj, _ := definition.(map[string]interface{})
+		scheme := make(map[string]interface{})
+
+		switch definitionObj["type"] {
+		case "basic":
+			scheme["type"] = "http"
+			scheme["scheme"] = "basic"
+		case "oauth2":
+			flow := make(map[string]interface{})
+			for _, key := range []string{"authorizationUrl", "tokenUrl", "scopes"} {
+				if value, ok := definitionObj[key]; ok {
+					flow[key] = value
+				}
+			}
+			if _, ok := flow["scopes"]; !ok {
+				flow["scopes"] = map[string]interface{}{}
+			}
+			flowName := fmt.Sprint(definitionObj["flow"])
+			for _, oauthFlow := range openApiOAuthFlows {
+				if oauthFlow[1] == flowName {
+					flowName = oauthFlow[0]
+				}
+			}
+			scheme["type"] = "oauth2"
+			scheme["flows"] = map[string]interface{}{flowName: flow}
+		default:
+			for key, value := range definitionObj {
+				scheme[key] = value
+			}
+		}
+
+		if description, ok := definitionObj["description"]; ok {
+			scheme["description"] = description
+		}
+		schemes[name] = scheme
+	}
+
+	return schemes
+}
+
+// openApiContent(schema, mediaTypes) builds an OpenAPI 3 content map offering schema for each media type,
+// defaulting to JSON
+func openApiContent(schema interface{}, mediaTypes []string) map[string]interface{} {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+
+	content := make(map[string]interface{})
+	for _, mediaType := range mediaTypes {
+		media := make(map[string]interface{})
+		if schema != nil {
+			media["schema"] = rewriteSwaggerRefs(schema)
+		}
+		content[mediaType] = media
+	}
+	return content
+}
+
+func swaggerMediaTypes(value interface{}) []string {
+	var mediaTypes []string
+	list, _ := value.([]interface{})
+	for _, mediaType := range list {
+		mediaTypes = append(mediaTypes, fmt.Sprint(mediaType))
+	}
+	return mediaTypes
+}
+
+////////////////
+// Validation //
+////////////////
+
+// validate() checks the operation IDs, paths and path parameters of the normalized document
+func (config *apiConfig) validate() {
+	paths, _ := config.document["paths"].(map[string]interface{})
+	operationIds := make(map[string]int)
+
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]interface{})
+
+		if _, ok := isValidRelpath(path); !ok {
+			config.addProblem(config.line("paths", path),
+				wski18n.T("path '{{.path}}' must begin with '/'", map[string]interface{}{"path": path}))
+		}
+
+		templateParams, _ := getPathParameterNames(path)
+		itemParams := config.pathParameters(item["parameters"], "paths", path, "parameters")
+
+		for _, verb := range apiOperationVerbs {
+			operation, ok := item[verb].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			operationLine := config.line("paths", path, verb)
+
+			operationId, _ := operation["operationId"].(string)
+			if operationId == "" {
+				config.addProblem(operationLine,
+					wski18n.T("operation {{.verb}} {{.path}} has no operationId", map[string]interface{}{"verb": verb, "path": path}))
+			} else if firstLine, ok := operationIds[operationId]; ok {
+				config.addProblem(config.line("paths", path, verb, "operationId"),
+					wski18n.T("operationId '{{.id}}' is already used by the operation on line {{.line}}",
+						map[string]interface{}{"id": operationId, "line": firstLine}))
+			} else {
+				operationIds[operationId] = operationLine
+			}
+
+			declared := make(map[string]int)
+			for name, line := range itemParams {
+				declared[name] = line
+			}
+			for name, line := range config.pathParameters(operation["parameters"], "paths", path, verb, "parameters") {
+				declared[name] = line
+			}
+
+			for _, name := range templateParams {
+				if _, ok := declared[name]; !ok {
+					config.addProblem(operationLine,
+						wski18n.T("path parameter '{{.name}}' of {{.verb}} {{.path}} is not declared with \"in: path\"",
+							map[string]interface{}{"name": name, "verb": verb, "path": path}))
+				}
+			}
+			var declaredNames []string
+			for name := range declared {
+				declaredNames = append(declaredNames, name)
+			}
+			sort.Strings(declaredNames)
+			for _, name := range declaredNames {
+				if !contains(templateParams, name) {
+					config.addProblem(declared[name],
+						wski18n.T("path parameter '{{.name}}' of {{.verb}} {{.path}} does not appear in the path",
+							map[string]interface{}{"name": name, "verb": verb, "path": path}))
+				}
+			}
+
+			if xOpenWhisk, ok := operation["x-openwhisk"].(map[string]interface{}); ok {
+				if action, _ := xOpenWhisk["action"].(string); action == "" {
+					config.addProblem(config.line("paths", path, verb, "x-openwhisk"),
+						wski18n.T("x-openwhisk of {{.verb}} {{.path}} does not name an action", map[string]interface{}{"verb": verb, "path": path}))
+				}
+			}
+		}
+	}
+}
+
+// warnDuplicateOperationIds() prints a warning for every operation ID of the normalized document that is
+// already used by another operation
+func (config *apiConfig) warnDuplicateOperationIds() {
+	paths, _ := config.document["paths"].(map[string]interface{})
+	operationIds := make(map[string]int)
+
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, verb := range apiOperationVerbs {
+			operation, _ := item[verb].(map[string]interface{})
+			operationId, _ := operation["operationId"].(string)
+			if operationId == "" {
+				continue
+			}
+
+			if firstLine, ok := operationIds[operationId]; ok {
+				problem := apiConfigProblem{
+					line: config.line("paths", path, verb, "operationId"),
+					message: wski18n.T("operationId '{{.id}}' is already used by the operation on line {{.line}}",
+						map[string]interface{}{"id": operationId, "line": firstLine}),
+				}
+				printWarning(problem.String())
+			} else {
+				operationIds[operationId] = config.line("paths", path, verb)
+			}
+		}
+	}
+}
+
+// pathParameters(parameters, location...) returns the names of the "in: path" parameters in a parameter
+// list mapped to their source lines, resolving references to swagger 2.0 parameter definitions
+func (config *apiConfig) pathParameters(parameters interface{}, location ...interface{}) map[string]int {
+	params := make(map[string]int)
+	definitions, _ := config.document["parameters"].(map[string]interface{})
+
+	list, _ := parameters.([]interface{})
+	for i, parameter := range list {
+		parameterObj, _ := parameter.(map[string]interface{})
+		if ref, ok := parameterObj["$ref"].(string); ok && strings.HasPrefix(ref, swaggerParamRefPrefix) {
+			parameterObj, _ = definitions[strings.TrimPrefix(ref, swaggerParamRefPrefix)].(map[string]interface{})
+		}
+		if parameterObj["in"] != "path" {
+			continue
+		}
+
+		name, _ := parameterObj["name"].(string)
+		line := config.line(append(location, i)...)
+		params[name] = line
+		if parameterObj["required"] != true {
+			config.addProblem(line,
+				wski18n.T("path parameter '{{.name}}' must be required", map[string]interface{}{"name": name}))
+		}
+	}
+
+	return params
+}
+
+// validateActions(client, namespace) confirms that every action referenced through an x-openwhisk extension
+// exists and is a web action
+func (config *apiConfig) validateActions(client *whisk.Client, namespace string) {
+	checked := make(map[string]error)
+	paths, _ := config.document["paths"].(map[string]interface{})
+
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, verb := range apiOperationVerbs {
+			operation, _ := item[verb].(map[string]interface{})
+			xOpenWhisk, ok := operation["x-openwhisk"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name := xOpenWhiskActionName(xOpenWhisk, namespace)
+			if name == "" {
+				continue
+			}
+
+			err, ok := checked[name]
+			if !ok {
+				var qname *QualifiedName
+				if qname, err = NewQualifiedName(name); err == nil {
+					_, err = isWebAction(client, *qname)
+				}
+				checked[name] = err
+			}
+			if err != nil {
+				config.addProblem(config.line("paths", path, verb, "x-openwhisk"), err.Error())
+			}
+		}
+	}
+}
+
+// xOpenWhiskActionName(xOpenWhisk, namespace) returns the fully qualified name of the action referenced by
+// an x-openwhisk extension, defaulting to namespace
+func xOpenWhiskActionName(xOpenWhisk map[string]interface{}, namespace string) string {
+	action, _ := xOpenWhisk["action"].(string)
+	if action == "" {
+		return ""
+	}
+
+	actionNamespace, _ := xOpenWhisk["namespace"].(string)
+	if actionNamespace == "" {
+		actionNamespace = namespace
+	}
+	if actionNamespace == "" {
+		actionNamespace = "_"
+	}
+
+	if pkg, _ := xOpenWhisk["package"].(string); pkg != "" {
+		return "/" + actionNamespace + "/" + pkg + "/" + action
+	}
+	return "/" + actionNamespace + "/" + action
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,290 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+const openApiTestDocument = `openapi: 3.0.3
+info:
+  title: books
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/{stage}/books
+    variables:
+      stage:
+        default: v1
+paths:
+  /{id}:
+    parameters:
+      - $ref: '#/components/parameters/id'
+    get:
+      operationId: getBook
+      summary: Get a book
+      tags: [books]
+      responses:
+        '200':
+          description: a book
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Book'
+      x-openwhisk:
+        namespace: guest
+        package: library
+        action: get-book
+        url: https://openwhisk.example.com/api/v1/web/guest/library/get-book.json
+    put:
+      operationId: putBook
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Book'
+      responses:
+        '204':
+          description: stored
+components:
+  parameters:
+    id:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+  schemas:
+    Book:
+      type: object
+`
+
+func writeApiTestFile(t *testing.T, name string, content string) string {
+	dir, err := ioutil.TempDir("", "wsk-api")
+	assert.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	file := filepath.Join(dir, name)
+	assert.Nil(t, ioutil.WriteFile(file, []byte(content), 0644))
+	return file
+}
+
+func TestParseSwaggerApiOpenApi3(t *testing.T) {
+	api, config, err := parseSwaggerApi(writeApiTestFile(t, "books.yaml", openApiTestDocument), "guest")
+	assert.Nil(t, err)
+	assert.NotNil(t, config)
+
+	swagger := new(whisk.ApiSwagger)
+	assert.Nil(t, json.Unmarshal([]byte(api.Swagger), swagger))
+	assert.Equal(t, "2.0", swagger.SwaggerName)
+	assert.Equal(t, "/v1/books", swagger.BasePath)
+	assert.Equal(t, "books", swagger.Info.Title)
+
+	get := swagger.Paths["/{id}"].Get
+	assert.Equal(t, "getBook", get.OperationId)
+	assert.Equal(t, "get-book", get.XOpenWhisk.ActionName)
+	assert.Equal(t, "library", get.XOpenWhisk.Package)
+	assert.Equal(t, []whisk.ApiParameter{{Name: "id", In: "path", Required: true, Type: "integer"}}, swagger.Paths["/{id}"].Parameters)
+
+	put := swagger.Paths["/{id}"].Put
+	assert.Equal(t, "body", put.Parameters[0].Name)
+	assert.Equal(t, "body", put.Parameters[0].In)
+
+	var document map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(api.Swagger), &document))
+	assert.Contains(t, document["definitions"], "Book")
+	assert.Contains(t, api.Swagger, `"$ref":"#/definitions/Book"`)
+	assert.NotContains(t, api.Swagger, "#/components/")
+
+	xOpenWhisk := map[string]interface{}{"namespace": "guest", "package": "library", "action": "get-book"}
+	assert.Equal(t, "/guest/library/get-book", xOpenWhiskActionName(xOpenWhisk, "other"))
+	assert.Equal(t, "/other/hello", xOpenWhiskActionName(map[string]interface{}{"action": "hello"}, "other"))
+	assert.Equal(t, "", xOpenWhiskActionName(map[string]interface{}{"namespace": "guest"}, "other"))
+}
+
+func TestParseSwaggerApiProblems(t *testing.T) {
+	document := `{
+  "openapi": "3.0.3",
+  "servers": [{"url": "/books"}],
+  "info": {"title": "books", "version": "1.0"},
+  "paths": {
+    "/{id}": {
+      "get": {
+        "operationId": "getBook",
+        "responses": {}
+      },
+      "put": {
+        "operationId": "getBook",
+        "parameters": [{"name": "isbn", "in": "path", "required": true}],
+        "responses": {},
+        "x-openwhisk": {"namespace": "guest"}
+      },
+      "delete": {
+        "responses": {}
+      }
+    }
+  }
+}
+`
+	_, _, err := parseSwaggerApi(writeApiTestFile(t, "books.json", document), "guest")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "line 7: path parameter 'id' of get /{id} is not declared")
+	assert.Contains(t, err.Error(), "line 11: path parameter 'id' of put /{id} is not declared")
+	assert.Contains(t, err.Error(), "line 12: operationId 'getBook' is already used by the operation on line 7")
+	assert.Contains(t, err.Error(), "line 13: path parameter 'isbn' of put /{id} does not appear in the path")
+	assert.Contains(t, err.Error(), "line 15: x-openwhisk of put /{id} does not name an action")
+	assert.Contains(t, err.Error(), "line 17: operation delete /{id} has no operationId")
+}
+
+func TestParseSwaggerApiSwagger2(t *testing.T) {
+	// Swagger 2.0 files are not held to the OpenAPI 3 checks, so operation IDs and path parameter
+	// declarations are optional
+	document := `{
+  "swagger": "2.0",
+  "basePath": "/books",
+  "info": {"title": "books", "version": "1.0"},
+  "paths": {
+    "/{id}": {
+      "get": {
+        "responses": {},
+        "x-openwhisk": {"namespace": "guest", "action": "get-book"}
+      },
+      "put": {
+        "operationId": "putBook",
+        "responses": {}
+      },
+      "delete": {
+        "operationId": "putBook",
+        "responses": {}
+      }
+    }
+  }
+}
+`
+	api, _, err := parseSwaggerApi(writeApiTestFile(t, "books.json", document), "guest")
+	assert.Nil(t, err)
+
+	swagger := new(whisk.ApiSwagger)
+	assert.Nil(t, json.Unmarshal([]byte(api.Swagger), swagger))
+	assert.Equal(t, "", swagger.Paths["/{id}"].Get.OperationId)
+	assert.Equal(t, "get-book", swagger.Paths["/{id}"].Get.XOpenWhisk.ActionName)
+}
+
+func TestApiCreateConfigActions(t *testing.T) {
+	actionGets, creates := 0, 0
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/actions/") {
+			actionGets++
+			json.NewEncoder(w).Encode(map[string]interface{}{"namespace": "guest/library", "name": "get-book"})
+			return
+		}
+		creates++
+		json.NewEncoder(w).Encode(map[string]interface{}{"gwApiUrl": "https://gateway.example.com/guest",
+			"apidoc": map[string]interface{}{"basePath": "/books", "paths": map[string]interface{}{}}})
+	})
+
+	savedFlags, savedContextId, savedAccessToken := Flags, ContextId, ApiGwAccessToken
+	t.Cleanup(func() { Flags, ContextId, ApiGwAccessToken = savedFlags, savedContextId, savedAccessToken })
+	ContextId, ApiGwAccessToken = "user", "token"
+
+	// A swagger 2.0 file is created as it always was, even when it points at an action that is not a web action
+	Flags.api.configfile = writeApiTestFile(t, "books.json", `{
+  "swagger": "2.0",
+  "basePath": "/books",
+  "info": {"title": "books", "version": "1.0"},
+  "paths": {"/{id}": {"get": {"responses": {}, "x-openwhisk": {"namespace": "guest", "package": "library", "action": "get-book"}}}}
+}`)
+	assert.Nil(t, apiCreateCmd.RunE(apiCreateCmd, []string{}))
+	assert.Equal(t, 0, actionGets)
+	assert.Equal(t, 1, creates)
+
+	// The actions of an OpenAPI 3 file must be web actions
+	Flags.api.configfile = writeApiTestFile(t, "books.yaml", openApiTestDocument)
+	err := apiCreateCmd.RunE(apiCreateCmd, []string{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is not a web action")
+	assert.Equal(t, 1, actionGets)
+	assert.Equal(t, 1, creates)
+}
+
+func TestParseSwaggerApiOpenApiVersions(t *testing.T) {
+	_, _, err := parseSwaggerApi(writeApiTestFile(t, "api.yaml", "openapi: 4.0.0\ninfo: {}\npaths: {}\n"), "guest")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "OpenAPI version '4.0.0' is not supported")
+
+	_, _, err = parseSwaggerApi(writeApiTestFile(t, "api.yaml", "openapi: 3.1.0\ninfo:\n  title: t\n  version: '1'\npaths: {}\n"), "guest")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "line 1: OpenAPI documents must declare a server URL")
+}
+
+func TestExportApiDocumentRoundTrip(t *testing.T) {
+	created, _, err := parseSwaggerApi(writeApiTestFile(t, "books.yaml", openApiTestDocument), "guest")
+	assert.Nil(t, err)
+
+	// The gateway stores the swagger document with its own extensions added
+	var stored map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(created.Swagger), &stored))
+	stored["x-ibm-configuration"] = map[string]interface{}{"assembly": map[string]interface{}{}}
+	stored["x-ibm-rate-limit"] = []interface{}{map[string]interface{}{"rate": 100}}
+	body, err := json.Marshal(map[string]interface{}{
+		"apis": []interface{}{map[string]interface{}{"id": "books", "value": map[string]interface{}{"apidoc": stored}}},
+	})
+	assert.Nil(t, err)
+
+	documents, err := readApiDocuments(&http.Response{Body: ioutil.NopCloser(bytes.NewReader(body))})
+	assert.Nil(t, err)
+	assert.Len(t, documents, 1)
+
+	swagger := exportApiDocument(documents[0], apiExportFormatOpenApi2)
+	assert.NotContains(t, swagger, "x-ibm-configuration")
+	assert.NotContains(t, swagger, "x-ibm-rate-limit")
+	assert.Contains(t, swagger["definitions"], "Book")
+	assert.Contains(t, documents[0], "x-ibm-configuration")
+
+	openApi := exportApiDocument(documents[0], apiExportFormatOpenApi3)
+	assert.Equal(t, openApiExportVersion, openApi["openapi"])
+	assert.NotContains(t, openApi, "x-ibm-configuration")
+	assert.NotContains(t, openApi, "x-ibm-rate-limit")
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "/v1/books"}}, openApi["servers"])
+	assert.Contains(t, openApi["components"].(map[string]interface{})["schemas"], "Book")
+
+	for _, document := range []map[string]interface{}{swagger, openApi} {
+		content, err := json.Marshal(document)
+		assert.Nil(t, err)
+		assert.NotContains(t, string(content), "x-ibm-")
+		recreated, _, err := parseSwaggerApi(writeApiTestFile(t, "books.json", string(content)), "guest")
+		assert.Nil(t, err)
+
+		var original, imported map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(created.Swagger), &original))
+		assert.Nil(t, json.Unmarshal([]byte(recreated.Swagger), &imported))
+		assert.Equal(t, original, imported)
+	}
+}
@@ -313,15 +313,19 @@ var packageGetCmd = &cobra.Command{
 			return werr
 		}
 
-		if Flags.common.summary {
+		if len(Flags.common.template) > 0 {
+			return printTemplate(xPackage, Flags.common.template)
+		} else if Flags.common.summary {
 			printSummary(xPackage)
 		} else {
 
 			if len(field) > 0 {
-				fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got package {{.name}}, displaying field {{.field}}\n",
-					map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName()),
-						"field": boldString(field)}))
-				printField(xPackage, field)
+				if !printField(xPackage, field) {
+					errMsg := wski18n.T("Invalid field filter '{{.arg}}'.", map[string]interface{}{"arg": field})
+					whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+						whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+					return whiskErr
+				}
 			} else {
 				fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got package {{.name}}\n",
 					map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName())}))
@@ -400,27 +404,60 @@ var packageListCmd = &cobra.Command{
 			Client.Namespace = qualifiedName.GetNamespace()
 		}
 
-		options := &whisk.PackageListOptions{
-			Skip:  Flags.common.skip,
-			Limit: Flags.common.limit,
+		var packages []whisk.Package
+		query, err := newListQuery(Flags.common.filter, Flags.common.sortBy)
+		if err != nil {
+			return err
 		}
 
-		packages, _, err := Client.Packages.List(options)
-		if err != nil {
-			whisk.Debug(whisk.DbgError, "Client.Packages.List(%+v) failed: %s\n", options, err)
-			errStr := wski18n.T("Unable to obtain the list of packages for namespace '{{.name}}': {{.err}}",
-				map[string]interface{}{"name": getClientNamespace(), "err": err})
-			werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-			return werr
+		// An explicit --sort-by order takes precedence over --name-sort
+		sortByName := Flags.common.nameSort && !query.sorted()
+
+		if Flags.common.all {
+			// Stream each page as it arrives unless the complete set is needed for sorting
+			stream := !sortByName && !query.sorted()
+			err = listAllPages(Flags.common.skip, func(skip int, limit int) (int, error) {
+				page, err := listPackages(&whisk.PackageListOptions{Skip: skip, Limit: limit})
+				if err != nil {
+					return 0, err
+				}
+
+				if stream {
+					printListPage(query.apply(page), skip == Flags.common.skip)
+				} else {
+					packages = append(packages, page...)
+				}
+				return len(page), nil
+			})
+			if err != nil || stream {
+				return err
+			}
+		} else {
+			packages, err = listPackages(&whisk.PackageListOptions{Skip: Flags.common.skip, Limit: Flags.common.limit})
+			if err != nil {
+				return err
+			}
 		}
 
-		sortByName := Flags.common.nameSort
-		printList(packages, sortByName)
+		printList(query.apply(packages), sortByName)
 
 		return nil
 	},
 }
 
+func listPackages(options *whisk.PackageListOptions) ([]whisk.Package, error) {
+	packages, _, err := Client.Packages.List(options)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Packages.List(%+v) failed: %s\n", options, err)
+		errStr := wski18n.T("Unable to obtain the list of packages for namespace '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": getClientNamespace(), "err": err})
+		werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		return nil, werr
+	}
+
+	return packages, nil
+}
+
 var packageRefreshCmd = &cobra.Command{
 	Use:           "refresh [NAMESPACE]",
 	Short:         wski18n.T("refresh package bindings"),
@@ -519,6 +556,8 @@ func init() {
 	packageUpdateCmd.Flags().StringVar(&Flags.common.shared, "shared", "", wski18n.T("package visibility `SCOPE`; yes = shared, no = private"))
 
 	packageGetCmd.Flags().BoolVarP(&Flags.common.summary, "summary", "s", false, wski18n.T("summarize package details; parameters with prefix \"*\" are bound"))
+	packageGetCmd.Flags().StringVar(&Flags.common.template, "template", "", wski18n.T("format the package with the Go `TEMPLATE`, e.g. {{.example}}",
+		map[string]interface{}{"example": "'{{.name}} {{.binding.name}}'"}))
 
 	packageBindCmd.Flags().StringSliceVarP(&Flags.common.annotation, "annotation", "a", []string{}, wski18n.T("annotation values in `KEY VALUE` format"))
 	packageBindCmd.Flags().StringVarP(&Flags.common.annotFile, "annotation-file", "A", "", wski18n.T("`FILE` containing annotation values in JSON format"))
@@ -529,6 +568,9 @@ func init() {
 	packageListCmd.Flags().IntVarP(&Flags.common.skip, "skip", "s", 0, wski18n.T("exclude the first `SKIP` number of packages from the result"))
 	packageListCmd.Flags().IntVarP(&Flags.common.limit, "limit", "l", 30, wski18n.T("only return `LIMIT` number of packages from the collection"))
 	packageListCmd.Flags().BoolVarP(&Flags.common.nameSort, "name-sort", "n", false, wski18n.T("sorts a list alphabetically by entity name; only applicable within the limit/skip returned entity block"))
+	packageListCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("page through all packages in the collection instead of a single LIMIT block; --name-sort then applies to the complete list"))
+	packageListCmd.Flags().StringArrayVar(&Flags.common.filter, "filter", []string{}, wski18n.T("only list packages matching the FIELD OPERATOR VALUE `EXPRESSION`, e.g. 'publish=true'; may be repeated"))
+	packageListCmd.Flags().StringVar(&Flags.common.sortBy, "sort-by", "", wski18n.T("sort the list by `FIELD`, ascending or, with FIELD:desc, descending"))
 
 	packageCmd.AddCommand(
 		packageBindCmd,
@@ -538,5 +580,11 @@ func init() {
 		packageDeleteCmd,
 		packageListCmd,
 		packageRefreshCmd,
+		packageExportCmd,
+		packageImportCmd,
+		packageCloneCmd,
+		packageRenameCmd,
+		packageSearchCmd,
+		packageEffectiveCmd,
 	)
 }
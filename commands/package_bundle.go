@@ -0,0 +1,424 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// A package bundle is a gzipped tar file with the package in PACKAGE_BUNDLE_MANIFEST and each of its
+// actions, code included, in PACKAGE_BUNDLE_ACTIONS/NAME.json
+const PACKAGE_BUNDLE_VERSION = 1
+const PACKAGE_BUNDLE_MANIFEST = "package.json"
+const PACKAGE_BUNDLE_ACTIONS = "actions"
+const PACKAGE_BUNDLE_EXT = ".tgz"
+
+type packageBundle struct {
+	Version   int            `json:"version"`
+	Namespace string         `json:"namespace"` // namespace the package was exported from
+	Package   *whisk.Package `json:"package"`
+	actions   []*whisk.Action
+}
+
+var packageExportCmd = &cobra.Command{
+	Use:           "export PACKAGE_NAME",
+	Short:         wski18n.T("export a package and its actions to a bundle file"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 1, 1, "Package export", wski18n.T("A package name is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		qualifiedName, err := NewQualifiedName(args[0])
+		if err != nil {
+			return NewQualifiedNameError(args[0], err)
+		}
+
+		bundle, err := exportPackage(qualifiedName)
+		if err != nil {
+			return err
+		}
+
+		filename := Flags.pkg.to
+		if len(filename) == 0 {
+			filename = qualifiedName.GetEntity() + PACKAGE_BUNDLE_EXT
+		}
+
+		if err = writePackageBundle(filename, bundle); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} exported package {{.name}} with {{.count}} actions to {{.file}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName()),
+				"count": len(bundle.actions), "file": filename}))
+		return nil
+	},
+}
+
+var packageImportCmd = &cobra.Command{
+	Use:           "import BUNDLE_FILE",
+	Short:         wski18n.T("create a package and its actions from a bundle file"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 1, 1, "Package import", wski18n.T("A bundle file is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		bundle, err := readPackageBundle(args[0])
+		if err != nil {
+			return err
+		}
+
+		name := bundle.Package.Name
+		if len(Flags.pkg.as) > 0 {
+			name = Flags.pkg.as
+		}
+		if len(Flags.pkg.namespace) > 0 {
+			name = fmt.Sprintf("/%s/%s", strings.Trim(Flags.pkg.namespace, "/"), name)
+		}
+
+		qualifiedName, err := NewQualifiedName(name)
+		if err != nil {
+			return NewQualifiedNameError(name, err)
+		}
+		if len(qualifiedName.GetPackageName()) > 0 {
+			errStr := wski18n.T("'{{.name}}' is not a valid package name.", map[string]interface{}{"name": name})
+			return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+		}
+
+		changes := new(entityChanges)
+		if err = importPackage(bundle, qualifiedName, changes); err != nil {
+			changes.rollback()
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} imported package {{.name}} with {{.count}} actions from {{.file}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName()),
+				"count": len(bundle.actions), "file": args[0]}))
+		return nil
+	},
+}
+
+// exportPackage fetches a package and every action and feed in it, code included. A package binding
+// has no actions of its own, so it cannot be exported.
+func exportPackage(qualifiedName *QualifiedName) (*packageBundle, error) {
+	Client.Namespace = qualifiedName.GetNamespace()
+
+	xPackage, _, err := Client.Packages.Get(qualifiedName.GetEntityName())
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Packages.Get(%s) failed: %s\n", qualifiedName.GetEntityName(), err)
+		errStr := wski18n.T("Unable to get package '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": err})
+		return nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	if xPackage.Binding != nil && len(xPackage.Binding.Name) > 0 {
+		errStr := wski18n.T("Package '{{.name}}' is a binding of '{{.binding}}'; export that package instead.",
+			map[string]interface{}{"name": qualifiedName.GetEntityName(),
+				"binding": getFullName(xPackage.Binding.Namespace, "", xPackage.Binding.Name)})
+		return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	bundle := &packageBundle{
+		Version:   PACKAGE_BUNDLE_VERSION,
+		Namespace: xPackage.Namespace,
+		Package: &whisk.Package{
+			Name:        xPackage.Name,
+			Publish:     xPackage.Publish,
+			Annotations: xPackage.Annotations,
+			Parameters:  xPackage.Parameters,
+		},
+	}
+
+	for _, entry := range append(xPackage.Actions, xPackage.Feeds...) {
+		actionName := qualifiedName.GetEntityName() + "/" + entry.Name
+		action, _, err := Client.Actions.Get(actionName, FETCH_CODE)
+		if err != nil {
+			return nil, actionGetError(actionName, FETCH_CODE, err)
+		}
+
+		bundle.actions = append(bundle.actions, &whisk.Action{
+			Name:        entry.Name,
+			Exec:        action.Exec,
+			Annotations: action.Annotations,
+			Parameters:  action.Parameters,
+			Limits:      action.Limits,
+		})
+	}
+
+	sort.Slice(bundle.actions, func(i, j int) bool { return bundle.actions[i].Name < bundle.actions[j].Name })
+	return bundle, nil
+}
+
+// importPackage creates the package of a bundle as qualifiedName and then its actions, each sequence
+// after the actions of the package it runs, recording in changes how to remove what it created
+func importPackage(bundle *packageBundle, qualifiedName *QualifiedName, changes *entityChanges) error {
+	Client.Namespace = qualifiedName.GetNamespace()
+
+	xPackage := *bundle.Package
+	xPackage.Name = qualifiedName.GetEntityName()
+	xPackage.Namespace = qualifiedName.GetNamespace()
+
+	if _, _, err := Client.Packages.Insert(&xPackage, false); err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Packages.Insert(%#v, false) failed: %s\n", xPackage, err)
+		errStr := wski18n.T("Unable to create package '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	changes.add(func() error { return deletePackage(qualifiedName.GetNamespace(), qualifiedName.GetEntityName()) })
+
+	for _, bundled := range packageImportOrder(bundle) {
+		if err := createBundleAction(bundle, bundled, qualifiedName); err != nil {
+			return err
+		}
+
+		actionName := qualifiedName.GetEntityName() + "/" + bundled.Name
+		changes.add(func() error { return deleteAction(qualifiedName.GetNamespace(), actionName) })
+	}
+
+	return nil
+}
+
+// createBundleAction creates an action of a bundle in the package qualifiedName, with the sequence
+// components in the package of the bundle renamed to match
+func createBundleAction(bundle *packageBundle, bundled *whisk.Action, qualifiedName *QualifiedName) error {
+	Client.Namespace = qualifiedName.GetNamespace()
+
+	action := *bundled
+	action.Name = qualifiedName.GetEntityName() + "/" + bundled.Name
+	action.Namespace = qualifiedName.GetNamespace()
+
+	if bundled.Exec != nil {
+		exec := *bundled.Exec
+		exec.Binary = nil
+		if exec.Kind == SEQUENCE {
+			exec.Components = nil
+			for _, component := range bundled.Exec.Components {
+				if entity, ok := bundle.component(component); ok {
+					component = fmt.Sprintf("/%s/%s/%s", qualifiedName.GetNamespace(), qualifiedName.GetEntityName(), entity)
+				}
+				exec.Components = append(exec.Components, component)
+			}
+		}
+		action.Exec = &exec
+	}
+
+	if _, _, err := Client.Actions.Insert(&action, false); err != nil {
+		return actionInsertError(&action, err)
+	}
+	return nil
+}
+
+// component returns the name of the action a sequence component refers to if it is in the package
+// of the bundle
+func (bundle *packageBundle) component(component string) (string, bool) {
+	for _, namespace := range []string{bundle.Namespace, "_"} {
+		prefix := fmt.Sprintf("/%s/%s/", namespace, bundle.Package.Name)
+		if strings.HasPrefix(component, prefix) {
+			return strings.TrimPrefix(component, prefix), true
+		}
+	}
+	return "", false
+}
+
+// packageImportOrder orders the actions of a bundle so that every sequence follows the actions of the
+// package that it runs. Cycles and components missing from the bundle are left for the controller to
+// reject.
+func packageImportOrder(bundle *packageBundle) []*whisk.Action {
+	var ordered []*whisk.Action
+	created := make(map[string]bool)
+	pending := bundle.actions
+
+	for len(pending) > 0 {
+		var waiting []*whisk.Action
+		for _, action := range pending {
+			ready := true
+			if action.Exec != nil {
+				for _, component := range action.Exec.Components {
+					if entity, ok := bundle.component(component); ok && !created[entity] {
+						ready = false
+					}
+				}
+			}
+
+			if ready {
+				ordered = append(ordered, action)
+				created[action.Name] = true
+			} else {
+				waiting = append(waiting, action)
+			}
+		}
+
+		if len(waiting) == len(pending) {
+			return append(ordered, waiting...)
+		}
+		pending = waiting
+	}
+
+	return ordered
+}
+
+func writePackageBundle(filename string, bundle *packageBundle) error {
+	if exists, err := FileExists(filename); err != nil {
+		return err
+	} else if exists {
+		return fileExistsError(filename)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return packageBundleWriteError(filename, err)
+	}
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = addPackageBundleEntry(tarWriter, PACKAGE_BUNDLE_MANIFEST, bundle)
+	for _, action := range bundle.actions {
+		if err == nil {
+			err = addPackageBundleEntry(tarWriter, PACKAGE_BUNDLE_ACTIONS+"/"+action.Name+".json", action)
+		}
+	}
+	for _, closer := range []io.Closer{tarWriter, gzipWriter, file} {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if err != nil {
+		os.Remove(filename)
+		return packageBundleWriteError(filename, err)
+	}
+	return nil
+}
+
+func addPackageBundleEntry(tarWriter *tar.Writer, name string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+	if err = tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = tarWriter.Write(content)
+	return err
+}
+
+func readPackageBundle(filename string) (*packageBundle, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "os.Open(%s) failed: %s\n", filename, err)
+		errStr := wski18n.T("File '{{.name}}' is not a valid file or it does not exist",
+			map[string]interface{}{"name": filename})
+		return nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_USAGE, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, packageBundleReadError(filename, err)
+	}
+
+	var bundle *packageBundle
+	var actions []*whisk.Action
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, packageBundleReadError(filename, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, packageBundleReadError(filename, err)
+		}
+
+		if header.Name == PACKAGE_BUNDLE_MANIFEST {
+			bundle = new(packageBundle)
+			err = json.Unmarshal(content, bundle)
+		} else if strings.HasPrefix(header.Name, PACKAGE_BUNDLE_ACTIONS+"/") && strings.HasSuffix(header.Name, ".json") {
+			action := new(whisk.Action)
+			err = json.Unmarshal(content, action)
+			actions = append(actions, action)
+		}
+		if err != nil {
+			return nil, packageBundleReadError(filename, fmt.Errorf("%s: %s", header.Name, err))
+		}
+	}
+
+	if bundle == nil || bundle.Package == nil || len(bundle.Package.Name) == 0 {
+		return nil, packageBundleReadError(filename,
+			errors.New(wski18n.T("the bundle has no {{.manifest}}", map[string]interface{}{"manifest": PACKAGE_BUNDLE_MANIFEST})))
+	}
+	if bundle.Version > PACKAGE_BUNDLE_VERSION {
+		return nil, packageBundleReadError(filename,
+			errors.New(wski18n.T("bundle version {{.version}} is not supported by this CLI", map[string]interface{}{"version": bundle.Version})))
+	}
+
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Name < actions[j].Name })
+	bundle.actions = actions
+	return bundle, nil
+}
+
+func packageBundleWriteError(filename string, err error) error {
+	whisk.Debug(whisk.DbgError, "Writing package bundle '%s' failed: %s\n", filename, err)
+	errStr := wski18n.T("Unable to write package bundle '{{.name}}': {{.err}}",
+		map[string]interface{}{"name": filename, "err": err})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func packageBundleReadError(filename string, err error) error {
+	whisk.Debug(whisk.DbgError, "Reading package bundle '%s' failed: %s\n", filename, err)
+	errStr := wski18n.T("'{{.name}}' is not a valid package bundle: {{.err}}",
+		map[string]interface{}{"name": filename, "err": err})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func init() {
+	packageExportCmd.Flags().StringVar(&Flags.pkg.to, "to", "", wski18n.T("write the bundle to `FILE`; defaults to PACKAGE_NAME.tgz"))
+
+	packageImportCmd.Flags().StringVar(&Flags.pkg.as, "as", "", wski18n.T("create the package as `NAME` instead of the name it was exported with"))
+	packageImportCmd.Flags().StringVar(&Flags.pkg.namespace, "namespace", "", wski18n.T("create the package in `NAMESPACE` instead of the default namespace"))
+}
@@ -0,0 +1,126 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func packageBundleTestController(t *testing.T, created *[]*whisk.Action) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path, "/namespaces/")+len("/namespaces"):]
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && path == "/guest/packages/tools":
+			w.Write([]byte(`{"name": "tools", "namespace": "guest", "publish": true,
+				"parameters": [{"key": "region", "value": "eu"}],
+				"actions": [{"name": "pipeline"}, {"name": "hello"}], "feeds": [{"name": "changes"}]}`))
+		case r.Method == http.MethodGet && path == "/guest/packages/bound":
+			w.Write([]byte(`{"name": "bound", "namespace": "guest", "binding": {"namespace": "other", "name": "tools"}}`))
+		case r.Method == http.MethodGet && path == "/guest/actions/tools/hello":
+			w.Write([]byte(`{"name": "hello", "namespace": "guest/tools", "version": "0.0.3",
+				"exec": {"kind": "nodejs:default", "code": "function main() {}", "binary": false}, "limits": {"timeout": 1000}}`))
+		case r.Method == http.MethodGet && path == "/guest/actions/tools/changes":
+			w.Write([]byte(`{"name": "changes", "namespace": "guest/tools", "annotations": [{"key": "feed", "value": true}],
+				"exec": {"kind": "nodejs:default", "code": "function main() {}"}}`))
+		case r.Method == http.MethodGet && path == "/guest/actions/tools/pipeline":
+			w.Write([]byte(`{"name": "pipeline", "namespace": "guest/tools",
+				"exec": {"kind": "sequence", "components": ["/guest/tools/hello", "/whisk.system/utils/echo"]}}`))
+		case r.Method == http.MethodPut && strings.HasPrefix(path, "/prod/packages/"):
+			w.Write([]byte(`{}`))
+		case r.Method == http.MethodPut && strings.HasPrefix(path, "/prod/actions/"):
+			action := new(whisk.Action)
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(action))
+			*created = append(*created, action)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "The requested resource does not exist."}`))
+		}
+	}
+}
+
+func TestPackageExportImport(t *testing.T) {
+	var created []*whisk.Action
+	newTestClient(t, packageBundleTestController(t, &created))
+
+	qualifiedName, _ := NewQualifiedName("/guest/tools")
+	bundle, err := exportPackage(qualifiedName)
+	assert.Nil(t, err)
+	assert.Equal(t, "guest", bundle.Namespace)
+	assert.Equal(t, 3, len(bundle.actions))
+	assert.Equal(t, "changes", bundle.actions[0].Name)
+	assert.Equal(t, "", bundle.actions[1].Version)
+	assert.Equal(t, 1000, *bundle.actions[1].Limits.Timeout)
+
+	filename := filepath.Join(filepath.Dir(writeApiTestFile(t, "unused", "")), "tools.tgz")
+	assert.Nil(t, writePackageBundle(filename, bundle))
+	err = writePackageBundle(filename, bundle)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	read, err := readPackageBundle(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, bundle.Package, read.Package)
+	assert.Equal(t, bundle.actions, read.actions)
+
+	target, _ := NewQualifiedName("/prod/toolkit")
+	assert.Nil(t, importPackage(read, target, new(entityChanges)))
+	assert.Equal(t, 3, len(created))
+	assert.Equal(t, "toolkit/pipeline", created[2].Name)
+	assert.Equal(t, []string{"/prod/toolkit/hello", "/whisk.system/utils/echo"}, created[2].Exec.Components)
+	assert.Nil(t, created[0].Exec.Binary)
+
+	qualifiedName, _ = NewQualifiedName("/guest/bound")
+	_, err = exportPackage(qualifiedName)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is a binding of '/other/tools'")
+
+	_, err = readPackageBundle(writeApiTestFile(t, "bundle.tgz", "not a bundle"))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is not a valid package bundle")
+}
+
+func TestPackageImportOrder(t *testing.T) {
+	sequence := func(name string, components ...string) *whisk.Action {
+		return &whisk.Action{Name: name, Exec: &whisk.Exec{Kind: SEQUENCE, Components: components}}
+	}
+	bundle := &packageBundle{Namespace: "guest", Package: &whisk.Package{Name: "p"}, actions: []*whisk.Action{
+		sequence("a", "/guest/p/b", "/_/p/c"),
+		sequence("b", "/guest/p/c", "/other/p/d"),
+		{Name: "c", Exec: &whisk.Exec{Kind: "nodejs:default"}},
+		sequence("x", "/guest/p/y"),
+		sequence("y", "/guest/p/x"),
+	}}
+
+	var names []string
+	for _, action := range packageImportOrder(bundle) {
+		names = append(names, action.Name)
+	}
+	assert.Equal(t, []string{"c", "b", "a", "x", "y"}, names)
+}
@@ -0,0 +1,348 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// entityChanges records how to undo each change of a command that changes several entities, so that
+// a failure part way through does not leave a partial copy behind
+type entityChanges struct {
+	undo []func() error
+}
+
+func (changes *entityChanges) add(undo func() error) {
+	changes.undo = append(changes.undo, undo)
+}
+
+// rollback undoes the recorded changes, the latest first. A change that cannot be undone is reported
+// so that it can be cleaned up by hand.
+func (changes *entityChanges) rollback() {
+	for i := len(changes.undo) - 1; i >= 0; i-- {
+		if err := changes.undo[i](); err != nil {
+			printWarning(wski18n.T("Unable to roll back a change: {{.err}}", map[string]interface{}{"err": err}))
+		}
+	}
+	changes.undo = nil
+}
+
+var packageCloneCmd = &cobra.Command{
+	Use:           "clone SOURCE_PACKAGE DESTINATION_PACKAGE",
+	Short:         wski18n.T("copy a package and its actions to a new package"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 2, 2, "Package clone",
+			wski18n.T("A source and a destination package name are required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		source, destination, err := parsePackageNames(args)
+		if err != nil {
+			return err
+		}
+
+		bundle, err := exportPackage(source)
+		if err != nil {
+			return err
+		}
+
+		changes := new(entityChanges)
+		if err = importPackage(bundle, destination, changes); err != nil {
+			changes.rollback()
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} cloned package {{.name}} to {{.destination}} with {{.count}} actions\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(source.GetEntityName()),
+				"destination": boldString(destination.GetEntityName()), "count": len(bundle.actions)}))
+		return nil
+	},
+}
+
+var packageRenameCmd = &cobra.Command{
+	Use:           "rename SOURCE_PACKAGE DESTINATION_PACKAGE",
+	Short:         wski18n.T("move a package and its actions to a new name, updating the sequences and rules that use them"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 2, 2, "Package rename",
+			wski18n.T("A source and a destination package name are required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		source, destination, err := parsePackageNames(args)
+		if err != nil {
+			return err
+		}
+
+		bundle, err := exportPackage(source)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		changes := new(entityChanges)
+		err = importPackage(bundle, destination, changes)
+		if err == nil {
+			count, err = rewriteReferences(source.GetNamespace(), bundle.renames(destination), changes)
+		}
+		if err == nil {
+			err = deleteBundlePackage(bundle, source, changes)
+		}
+		if err != nil {
+			changes.rollback()
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} renamed package {{.name}} to {{.destination}}; updated {{.count}} sequences and rules that use its actions\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(source.GetEntityName()),
+				"destination": boldString(destination.GetEntityName()), "count": count}))
+		return nil
+	},
+}
+
+func parsePackageNames(args []string) (*QualifiedName, *QualifiedName, error) {
+	var names []*QualifiedName
+	for _, arg := range args {
+		qualifiedName, err := NewQualifiedName(arg)
+		if err != nil {
+			return nil, nil, NewQualifiedNameError(arg, err)
+		}
+		if len(qualifiedName.GetPackageName()) > 0 {
+			errStr := wski18n.T("'{{.name}}' is not a valid package name.", map[string]interface{}{"name": arg})
+			return nil, nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+		}
+		names = append(names, qualifiedName)
+	}
+	return names[0], names[1], nil
+}
+
+// renames maps the fully qualified name of each action of a bundle to its name in the package destination
+func (bundle *packageBundle) renames(destination *QualifiedName) map[string]string {
+	renamed := make(map[string]string)
+	for _, action := range bundle.actions {
+		renamed[fmt.Sprintf("/%s/%s/%s", bundle.Namespace, bundle.Package.Name, action.Name)] =
+			fmt.Sprintf("/%s/%s/%s", destination.GetNamespace(), destination.GetEntityName(), action.Name)
+	}
+	return renamed
+}
+
+// deleteBundlePackage deletes the actions of an exported package and then the package. Deleted actions
+// are recreated from the bundle on rollback.
+func deleteBundlePackage(bundle *packageBundle, qualifiedName *QualifiedName, changes *entityChanges) error {
+	ordered := packageImportOrder(bundle)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		action := ordered[i]
+		if err := deleteAction(qualifiedName.GetNamespace(), qualifiedName.GetEntityName()+"/"+action.Name); err != nil {
+			return err
+		}
+		changes.add(func() error { return createBundleAction(bundle, action, qualifiedName) })
+	}
+
+	return deletePackage(qualifiedName.GetNamespace(), qualifiedName.GetEntityName())
+}
+
+// rewriteReferences points the sequences and rules of a namespace that run one of the renamed actions,
+// keyed by fully qualified name, at the new name. The sequences being renamed themselves are skipped.
+func rewriteReferences(namespace string, renamed map[string]string, changes *entityChanges) (int, error) {
+	var sequences, rules []string
+	count := 0
+
+	Client.Namespace = namespace
+	err := listAllPages(0, func(skip int, limit int) (int, error) {
+		options := &whisk.ActionListOptions{Skip: skip, Limit: limit}
+		page, _, err := Client.Actions.List("", options)
+		if err != nil {
+			return 0, actionListError("", options, err)
+		}
+		for _, action := range page {
+			_, moving := renamed[fmt.Sprintf("/%s/%s", action.Namespace, action.Name)]
+			if !moving && getValueString(action.Annotations, "exec") == SEQUENCE {
+				sequences = append(sequences, entityPathName(action.Namespace, action.Name))
+			}
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	for _, name := range sequences {
+		name := name
+		Client.Namespace = namespace
+		action, _, err := Client.Actions.Get(name, DO_NOT_FETCH_CODE)
+		if err != nil {
+			return count, actionGetError(name, DO_NOT_FETCH_CODE, err)
+		}
+		if action.Exec == nil {
+			continue
+		}
+
+		components, changed := renameComponents(action.Exec.Components, renamed)
+		if !changed {
+			continue
+		}
+		if err = updateSequence(namespace, name, action, components); err != nil {
+			return count, err
+		}
+		original := action.Exec.Components
+		changes.add(func() error { return updateSequence(namespace, name, action, original) })
+		count++
+	}
+
+	err = listAllPages(0, func(skip int, limit int) (int, error) {
+		page, _, err := Client.Rules.List(&whisk.RuleListOptions{Skip: skip, Limit: limit})
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "Client.Rules.List(%d, %d) error: %s\n", skip, limit, err)
+			errStr := wski18n.T("Unable to obtain the list of rules for namespace '{{.name}}': {{.err}}",
+				map[string]interface{}{"name": getClientNamespace(), "err": err})
+			return 0, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+		for _, rule := range page {
+			rules = append(rules, rule.Name)
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	for _, name := range rules {
+		Client.Namespace = namespace
+		rule, err := getRule(namespace, name)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "getRule(%s) failed: %s\n", name, err)
+			errStr := wski18n.T("Unable to get rule '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+			return count, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+
+		original := ruleEntityFullName(ruleEntityPath(rule.Action))
+		action, ok := renamed[original]
+		if !ok {
+			continue
+		}
+		if err = updateRuleAction(namespace, rule, action); err != nil {
+			return count, err
+		}
+		changes.add(func() error { return updateRuleAction(namespace, rule, original) })
+		count++
+	}
+
+	return count, nil
+}
+
+// entityPathName returns the name of a listed entity relative to its namespace, with the package
+// taken from the namespace path, e.g. ("guest/utils", "date") is "utils/date"
+func entityPathName(namespace string, name string) string {
+	if parts := strings.SplitN(namespace, "/", 2); len(parts) > 1 {
+		return parts[1] + "/" + name
+	}
+	return name
+}
+
+func renameComponents(components []string, renamed map[string]string) ([]string, bool) {
+	var result []string
+	changed := false
+	for _, component := range components {
+		if name, ok := renamed[component]; ok {
+			component = name
+			changed = true
+		}
+		result = append(result, component)
+	}
+	return result, changed
+}
+
+func updateSequence(namespace string, name string, action *whisk.Action, components []string) error {
+	Client.Namespace = namespace
+
+	exec := &whisk.Exec{Kind: SEQUENCE, Components: components}
+	sequence := &whisk.Action{
+		Name:        name,
+		Namespace:   namespace,
+		Exec:        exec,
+		Annotations: action.Annotations,
+		Parameters:  action.Parameters,
+		Limits:      action.Limits,
+	}
+
+	if _, _, err := Client.Actions.Insert(sequence, true); err != nil {
+		return actionInsertError(sequence, err)
+	}
+	return nil
+}
+
+// updateRuleAction points a rule at another action. Updating a rule activates it, so an inactive rule
+// is disabled again.
+func updateRuleAction(namespace string, rule *whisk.Rule, action string) error {
+	Client.Namespace = namespace
+	ruleStatusCache.forget(rule.Name)
+
+	updated := &whisk.Rule{
+		Name:        rule.Name,
+		Annotations: rule.Annotations,
+		Trigger:     ruleEntityFullName(ruleEntityPath(rule.Trigger)),
+		Action:      action,
+	}
+
+	if _, _, err := Client.Rules.Insert(updated, true); err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Rules.Insert(%#v) failed: %s\n", updated, err)
+		errStr := wski18n.T("Unable to update rule '{{.name}}': {{.err}}", map[string]interface{}{"name": rule.Name, "err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	if rule.Status == "inactive" {
+		if _, _, err := Client.Rules.SetState(rule.Name, "inactive"); err != nil {
+			whisk.Debug(whisk.DbgError, "Client.Rules.SetState(%s, inactive) failed: %s\n", rule.Name, err)
+			errStr := wski18n.T("Unable to disable rule '{{.name}}': {{.err}}", map[string]interface{}{"name": rule.Name, "err": err})
+			return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+	}
+
+	return nil
+}
+
+func deleteAction(namespace string, name string) error {
+	Client.Namespace = namespace
+	if _, err := Client.Actions.Delete(name); err != nil {
+		return actionDeleteError(name, err)
+	}
+	return nil
+}
+
+func deletePackage(namespace string, name string) error {
+	Client.Namespace = namespace
+	if _, err := Client.Packages.Delete(name); err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Packages.Delete(%s) failed: %s\n", name, err)
+		errStr := wski18n.T("Unable to delete package '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return nil
+}
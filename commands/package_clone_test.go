@@ -0,0 +1,80 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func entityStoreComponents(store *entityStore, key string) []interface{} {
+	return store.entities[key]["exec"].(map[string]interface{})["components"].([]interface{})
+}
+
+func TestPackageRename(t *testing.T) {
+	store := newEntityStore(t, entityStoreTestEntities())
+
+	source, _ := NewQualifiedName("/guest/tools")
+	destination, _ := NewQualifiedName("/guest/kit")
+	bundle, err := exportPackage(source)
+	assert.Nil(t, err)
+
+	// A failure deleting the original package undoes every step
+	store.failing = "DELETE packages/tools"
+	changes := new(entityChanges)
+	assert.Nil(t, importPackage(bundle, destination, changes))
+	count, err := rewriteReferences("guest", bundle.renames(destination), changes)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []interface{}{"/guest/kit/hello", "/guest/other"}, entityStoreComponents(store, "actions/pipeline"))
+	assert.Equal(t, "inactive", store.entities["rules/greet"]["status"])
+	assert.NotNil(t, deleteBundlePackage(bundle, source, changes))
+	changes.rollback()
+
+	assert.Equal(t, []string{"other", "pipeline", "tools/hello", "tools/steps"}, store.names("actions"))
+	assert.Equal(t, []string{"tools"}, store.names("packages"))
+	assert.Equal(t, []interface{}{"/guest/tools/hello", "/guest/other"}, entityStoreComponents(store, "actions/pipeline"))
+	assert.Equal(t, "guest/tools", store.entities["rules/greet"]["action"].(map[string]interface{})["path"])
+	assert.Equal(t, "inactive", store.entities["rules/greet"]["status"])
+}
+
+func TestMoveAction(t *testing.T) {
+	store := newEntityStore(t, entityStoreTestEntities())
+
+	source, _ := NewQualifiedName("/guest/tools/hello")
+	destination, _ := NewQualifiedName("/guest/hello")
+
+	store.failing = "PUT rules/greet"
+	_, err := moveAction(source, destination)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Unable to update rule 'greet'")
+	assert.Equal(t, []string{"other", "pipeline", "tools/hello", "tools/steps"}, store.names("actions"))
+	assert.Equal(t, []interface{}{"/guest/tools/hello"}, entityStoreComponents(store, "actions/tools/steps"))
+
+	store.failing = ""
+	count, err := moveAction(source, destination)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"hello", "other", "pipeline", "tools/steps"}, store.names("actions"))
+	assert.Equal(t, []interface{}{"/guest/hello"}, entityStoreComponents(store, "actions/tools/steps"))
+	assert.Equal(t, map[string]interface{}{"path": "guest", "name": "hello"}, store.entities["rules/greet"]["action"])
+	assert.Equal(t, "inactive", store.entities["rules/greet"]["status"])
+}
@@ -0,0 +1,263 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Layers that supply parameter values, from the lowest precedence to the highest
+const PARAM_LAYER_PACKAGE = "package"
+const PARAM_LAYER_BINDING = "binding"
+const PARAM_LAYER_ACTION = "action"
+const PARAM_LAYER_INVOCATION = "invocation"
+
+const SECRET_MASK = "********"
+
+// Parameter names containing one of these are taken to hold secrets
+var SECRET_PARAMETER_NAMES = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "credential", "private"}
+
+type parameterLayer struct {
+	name       string
+	parameters whisk.KeyValueArr
+}
+
+type effectiveParameter struct {
+	key        string
+	value      interface{}
+	layer      string
+	overridden []string // the lower layers whose value this one replaces
+	final      bool     // bound to a value by an action annotated final, so an invocation cannot change it
+	rejected   bool     // an invocation value was given for a final parameter, which the controller rejects
+}
+
+var packageEffectiveCmd = &cobra.Command{
+	Use:           "effective PACKAGE_NAME [ACTION_NAME]",
+	Short:         wski18n.T("show the parameter values a binding or package resolves to, and where each comes from"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 1, 2, "Package effective",
+			wski18n.T("A package name and an optional action name are required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		qualifiedName, err := NewQualifiedName(args[0])
+		if err != nil {
+			return NewQualifiedNameError(args[0], err)
+		}
+
+		Client.Namespace = qualifiedName.GetNamespace()
+		xPackage, err := getEffectivePackage(qualifiedName.GetEntityName())
+		if err != nil {
+			return err
+		}
+
+		origin := xPackage
+		var layers []parameterLayer
+		if xPackage.Binding != nil && len(xPackage.Binding.Name) > 0 {
+			Client.Namespace = xPackage.Binding.Namespace
+			if origin, err = getEffectivePackage(xPackage.Binding.Name); err != nil {
+				return err
+			}
+			layers = append(layers, parameterLayer{PARAM_LAYER_PACKAGE, origin.Parameters},
+				parameterLayer{PARAM_LAYER_BINDING, xPackage.Parameters})
+		} else {
+			layers = append(layers, parameterLayer{PARAM_LAYER_PACKAGE, origin.Parameters})
+		}
+
+		annotations := origin.Annotations
+		final := false
+		var action *whisk.Action
+		if len(args) > 1 {
+			actionName := origin.Name + "/" + args[1]
+			Client.Namespace = origin.Namespace
+			if action, _, err = Client.Actions.Get(actionName, DO_NOT_FETCH_CODE); err != nil {
+				return actionGetError(actionName, DO_NOT_FETCH_CODE, err)
+			}
+			layers = append(layers, parameterLayer{PARAM_LAYER_ACTION, action.Parameters})
+			annotations = append(annotations, action.Annotations...)
+			final = getValueBool(action.Annotations, FINAL_ANNOT)
+		}
+
+		parameters, err := getJSONFromStrings(Flags.common.param, true)
+		if err != nil {
+			return getJSONFromStringsParamError(Flags.common.param, true, err)
+		}
+		layers = append(layers, parameterLayer{PARAM_LAYER_INVOCATION, parameters.(whisk.KeyValueArr)})
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got effective parameters of {{.name}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName())}))
+		fmt.Fprintf(color.Output, "%s %s\n", boldString(fmt.Sprintf("%7s", "package")),
+			getFullName(xPackage.Namespace, xPackage.Name, ""))
+		if origin != xPackage {
+			fmt.Fprintf(color.Output, "   (%s: %s)\n", boldString(wski18n.T("binding of")), getFullName(origin.Namespace, origin.Name, ""))
+		}
+		if action != nil {
+			fmt.Fprintf(color.Output, "   (%s: %s)\n", boldString(wski18n.T("action")), getFullName(origin.Namespace, origin.Name, action.Name))
+		}
+		resolved := resolveEffectiveParameters(layers, final)
+		printEffectiveParameters(resolved, annotations)
+
+		var rejected []string
+		for _, parameter := range resolved {
+			if parameter.rejected {
+				rejected = append(rejected, parameter.key)
+			}
+		}
+		if len(rejected) > 0 {
+			errStr := wski18n.T("An invocation with these parameters would be rejected: action '{{.name}}' is final and binds {{.params}}",
+				map[string]interface{}{"name": action.Name, "params": strings.Join(rejected, ", ")})
+			return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+
+		return nil
+	},
+}
+
+func getEffectivePackage(name string) (*whisk.Package, error) {
+	xPackage, _, err := Client.Packages.Get(name)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Packages.Get(%s) failed: %s\n", name, err)
+		errStr := wski18n.T("Unable to get package '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+		return nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return xPackage, nil
+}
+
+// resolveEffectiveParameters merges the layers in order, each overriding the ones before it. The
+// controller returns a binding and a packaged action with the parameters of the package merged in, so
+// a value equal to the one of the package, or to the one already resolved, is credited to the lower
+// layer. When the action is final, the controller rejects invocations that set one of its parameters
+// bound to a value.
+func resolveEffectiveParameters(layers []parameterLayer, final bool) []*effectiveParameter {
+	var resolved []*effectiveParameter
+	byKey := make(map[string]*effectiveParameter)
+	inherited := make(map[string]interface{})
+
+	for _, layer := range layers {
+		for _, kv := range layer.parameters {
+			parameter, exists := byKey[kv.Key]
+			packageValue, isInherited := inherited[kv.Key]
+			isInherited = isInherited && reflect.DeepEqual(packageValue, kv.Value)
+
+			if layer.name == PARAM_LAYER_PACKAGE {
+				inherited[kv.Key] = kv.Value
+			}
+
+			if !exists {
+				parameter = &effectiveParameter{key: kv.Key, value: kv.Value, layer: layer.name}
+				byKey[kv.Key] = parameter
+				resolved = append(resolved, parameter)
+			} else if layer.name == PARAM_LAYER_INVOCATION && parameter.final {
+				parameter.rejected = true
+			} else if layer.name == PARAM_LAYER_INVOCATION || !(isInherited || reflect.DeepEqual(parameter.value, kv.Value)) {
+				parameter.overridden = append(parameter.overridden, parameter.layer)
+				parameter.value = kv.Value
+				parameter.layer = layer.name
+			}
+		}
+
+		if final && layer.name == PARAM_LAYER_ACTION {
+			for _, parameter := range resolved {
+				parameter.final = parameter.value != nil
+			}
+		}
+	}
+
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].key < resolved[j].key })
+	return resolved
+}
+
+// secretParameter reports whether a parameter holds a secret, by its name or by a parameter
+// annotation of type password
+func secretParameter(key string, annotations whisk.KeyValueArr) bool {
+	lowerKey := strings.ToLower(key)
+	for _, name := range SECRET_PARAMETER_NAMES {
+		if strings.Contains(lowerKey, name) {
+			return true
+		}
+	}
+
+	described, _ := annotations.GetValue("parameters").([]interface{})
+	for _, description := range described {
+		if description, ok := description.(map[string]interface{}); ok && description["name"] == key {
+			if description["type"] == "password" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func printEffectiveParameters(parameters []*effectiveParameter, annotations whisk.KeyValueArr) {
+	if len(parameters) == 0 {
+		fmt.Fprintf(color.Output, "   (%s: %s)\n", boldString(wski18n.T("parameters")), wski18n.T("none defined"))
+		return
+	}
+
+	rows := make([][]string, len(parameters))
+	keyWidth, valueWidth, layerWidth := len("parameter"), len("value"), len("layer")
+	for i, parameter := range parameters {
+		value := SECRET_MASK
+		if Flags.pkg.showSecrets || !secretParameter(parameter.key, annotations) {
+			encoded, _ := json.Marshal(parameter.value)
+			value = string(encoded)
+		}
+
+		var notes []string
+		if len(parameter.overridden) > 0 {
+			notes = append(notes, wski18n.T("overrides {{.layers}}", map[string]interface{}{"layers": strings.Join(parameter.overridden, ", ")}))
+		}
+		if parameter.final {
+			notes = append(notes, wski18n.T("final"))
+		}
+		if parameter.rejected {
+			notes = append(notes, wski18n.T("invocation would be rejected"))
+		}
+
+		rows[i] = []string{parameter.key, value, parameter.layer, strings.Join(notes, "; ")}
+		keyWidth = max(keyWidth, len(parameter.key))
+		valueWidth = max(valueWidth, len(value))
+		layerWidth = max(layerWidth, len(parameter.layer))
+	}
+
+	rowFmt := fmt.Sprintf("%%-%ds  %%-%ds  %%-%ds  %%s\n", keyWidth, valueWidth, layerWidth)
+	fmt.Fprint(color.Output, boldString(strings.TrimRight(fmt.Sprintf(rowFmt, "parameter", "value", "layer", ""), " \n")+"\n"))
+	for _, row := range rows {
+		fmt.Fprint(color.Output, strings.TrimRight(fmt.Sprintf(rowFmt, row[0], row[1], row[2], row[3]), " \n")+"\n")
+	}
+}
+
+func init() {
+	packageEffectiveCmd.Flags().StringSliceVarP(&Flags.common.param, "param", "p", []string{}, wski18n.T("invocation parameter values in `KEY VALUE` format"))
+	packageEffectiveCmd.Flags().StringVarP(&Flags.common.paramFile, "param-file", "P", "", wski18n.T("`FILE` containing invocation parameter values in JSON format"))
+	packageEffectiveCmd.Flags().BoolVar(&Flags.pkg.showSecrets, "show-secrets", false, wski18n.T("show the values of parameters that hold secrets instead of masking them"))
+}
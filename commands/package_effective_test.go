@@ -0,0 +1,66 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveEffectiveParameters(t *testing.T) {
+	layers := []parameterLayer{
+		{PARAM_LAYER_PACKAGE, whisk.KeyValueArr{{Key: "host", Value: "db.local"}, {Key: "port", Value: 5984}}},
+		{PARAM_LAYER_BINDING, whisk.KeyValueArr{{Key: "host", Value: "db.prod"}, {Key: "port", Value: 5984}, {Key: "password", Value: "s3"}}},
+		{PARAM_LAYER_ACTION, whisk.KeyValueArr{{Key: "host", Value: "db.local"}, {Key: "port", Value: 5984}, {Key: "limit", Value: 10}, {Key: "order", Value: nil}}},
+		{PARAM_LAYER_INVOCATION, whisk.KeyValueArr{{Key: "limit", Value: 20}, {Key: "order", Value: "asc"}, {Key: "query", Value: "x"}}},
+	}
+
+	parameters := resolveEffectiveParameters(layers, false)
+	assert.Equal(t, []*effectiveParameter{
+		{key: "host", value: "db.prod", layer: PARAM_LAYER_BINDING, overridden: []string{PARAM_LAYER_PACKAGE}},
+		{key: "limit", value: 20, layer: PARAM_LAYER_INVOCATION, overridden: []string{PARAM_LAYER_ACTION}},
+		{key: "order", value: "asc", layer: PARAM_LAYER_INVOCATION, overridden: []string{PARAM_LAYER_ACTION}},
+		{key: "password", value: "s3", layer: PARAM_LAYER_BINDING},
+		{key: "port", value: 5984, layer: PARAM_LAYER_PACKAGE},
+		{key: "query", value: "x", layer: PARAM_LAYER_INVOCATION},
+	}, parameters)
+
+	// Only parameters bound to a value are final; setting one of them makes the controller reject the invocation
+	parameters = resolveEffectiveParameters(layers, true)
+	assert.Equal(t, &effectiveParameter{key: "limit", value: 10, layer: PARAM_LAYER_ACTION, final: true, rejected: true}, parameters[1])
+	assert.Equal(t, &effectiveParameter{key: "order", value: "asc", layer: PARAM_LAYER_INVOCATION, overridden: []string{PARAM_LAYER_ACTION}}, parameters[2])
+	assert.True(t, parameters[0].final)
+	assert.False(t, parameters[5].final)
+}
+
+func TestSecretParameter(t *testing.T) {
+	annotations := whisk.KeyValueArr{{Key: "parameters", Value: []interface{}{
+		map[string]interface{}{"name": "dbUrl", "type": "password"},
+		map[string]interface{}{"name": "dbName", "description": "name of the database"},
+	}}}
+
+	assert.True(t, secretParameter("API_KEY", nil))
+	assert.True(t, secretParameter("accessToken", nil))
+	assert.True(t, secretParameter("dbUrl", annotations))
+	assert.False(t, secretParameter("dbName", annotations))
+	assert.False(t, secretParameter("host", nil))
+}
@@ -0,0 +1,183 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Namespaces that a search always looks in, as their packages are shared with every user
+var SYSTEM_NAMESPACES = []string{"whisk.system"}
+
+var packageSearchCmd = &cobra.Command{
+	Use:           "search TERM",
+	Short:         wski18n.T("search the shared packages of the accessible and system namespaces"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 1, 1, "Package search", wski18n.T("A search term is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		packages := searchPackages(args[0], searchNamespaces())
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} found {{.count}} shared packages matching {{.term}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "count": len(packages), "term": boldString(args[0])}))
+		for _, xPackage := range packages {
+			printPackageSummary(xPackage)
+		}
+		return nil
+	},
+}
+
+// searchNamespaces returns the namespaces of the user, the system namespaces and those given with
+// --namespace, without duplicates. When the namespaces of the user cannot be listed the others are
+// still searched.
+func searchNamespaces() []string {
+	var names []string
+
+	namespaces, _, err := Client.Namespaces.List()
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Namespaces.List() error: %s\n", err)
+		printWarning(wski18n.T("Unable to obtain the list of available namespaces: {{.err}}",
+			map[string]interface{}{"err": err}))
+	}
+	for _, namespace := range namespaces {
+		names = append(names, namespace.Name)
+	}
+	names = append(names, SYSTEM_NAMESPACES...)
+	for _, namespace := range Flags.pkg.namespaces {
+		names = append(names, strings.Trim(namespace, "/"))
+	}
+
+	var unique []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
+
+// searchPackages returns the shared packages of the namespaces that match the term, by name or in
+// their description or parameters. A package that matches is returned with all its actions and feeds,
+// any other with just the ones that match. Namespaces and packages that cannot be read are skipped
+// with a warning.
+func searchPackages(term string, namespaces []string) []*whisk.Package {
+	var found []*whisk.Package
+	term = strings.ToLower(term)
+
+	for _, namespace := range namespaces {
+		var shared []string
+
+		Client.Namespace = namespace
+		err := listAllPages(0, func(skip int, limit int) (int, error) {
+			page, err := listPackages(&whisk.PackageListOptions{Skip: skip, Limit: limit})
+			if err != nil {
+				return 0, err
+			}
+			for _, xPackage := range page {
+				isBinding := xPackage.Binding != nil && len(xPackage.Binding.Name) > 0
+				if xPackage.Publish != nil && *xPackage.Publish && !isBinding {
+					shared = append(shared, xPackage.Name)
+				}
+			}
+			return len(page), nil
+		})
+		if err != nil {
+			printWarning(err.Error())
+			continue
+		}
+
+		for _, name := range shared {
+			xPackage, _, err := Client.Packages.Get(name)
+			if err != nil {
+				whisk.Debug(whisk.DbgError, "Client.Packages.Get(%s) failed: %s\n", name, err)
+				printWarning(wski18n.T("Unable to get package '{{.name}}': {{.err}}",
+					map[string]interface{}{"name": getFullName(namespace, name, ""), "err": err}))
+				continue
+			}
+
+			if xPackage = matchPackage(xPackage, term); xPackage != nil {
+				found = append(found, xPackage)
+			}
+		}
+	}
+
+	return found
+}
+
+// matchPackage returns the package with its actions sorted into actions and feeds, by the feed
+// annotation, and narrowed down to the ones that match the term unless the package itself does.
+// It returns nil when nothing matches.
+func matchPackage(xPackage *whisk.Package, term string) *whisk.Package {
+	matched := *xPackage
+	matched.Actions, matched.Feeds = nil, nil
+	packageMatches := searchMatches(term, xPackage.Name, xPackage.Annotations, xPackage.Parameters)
+
+	for _, action := range append(xPackage.Actions, xPackage.Feeds...) {
+		if !packageMatches && !searchMatches(term, action.Name, action.Annotations, action.Parameters) {
+			continue
+		}
+		if action.Annotations.FindKeyValue("feed") >= 0 {
+			matched.Feeds = append(matched.Feeds, action)
+		} else {
+			matched.Actions = append(matched.Actions, action)
+		}
+	}
+
+	if !packageMatches && len(matched.Actions) == 0 && len(matched.Feeds) == 0 {
+		return nil
+	}
+
+	sort.Slice(matched.Actions, func(i, j int) bool { return matched.Actions[i].Name < matched.Actions[j].Name })
+	sort.Slice(matched.Feeds, func(i, j int) bool { return matched.Feeds[i].Name < matched.Feeds[j].Name })
+	return &matched
+}
+
+// searchMatches reports whether the lowercase term is in the name, description, parameter names or
+// parameter descriptions of an entity
+func searchMatches(term string, name string, annotations whisk.KeyValueArr, parameters whisk.KeyValueArr) bool {
+	texts := []string{name, getValueString(annotations, "description")}
+	for _, param := range getParamUnion(annotations, parameters, "name") {
+		texts = append(texts, strings.TrimLeft(param, "*"))
+	}
+	texts = append(texts, getChildValueStrings(annotations, "parameters", "description")...)
+
+	for _, text := range texts {
+		if strings.Contains(strings.ToLower(text), term) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	packageSearchCmd.Flags().StringSliceVar(&Flags.pkg.namespaces, "namespace", []string{}, wski18n.T("also search the shared packages of `NAMESPACE`; may be repeated"))
+}
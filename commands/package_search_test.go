@@ -0,0 +1,95 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func packageSearchTestController(w http.ResponseWriter, r *http.Request) {
+	responses := map[string]string{
+		"":                       `["guest", "team"]`,
+		"/guest/packages":        `[{"name": "mine", "publish": false}, {"name": "shared", "publish": true}]`,
+		"/team/packages":         `[{"name": "bound", "publish": true, "binding": {"namespace": "x", "name": "y"}}]`,
+		"/whisk.system/packages": `[{"name": "alarms", "publish": true}, {"name": "utils", "publish": true}]`,
+		"/guest/packages/shared": `{"name": "shared", "namespace": "guest", "publish": true,
+			"annotations": [{"key": "description", "value": "Team helpers"}],
+			"actions": [{"name": "tool"}]}`,
+		"/whisk.system/packages/alarms": `{"name": "alarms", "namespace": "whisk.system", "publish": true,
+			"actions": [{"name": "alarm", "annotations": [{"key": "feed", "value": true},
+				{"key": "parameters", "value": [{"name": "cron", "description": "Cron schedule of the alarm"}]}]},
+				{"name": "interval", "annotations": [{"key": "feed", "value": true}]}]}`,
+		"/whisk.system/packages/utils": `{"name": "utils", "namespace": "whisk.system", "publish": true,
+			"actions": [{"name": "date"}, {"name": "sort", "parameters": [{"key": "schedule", "value": 1}]}]}`,
+	}
+
+	path := ""
+	if i := strings.Index(r.URL.Path, "/namespaces/"); i >= 0 {
+		path = r.URL.Path[i+len("/namespaces"):]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if body, ok := responses[path]; ok && (r.URL.Query().Get("skip") == "" || r.URL.Query().Get("skip") == "0") {
+		w.Write([]byte(body))
+		return
+	}
+	if strings.HasSuffix(path, "/packages") {
+		w.Write([]byte(`[]`))
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"error": "The requested resource does not exist."}`))
+}
+
+func packageSearchResults(packages []*whisk.Package) []string {
+	var results []string
+	for _, xPackage := range packages {
+		results = append(results, "/"+xPackage.Namespace+"/"+xPackage.Name)
+		for _, action := range xPackage.Actions {
+			results = append(results, "action "+action.Name)
+		}
+		for _, feed := range xPackage.Feeds {
+			results = append(results, "feed "+feed.Name)
+		}
+	}
+	return results
+}
+
+func TestSearchPackages(t *testing.T) {
+	newTestClient(t, packageSearchTestController)
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+
+	Flags.pkg.namespaces = []string{"/whisk.system", "other"}
+	namespaces := searchNamespaces()
+	assert.Equal(t, []string{"guest", "team", "whisk.system", "other"}, namespaces)
+
+	assert.Equal(t, []string{"/whisk.system/alarms", "feed alarm", "/whisk.system/utils", "action sort"},
+		packageSearchResults(searchPackages("SCHEDULE", namespaces)))
+	assert.Equal(t, []string{"/guest/shared", "action tool"},
+		packageSearchResults(searchPackages("helpers", namespaces)))
+	assert.Equal(t, []string{"/whisk.system/alarms", "feed alarm", "feed interval"},
+		packageSearchResults(searchPackages("alarms", namespaces)))
+	assert.Empty(t, searchPackages("mine", namespaces))
+}
@@ -71,6 +71,9 @@ var projectExportCmd = &cobra.Command{
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(cobraCMD *cobra.Command, args []string) error {
+		if Flags.project.all {
+			return exportNamespace()
+		}
 		return cmd.ExportCmdImp(cobraCMD, args)
 	},
 }
@@ -96,4 +99,8 @@ func init() {
 	projectCmd.AddCommand(projectUnDeployCmd)
 	projectCmd.AddCommand(projectSyncCmd)
 	projectCmd.AddCommand(projectExportCmd)
+	projectCmd.AddCommand(projectPlanCmd)
+	projectCmd.AddCommand(projectApplyCmd)
+	projectCmd.AddCommand(projectDriftCmd)
+	projectCmd.AddCommand(projectInitCmd)
 }
@@ -0,0 +1,242 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/apache/openwhisk-wskdeploy/deployers"
+	"github.com/apache/openwhisk-wskdeploy/parsers"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Exit code of 'wsk project drift' when the deployed project differs from its manifest, told apart
+// from the exit codes of errors
+const EXIT_CODE_DRIFT = 5
+
+const DRIFT_API = "api"
+
+var projectDriftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: wski18n.T("compare the entities the manifest declares with the deployed ones"),
+	Long: wski18n.T("Compare the packages, actions, triggers, rules and APIs the manifest declares with the deployed ones. " +
+		"Entities annotated as managed by the project, or in its packages, that the manifest does not declare are reported as added. " +
+		"Exits with 0 when nothing drifted and 5 when something did."),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deployer, err := loadProjectDeployment()
+		if err != nil {
+			return err
+		}
+		if len(deployer.ProjectName) == 0 {
+			printWarning(wski18n.T("The manifest names no project, so only entities added to its packages are found"))
+		}
+
+		drift, err := findProjectDrift(deployer.ProjectName, deployer.Deployment)
+		if err != nil {
+			return err
+		}
+
+		if len(drift) == 0 {
+			fmt.Fprintf(color.Output, wski18n.T("{{.ok}} the deployed project matches the manifest\n",
+				map[string]interface{}{"ok": color.GreenString("ok:")}))
+			return nil
+		}
+
+		counts := printProjectDrift(drift)
+		errStr := wski18n.T("The deployed project drifted from the manifest: {{.added}} added, {{.modified}} modified, {{.deleted}} deleted",
+			map[string]interface{}{"added": counts[PLAN_DELETE], "modified": counts[PLAN_UPDATE], "deleted": counts[PLAN_CREATE]})
+		return whisk.MakeWskError(errors.New(errStr), EXIT_CODE_DRIFT, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	},
+}
+
+// findProjectDrift returns the changes a plan of the project would make, other than no-ops, so that an
+// entity to create was deleted, one to update modified and one to delete added. Actions added to the
+// packages of the project without its annotation, and the routes of its APIs, are compared as well.
+func findProjectDrift(project string, deployment *deployers.DeploymentProject) ([]*planChange, error) {
+	plan, err := planProject(project, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	var drift []*planChange
+	found := make(map[string]bool)
+	for _, change := range plan.Changes {
+		if change.Operation != PLAN_NOOP {
+			drift = append(drift, change)
+		}
+		found[change.Kind+" "+change.Name] = true
+	}
+
+	var packageNames []string
+	for name := range deployment.Packages {
+		if name != parsers.DEFAULT_PACKAGE {
+			packageNames = append(packageNames, name)
+		}
+	}
+	sort.Strings(packageNames)
+
+	for _, packageName := range packageNames {
+		var added []string
+		err := listAllPages(0, func(skip int, limit int) (int, error) {
+			options := &whisk.ActionListOptions{Skip: skip, Limit: limit}
+			page, response, err := Client.Actions.List(packageName, options)
+			if err != nil && response != nil && response.StatusCode == http.StatusNotFound {
+				return 0, nil
+			} else if err != nil {
+				return 0, actionListError(packageName, options, err)
+			}
+			for _, action := range page {
+				if name := packageName + "/" + action.Name; !found[PLAN_ACTION+" "+name] {
+					added = append(added, name)
+				}
+			}
+			return len(page), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(added)
+		for _, name := range added {
+			drift = append(drift, &planChange{Operation: PLAN_DELETE, Kind: PLAN_ACTION, Name: name})
+		}
+	}
+
+	if len(deployment.Apis) > 0 {
+		deployed, err := getApiRoutes(deployment)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, compareApiRoutes(declaredApiRoutes(deployment), deployed)...)
+	}
+	return drift, nil
+}
+
+// declaredApiRoutes maps the "VERB /basepath/relpath" routes of the APIs of a deployment to the names
+// of their actions
+func declaredApiRoutes(deployment *deployers.DeploymentProject) map[string]string {
+	routes := make(map[string]string)
+	for _, request := range deployment.Apis {
+		if doc := request.ApiDoc; doc != nil && doc.Action != nil {
+			route := strings.ToUpper(doc.GatewayMethod) + " " + doc.GatewayBasePath + doc.GatewayRelPath
+			routes[route] = planReferenceName(doc.Action.Name)
+		}
+	}
+	return routes
+}
+
+// getApiRoutes gets the routes of the deployed APIs with the base paths of a deployment
+func getApiRoutes(deployment *deployers.DeploymentProject) (map[string]string, error) {
+	basePaths := make(map[string]bool)
+	for _, request := range deployment.Apis {
+		if request.ApiDoc != nil {
+			basePaths[request.ApiDoc.GatewayBasePath] = true
+		}
+	}
+
+	accessToken := Client.Config.ApigwAccessToken
+	if len(accessToken) == 0 {
+		var err error
+		if accessToken, err = getAccessToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	routes := make(map[string]string)
+	for basePath := range basePaths {
+		options := &whisk.ApiGetRequestOptions{
+			ApiBasePath: basePath,
+			SpaceGuid:   strings.Split(Client.Config.AuthToken, ":")[0],
+			AccessToken: accessToken,
+		}
+		apis, _, err := Client.Apis.Get(new(whisk.ApiGetRequest), options)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "Client.Apis.Get(%#v) error: %s\n", options, err)
+			errStr := wski18n.T("Unable to get API '{{.name}}': {{.err}}", map[string]interface{}{"name": basePath, "err": err})
+			return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+
+		for _, api := range apis.Apis {
+			if api.ApiValue == nil || api.ApiValue.Swagger == nil {
+				continue
+			}
+			for _, route := range genFilteredList(api.ApiValue, "", "") {
+				routes[strings.ToUpper(route.Verb)+" "+route.BasePath+route.RelPath] = planReferenceName(route.ActionName)
+			}
+		}
+	}
+	return routes, nil
+}
+
+// compareApiRoutes returns the declared routes that are not deployed, the deployed ones that are not
+// declared and the ones whose action differs
+func compareApiRoutes(declared map[string]string, deployed map[string]string) []*planChange {
+	var drift []*planChange
+	for route, action := range declared {
+		if deployedAction, exists := deployed[route]; !exists {
+			drift = append(drift, &planChange{Operation: PLAN_CREATE, Kind: DRIFT_API, Name: route})
+		} else if deployedAction != action {
+			drift = append(drift, &planChange{Operation: PLAN_UPDATE, Kind: DRIFT_API, Name: route,
+				Diffs: []planDiff{{Field: "action", Old: deployedAction, New: action}}})
+		}
+	}
+	for route := range deployed {
+		if _, exists := declared[route]; !exists {
+			drift = append(drift, &planChange{Operation: PLAN_DELETE, Kind: DRIFT_API, Name: route})
+		}
+	}
+
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Name < drift[j].Name })
+	return drift
+}
+
+// printProjectDrift prints what was added, modified and deleted compared with the manifest, and
+// returns the number of each by plan operation
+func printProjectDrift(drift []*planChange) map[string]int {
+	counts := make(map[string]int)
+	for _, change := range drift {
+		counts[change.Operation]++
+
+		switch change.Operation {
+		case PLAN_DELETE:
+			fmt.Fprintf(color.Output, "  %s %s %s %s\n", color.GreenString("+"), change.Kind, boldString(change.Name),
+				wski18n.T("(added, not in the manifest)"))
+		case PLAN_UPDATE:
+			fmt.Fprintf(color.Output, "  %s %s %s %s\n", color.YellowString("~"), change.Kind, boldString(change.Name),
+				wski18n.T("(modified)"))
+		case PLAN_CREATE:
+			fmt.Fprintf(color.Output, "  %s %s %s %s\n", color.RedString("-"), change.Kind, boldString(change.Name),
+				wski18n.T("(deleted, declared in the manifest)"))
+		}
+		for _, diff := range change.Diffs {
+			fmt.Fprintf(color.Output, "      %s: %s %s\n", diff.Field, planValueString(diff.Old),
+				wski18n.T("(manifest: {{.value}})", map[string]interface{}{"value": planValueString(diff.New)}))
+		}
+	}
+	return counts
+}
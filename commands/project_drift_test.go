@@ -0,0 +1,78 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindProjectDrift(t *testing.T) {
+	entities := entityStoreTestEntities()
+	entities["packages/tools"] = `{"annotations": [{"key": "whisk-managed", "value": {"projectName": "demo"}}]}`
+	entities["actions/tools/hello"] = `{"exec": {"kind": "nodejs:default", "code": "function main() { return {} }"},
+		"parameters": [{"key": "debug", "value": true}]}`
+	entities["actions/tools/fresh"] = `{"exec": {"kind": "nodejs:default", "code": "function main() { return {} }"}}`
+	entities["actions/tools/patch"] = `{"exec": {"kind": "nodejs:default", "code": "function main() {}"}}`
+	entities["rules/greet"] = `{"trigger": "/guest/ticks", "action": "/guest/tools/hello"}`
+	entities["triggers/ticks"] = `{"annotations": [{"key": "whisk-managed", "value": {"projectName": "demo"}}]}`
+	newEntityStore(t, entities)
+
+	deployment := projectPlanTestDeployment()
+	delete(deployment.Packages["tools"].Sequences, "steps")
+	drift, err := findProjectDrift("demo", deployment)
+	assert.Nil(t, err)
+
+	var found []string
+	for _, change := range drift {
+		found = append(found, change.Operation+" "+change.Kind+" "+change.Name)
+	}
+	assert.Equal(t, []string{
+		"update package tools",
+		"update action tools/hello",
+		"delete trigger ticks",
+		"delete action tools/patch",
+		"delete action tools/steps",
+	}, found)
+	assert.Equal(t, []planDiff{{Field: "parameters.region", New: "eu"}}, drift[0].Diffs)
+	assert.Equal(t, []planDiff{{Field: "parameters.debug", Old: true}}, drift[1].Diffs)
+}
+
+func TestCompareApiRoutes(t *testing.T) {
+	declared := map[string]string{
+		"GET /books/list":  "library/list",
+		"POST /books/add":  "library/add",
+		"GET /books/count": "library/count",
+	}
+	deployed := map[string]string{
+		"GET /books/list":    "library/list",
+		"POST /books/add":    "library/add-v2",
+		"DELETE /books/drop": "library/drop",
+	}
+
+	drift := compareApiRoutes(declared, deployed)
+	assert.Equal(t, []*planChange{
+		{Operation: PLAN_DELETE, Kind: DRIFT_API, Name: "DELETE /books/drop"},
+		{Operation: PLAN_CREATE, Kind: DRIFT_API, Name: "GET /books/count"},
+		{Operation: PLAN_UPDATE, Kind: DRIFT_API, Name: "POST /books/add",
+			Diffs: []planDiff{{Field: "action", Old: "library/add-v2", New: "library/add"}}},
+	}, drift)
+}
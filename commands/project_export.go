@@ -0,0 +1,623 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/apache/openwhisk-wskdeploy/deployers"
+	"github.com/apache/openwhisk-wskdeploy/parsers"
+	"github.com/apache/openwhisk-wskdeploy/utils"
+
+	"github.com/fatih/color"
+	"github.com/ghodss/yaml"
+)
+
+// Annotations the controller or wskdeploy set themselves, which a manifest leaves out
+var EXPORT_IGNORED_ANNOTATIONS = append([]string{"feed"}, PLAN_IGNORED_ANNOTATIONS...)
+
+// The parts of the wskdeploy manifest schema that an export of a namespace writes, which serve for
+// its deployment file as well
+type manifestFile struct {
+	Project manifestProject `json:"project"`
+}
+
+type manifestProject struct {
+	Name      string                      `json:"name,omitempty"`
+	Namespace string                      `json:"namespace,omitempty"`
+	Packages  map[string]*manifestPackage `json:"packages,omitempty"`
+}
+
+type manifestPackage struct {
+	Public       bool                                                         `json:"public,omitempty"`
+	Inputs       map[string]manifestParameter                                 `json:"inputs,omitempty"`
+	Annotations  map[string]interface{}                                       `json:"annotations,omitempty"`
+	Dependencies map[string]*manifestDependency                               `json:"dependencies,omitempty"`
+	Actions      map[string]*manifestAction                                   `json:"actions,omitempty"`
+	Sequences    map[string]*manifestSequence                                 `json:"sequences,omitempty"`
+	Triggers     map[string]*manifestTrigger                                  `json:"triggers,omitempty"`
+	Rules        map[string]*manifestRule                                     `json:"rules,omitempty"`
+	Apis         map[string]map[string]map[string]map[string]manifestApiRoute `json:"apis,omitempty"`
+}
+
+type manifestDependency struct {
+	Location string                       `json:"location"`
+	Inputs   map[string]manifestParameter `json:"inputs,omitempty"`
+}
+
+type manifestAction struct {
+	Function    string                       `json:"function,omitempty"`
+	Runtime     string                       `json:"runtime,omitempty"`
+	Docker      string                       `json:"docker,omitempty"`
+	Main        string                       `json:"main,omitempty"`
+	Limits      *manifestLimits              `json:"limits,omitempty"`
+	Inputs      map[string]manifestParameter `json:"inputs,omitempty"`
+	Annotations map[string]interface{}       `json:"annotations,omitempty"`
+}
+
+type manifestLimits struct {
+	Timeout *int `json:"timeout,omitempty"`
+	Memory  *int `json:"memorySize,omitempty"`
+	Logsize *int `json:"logSize,omitempty"`
+}
+
+type manifestSequence struct {
+	Actions     string                 `json:"actions"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+}
+
+type manifestTrigger struct {
+	Feed        string                       `json:"feed,omitempty"`
+	Inputs      map[string]manifestParameter `json:"inputs,omitempty"`
+	Annotations map[string]interface{}       `json:"annotations,omitempty"`
+}
+
+type manifestRule struct {
+	Trigger     string                 `json:"trigger"`
+	Action      string                 `json:"action"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+}
+
+type manifestApiRoute struct {
+	Method   string `json:"method"`
+	Response string `json:"response"`
+}
+
+// manifestParameter declares the type of an input, so that wskdeploy neither guesses it from the value
+// nor takes a string value naming a type for that type
+type manifestParameter struct {
+	Type  string      `json:"type,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// namespaceExport collects the entities of a namespace as the packages of a manifest, with the files
+// of the code of its actions. Root actions, triggers and rules go in the default package, which
+// wskdeploy deploys to the namespace itself.
+type namespaceExport struct {
+	namespace string
+	packages  map[string]*manifestPackage
+	files     map[string][]byte
+	warnings  []string
+	counts    map[string]int
+}
+
+func newNamespaceExport(namespace string) *namespaceExport {
+	return &namespaceExport{
+		namespace: namespace,
+		packages:  make(map[string]*manifestPackage),
+		files:     make(map[string][]byte),
+		counts:    make(map[string]int),
+	}
+}
+
+// exportNamespace writes a manifest and a deployment file that recreate every entity of the namespace,
+// whether or not a project deployed it, with the code of its actions next to them
+func exportNamespace() error {
+	config, err := deployers.NewWhiskConfig(utils.Flags.CfgFile, "", "")
+	if err != nil {
+		return projectConfigError(err)
+	}
+	if err := setProjectClient(config); err != nil {
+		return err
+	}
+
+	if len(Client.Namespace) == 0 || Client.Namespace == "_" {
+		Client.Namespace = getNamespace()
+	}
+	namespace := Client.Namespace
+
+	dir := strings.TrimSpace(utils.Flags.ProjectPath)
+	manifestName := utils.ManifestFileNameYaml
+	if len(utils.Flags.ManifestPath) > 0 {
+		dir, manifestName = filepath.Split(utils.Flags.ManifestPath)
+	}
+	if len(dir) == 0 {
+		dir = utils.DEFAULT_PROJECT_PATH
+	}
+	deploymentName := utils.DeploymentFileNameYaml
+	if len(utils.Flags.DeploymentPath) > 0 {
+		deploymentName = filepath.Base(utils.Flags.DeploymentPath)
+	}
+
+	export, err := exportNamespaceEntities(namespace)
+	if err != nil {
+		return err
+	}
+	export.addApis()
+
+	if err := export.addManifests(manifestName, deploymentName, utils.Flags.ProjectName); err != nil {
+		return err
+	}
+	// The code files are named as saveCode names them, but are written together with the manifests by
+	// writeProjectFiles, which checks that none of them exists before writing any, so that a failed
+	// export does not leave part of a project behind
+	if _, err := writeProjectFiles(dir, export.files); err != nil {
+		return err
+	}
+
+	for _, warning := range export.warnings {
+		printWarning(warning)
+	}
+	fmt.Fprintf(color.Output, wski18n.T("{{.ok}} exported {{.packages}} packages, {{.actions}} actions, {{.triggers}} triggers, {{.rules}} rules and {{.apis}} API routes of namespace {{.name}} to {{.manifest}}\n",
+		map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(namespace),
+			"packages": export.counts[PLAN_PACKAGE], "actions": export.counts[PLAN_ACTION], "triggers": export.counts[PLAN_TRIGGER],
+			"rules": export.counts[PLAN_RULE], "apis": export.counts[DRIFT_API], "manifest": filepath.Join(dir, manifestName)}))
+	return nil
+}
+
+// exportNamespaceEntities collects the packages, actions, triggers and rules of a namespace
+func exportNamespaceEntities(namespace string) (*namespaceExport, error) {
+	export := newNamespaceExport(namespace)
+	export.pkg(parsers.DEFAULT_PACKAGE)
+
+	err := listAllPages(0, func(skip int, limit int) (int, error) {
+		packages, err := listPackages(&whisk.PackageListOptions{Skip: skip, Limit: limit})
+		if err != nil {
+			return 0, err
+		}
+		for _, listed := range packages {
+			if err := export.addPackage(listed.Name); err != nil {
+				return 0, err
+			}
+		}
+		return len(packages), nil
+	})
+	if err == nil {
+		err = listAllPages(0, func(skip int, limit int) (int, error) {
+			options := &whisk.ActionListOptions{Skip: skip, Limit: limit}
+			actions, _, err := Client.Actions.List("", options)
+			if err != nil {
+				return 0, actionListError("", options, err)
+			}
+			for _, listed := range actions {
+				if err := export.addAction(listed.Namespace, listed.Name); err != nil {
+					return 0, err
+				}
+			}
+			return len(actions), nil
+		})
+	}
+	if err == nil {
+		err = listAllPages(0, func(skip int, limit int) (int, error) {
+			triggers, err := listTriggers(&whisk.TriggerListOptions{Skip: skip, Limit: limit})
+			if err != nil {
+				return 0, err
+			}
+			for _, listed := range triggers {
+				if err := export.addTrigger(listed.Name); err != nil {
+					return 0, err
+				}
+			}
+			return len(triggers), nil
+		})
+	}
+	if err == nil {
+		err = listAllPages(0, func(skip int, limit int) (int, error) {
+			rules, err := listRules(&whisk.RuleListOptions{Skip: skip, Limit: limit})
+			if err != nil {
+				return 0, err
+			}
+			for _, listed := range rules {
+				if err := export.addRule(listed.Namespace, listed.Name); err != nil {
+					return 0, err
+				}
+			}
+			return len(rules), nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// pkg returns the manifest package of a name, adding it when there is none
+func (export *namespaceExport) pkg(name string) *manifestPackage {
+	if _, exists := export.packages[name]; !exists {
+		export.packages[name] = &manifestPackage{}
+	}
+	return export.packages[name]
+}
+
+func (export *namespaceExport) warn(message string) {
+	export.warnings = append(export.warnings, message)
+}
+
+// addPackage adds a package, or a dependency of the default package on what a binding binds
+func (export *namespaceExport) addPackage(name string) error {
+	xPackage, _, err := Client.Packages.Get(name)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Packages.Get(%s) failed: %s\n", name, err)
+		errStr := wski18n.T("Unable to get package '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	export.counts[PLAN_PACKAGE]++
+
+	if xPackage.Binding != nil && len(xPackage.Binding.Name) > 0 {
+		if xPackage.Binding.Namespace == export.namespace {
+			export.warn(wski18n.T("Package binding '{{.name}}' binds a package of the namespace, which wskdeploy may not have created yet when it creates the binding",
+				map[string]interface{}{"name": name}))
+		}
+		defaultPackage := export.pkg(parsers.DEFAULT_PACKAGE)
+		if defaultPackage.Dependencies == nil {
+			defaultPackage.Dependencies = make(map[string]*manifestDependency)
+		}
+		defaultPackage.Dependencies[name] = &manifestDependency{
+			Location: "/" + xPackage.Binding.Namespace + "/" + xPackage.Binding.Name,
+			Inputs:   exportInputs(xPackage.Parameters),
+		}
+		return nil
+	}
+
+	exported := export.pkg(name)
+	exported.Public = xPackage.Publish != nil && *xPackage.Publish
+	exported.Inputs = exportInputs(xPackage.Parameters)
+	exported.Annotations = exportAnnotations(xPackage.Annotations)
+	return nil
+}
+
+// addAction adds an action, writing its code to a file in the directory of its package, or a sequence
+func (export *namespaceExport) addAction(entityPath string, name string) error {
+	packageName := parsers.DEFAULT_PACKAGE
+	if parts := strings.SplitN(entityPath, "/", 2); len(parts) == 2 {
+		packageName = parts[1]
+		name = packageName + "/" + name
+	}
+	if exported, exists := export.packages[packageName]; !exists || exported == nil {
+		// the actions of bindings are those of the package that they bind
+		return nil
+	}
+
+	action, _, err := Client.Actions.Get(name, FETCH_CODE)
+	if err != nil {
+		return actionGetError(name, FETCH_CODE, err)
+	}
+	export.counts[PLAN_ACTION]++
+	exported := export.pkg(packageName)
+
+	if action.Exec.Kind == SEQUENCE {
+		var components []string
+		for _, component := range action.Exec.Components {
+			reference, ok := export.reference(component)
+			if !ok || (!strings.Contains(reference, "/") && packageName != parsers.DEFAULT_PACKAGE) {
+				export.warn(wski18n.T("Sequence '{{.name}}' runs '{{.component}}', which a manifest cannot refer to from package '{{.package}}'",
+					map[string]interface{}{"name": name, "component": component, "package": packageName}))
+			}
+			if !ok {
+				reference = strings.TrimPrefix(component, "/")
+			}
+			components = append(components, reference)
+		}
+		if len(action.Parameters) > 0 {
+			export.warn(wski18n.T("Sequence '{{.name}}' has parameters, which a manifest cannot declare for a sequence",
+				map[string]interface{}{"name": name}))
+		}
+
+		if exported.Sequences == nil {
+			exported.Sequences = make(map[string]*manifestSequence)
+		}
+		exported.Sequences[action.Name] = &manifestSequence{
+			Actions:     strings.Join(components, ","),
+			Annotations: exportAnnotations(action.Annotations),
+		}
+		return nil
+	}
+
+	exportedAction := &manifestAction{
+		Main:        action.Exec.Main,
+		Inputs:      exportInputs(action.Parameters),
+		Annotations: exportAnnotations(action.Annotations),
+	}
+	runtime := strings.Split(action.Exec.Kind, ":")[0]
+	if runtime == BLACKBOX {
+		exportedAction.Docker = action.Exec.Image
+	} else {
+		exportedAction.Runtime = action.Exec.Kind
+	}
+	if limits := action.Limits; limits != nil {
+		exportedAction.Limits = &manifestLimits{Timeout: limits.Timeout, Memory: limits.Memory, Logsize: limits.Logsize}
+		if limits.Concurrency != nil && *limits.Concurrency > 1 {
+			export.warn(wski18n.T("Action '{{.name}}' has a concurrency limit of {{.limit}}, which a manifest cannot declare",
+				map[string]interface{}{"name": name, "limit": *limits.Concurrency}))
+		}
+	}
+
+	if action.Exec.Code != nil {
+		code := []byte(*action.Exec.Code)
+		if action.Exec.Binary != nil && *action.Exec.Binary {
+			if code, err = base64.StdEncoding.DecodeString(*action.Exec.Code); err != nil {
+				whisk.Debug(whisk.DbgError, "Decode of the code of '%s' failed: %s\n", name, err)
+				errStr := wski18n.T("Unable to decode the code of action '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+				return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			}
+		}
+		exportedAction.Function = packageName + "/" + actionCodeFilename(action)
+		export.files[exportedAction.Function] = code
+	}
+
+	if exported.Actions == nil {
+		exported.Actions = make(map[string]*manifestAction)
+	}
+	exported.Actions[action.Name] = exportedAction
+	return nil
+}
+
+// addTrigger adds a trigger to the default package. The inputs of a trigger with a feed are the
+// configuration its feed action reads back, where it can.
+func (export *namespaceExport) addTrigger(name string) error {
+	trigger, _, err := Client.Triggers.Get(name)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Triggers.Get(%s) failed: %s\n", name, err)
+		errStr := wski18n.T("Unable to get trigger '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	export.counts[PLAN_TRIGGER]++
+
+	exported := &manifestTrigger{
+		Feed:        getValueString(trigger.Annotations, "feed"),
+		Inputs:      exportInputs(trigger.Parameters),
+		Annotations: exportAnnotations(trigger.Annotations),
+	}
+	if len(exported.Feed) > 0 {
+		result, err := invokeTriggerFeed(name, exported.Feed, FEED_READ, nil)
+		config, _ := result["config"].(map[string]interface{})
+		if err != nil || config == nil {
+			export.warn(wski18n.T("The feed of trigger '{{.name}}' did not return its configuration, so the trigger has only its own parameters",
+				map[string]interface{}{"name": name}))
+		}
+		for key, value := range config {
+			if key != "startDate" && key != FEED_AUTH_KEY && key != FEED_TRIGGER_NAME && key != FEED_LIFECYCLE_EVENT {
+				if exported.Inputs == nil {
+					exported.Inputs = make(map[string]manifestParameter)
+				}
+				exported.Inputs[key] = exportInput(value)
+			}
+		}
+	}
+
+	defaultPackage := export.pkg(parsers.DEFAULT_PACKAGE)
+	if defaultPackage.Triggers == nil {
+		defaultPackage.Triggers = make(map[string]*manifestTrigger)
+	}
+	defaultPackage.Triggers[name] = exported
+	return nil
+}
+
+// addRule adds a rule to the default package, where the names of actions in packages include them
+func (export *namespaceExport) addRule(namespace string, name string) error {
+	// listRules has fetched the rule for its status, so it comes from the rule cache
+	rule, err := getRule(namespace, name)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "getRule(%s) failed: %s\n", name, err)
+		errStr := wski18n.T("Unable to get rule '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	export.counts[PLAN_RULE]++
+
+	action := ruleEntityFullName(ruleEntityPath(rule.Action))
+	reference, ok := export.reference(action)
+	if !ok {
+		export.warn(wski18n.T("Rule '{{.name}}' invokes '{{.action}}' of another namespace, which a manifest cannot refer to",
+			map[string]interface{}{"name": name, "action": action}))
+		reference = strings.TrimPrefix(action, "/")
+	}
+	if rule.Status != "" && rule.Status != "active" {
+		export.warn(wski18n.T("Rule '{{.name}}' is {{.status}}, but wskdeploy creates rules active",
+			map[string]interface{}{"name": name, "status": rule.Status}))
+	}
+
+	_, _, triggerName := ruleEntityPath(rule.Trigger)
+	defaultPackage := export.pkg(parsers.DEFAULT_PACKAGE)
+	if defaultPackage.Rules == nil {
+		defaultPackage.Rules = make(map[string]*manifestRule)
+	}
+	defaultPackage.Rules[name] = &manifestRule{
+		Trigger:     triggerName,
+		Action:      reference,
+		Annotations: exportAnnotations(rule.Annotations),
+	}
+	return nil
+}
+
+// addApis adds the routes of the APIs of the namespace. The API gateway is optional, so APIs that
+// cannot be listed only give a warning.
+func (export *namespaceExport) addApis() {
+	options := new(whisk.ApiListRequestOptions)
+	var err error
+	if options.SpaceGuid, err = getUserContextId(); err == nil {
+		options.AccessToken, err = getAccessToken()
+	}
+
+	if err == nil {
+		err = listAllPages(0, func(skip int, limit int) (int, error) {
+			options.Limit = limit
+			options.Skip = skip
+			apis, _, err := Client.Apis.List(options)
+			if err != nil {
+				whisk.Debug(whisk.DbgError, "Client.Apis.List(%#v) error: %s\n", options, err)
+				return 0, err
+			}
+			for _, api := range apis.Apis {
+				if api.ApiValue != nil {
+					export.addApi(api.ApiValue)
+				}
+			}
+			return len(apis.Apis), nil
+		})
+	}
+	if err != nil {
+		export.warn(wski18n.T("Unable to obtain the API list, so no APIs were exported: {{.err}}", map[string]interface{}{"err": err}))
+	}
+}
+
+// addApi adds the routes of an API to the packages of their actions, as wskdeploy only routes to
+// actions of the package that declares the API
+func (export *namespaceExport) addApi(api *whisk.RetApi) {
+	if api.Swagger == nil || api.Swagger.Info == nil {
+		return
+	}
+	name := api.Swagger.Info.Title
+	basePath := strings.TrimPrefix(api.Swagger.BasePath, "/")
+
+	for swaggerPath, operations := range api.Swagger.Paths {
+		for verb, operation := range operations.MakeOperationMap() {
+			target := operation.XOpenWhisk
+			if target == nil || (target.Namespace != export.namespace && target.Namespace != "_") {
+				export.warn(wski18n.T("The route {{.verb}} {{.path}} of API '{{.name}}' is not to an action of the namespace",
+					map[string]interface{}{"verb": strings.ToUpper(verb), "path": api.Swagger.BasePath + swaggerPath, "name": name}))
+				continue
+			}
+
+			packageName := target.Package
+			if len(packageName) == 0 {
+				packageName = parsers.DEFAULT_PACKAGE
+			}
+			exported := export.pkg(packageName)
+			if exported.Apis == nil {
+				exported.Apis = make(map[string]map[string]map[string]map[string]manifestApiRoute)
+			}
+			if exported.Apis[name] == nil {
+				exported.Apis[name] = make(map[string]map[string]map[string]manifestApiRoute)
+			}
+			if exported.Apis[name][basePath] == nil {
+				exported.Apis[name][basePath] = make(map[string]map[string]manifestApiRoute)
+			}
+			relPath := strings.TrimPrefix(swaggerPath, "/")
+			if exported.Apis[name][basePath][relPath] == nil {
+				exported.Apis[name][basePath][relPath] = make(map[string]manifestApiRoute)
+			}
+			if _, exists := exported.Apis[name][basePath][relPath][target.ActionName]; exists {
+				export.warn(wski18n.T("Action '{{.action}}' serves more than one verb of {{.path}} in API '{{.name}}', which a manifest cannot declare",
+					map[string]interface{}{"action": target.ActionName, "path": api.Swagger.BasePath + swaggerPath, "name": name}))
+			}
+
+			response := strings.TrimPrefix(path.Ext(target.ApiUrl), ".")
+			if len(response) == 0 {
+				response = "json"
+			}
+			exported.Apis[name][basePath][relPath][target.ActionName] = manifestApiRoute{Method: strings.ToUpper(verb), Response: response}
+			export.counts[DRIFT_API]++
+		}
+	}
+}
+
+// reference returns how a manifest refers to an action of the namespace given its fully qualified
+// name, which is package/action, or just action for one in the default package
+func (export *namespaceExport) reference(qualifiedName string) (string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(qualifiedName, "/"), "/", 2)
+	if len(parts) != 2 || (parts[0] != export.namespace && parts[0] != "_") {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// addManifests adds the manifest, and a deployment file that binds it to the namespace
+func (export *namespaceExport) addManifests(manifestName string, deploymentName string, project string) error {
+	if p := export.packages[parsers.DEFAULT_PACKAGE]; len(p.Dependencies)+len(p.Actions)+len(p.Sequences)+len(p.Triggers)+len(p.Rules)+len(p.Apis) == 0 {
+		delete(export.packages, parsers.DEFAULT_PACKAGE)
+	}
+
+	manifest := manifestFile{Project: manifestProject{Name: project, Packages: export.packages}}
+	deployment := manifestFile{Project: manifestProject{Name: project, Namespace: export.namespace}}
+	for name, contents := range map[string]interface{}{manifestName: manifest, deploymentName: deployment} {
+		content, err := yaml.Marshal(contents)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "yaml.Marshal() of '%s' error: %s\n", name, err)
+			errStr := wski18n.T("Unable to write '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+			return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+		export.files[name] = content
+	}
+	return nil
+}
+
+func exportInputs(parameters whisk.KeyValueArr) map[string]manifestParameter {
+	if len(parameters) == 0 {
+		return nil
+	}
+	inputs := make(map[string]manifestParameter)
+	for _, kv := range parameters {
+		inputs[kv.Key] = exportInput(kv.Value)
+	}
+	return inputs
+}
+
+// exportInput declares the type of a parameter value as the client decodes it, with numbers left as
+// they were written
+func exportInput(value interface{}) manifestParameter {
+	switch value := value.(type) {
+	case string:
+		return manifestParameter{Type: parsers.STRING, Value: value}
+	case bool:
+		return manifestParameter{Type: parsers.BOOLEAN, Value: value}
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return manifestParameter{Type: parsers.INTEGER, Value: value}
+		}
+		return manifestParameter{Type: parsers.FLOAT, Value: value}
+	case map[string]interface{}:
+		return manifestParameter{Type: parsers.JSON, Value: value}
+	default:
+		return manifestParameter{Value: value}
+	}
+}
+
+func exportAnnotations(annotations whisk.KeyValueArr) map[string]interface{} {
+	exported := make(map[string]interface{})
+	for _, kv := range annotations {
+		if !contains(EXPORT_IGNORED_ANNOTATIONS, kv.Key) {
+			exported[kv.Key] = kv.Value
+		}
+	}
+	if len(exported) == 0 {
+		return nil
+	}
+	return exported
+}
+
+func init() {
+	projectExportCmd.Flags().BoolVar(&Flags.project.all, "all", false, wski18n.T("export every entity of the namespace, whether or not a project deployed it"))
+}
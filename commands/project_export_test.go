@@ -0,0 +1,97 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/apache/openwhisk-wskdeploy/parsers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportNamespace(t *testing.T) {
+	entities := entityStoreTestEntities()
+	entities["packages/tools"] = `{"publish": true, "parameters": [{"key": "region", "value": "eu"}]}`
+	entities["packages/kit"] = `{"binding": {"namespace": "whisk.system", "name": "utils"}}`
+	entities["actions/tools/hello"] = `{"exec": {"kind": "nodejs:default", "code": "function main() {}"},
+		"parameters": [{"key": "retries", "value": 3}, {"key": "mode", "value": "json"}],
+		"annotations": [{"key": "web-export", "value": true}, {"key": "exec", "value": "nodejs"}],
+		"limits": {"timeout": 60000, "memory": 256, "logs": 10, "concurrency": 1}}`
+	entities["actions/tools/bundle"] = `{"exec": {"kind": "python:3", "code": "UEsDBA==", "binary": true, "main": "run"}}`
+	entities["actions/tools/steps"] = `{"exec": {"kind": "sequence", "components": ["/guest/tools/hello", "/guest/other"]}}`
+	entities["triggers/ticks"] = `{"parameters": [{"key": "every", "value": 1.5}]}`
+	newEntityStore(t, entities)
+
+	export, err := exportNamespaceEntities("guest")
+	assert.Nil(t, err)
+	export.addApi(&whisk.RetApi{Swagger: &whisk.ApiSwagger{
+		BasePath: "/books",
+		Info:     &whisk.ApiSwaggerInfo{Title: "books-api"},
+		Paths: map[string]*whisk.ApiSwaggerPath{"/list": {Get: &whisk.ApiSwaggerOperation{XOpenWhisk: &whisk.ApiSwaggerOpXOpenWhisk{
+			ActionName: "hello", Namespace: "guest", Package: "tools", ApiUrl: "https://example.com/api/v1/web/guest/tools/hello.http"}}}},
+	}})
+	assert.Equal(t, []string{
+		"Sequence 'tools/steps' runs '/guest/other', which a manifest cannot refer to from package 'tools'",
+		"Rule 'greet' is inactive, but wskdeploy creates rules active",
+	}, export.warnings)
+
+	dir := t.TempDir()
+	assert.Nil(t, export.addManifests("manifest.yaml", "deployment.yaml", "demo"))
+	names, err := writeProjectFiles(dir, export.files)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"default/other.js", "deployment.yaml", "manifest.yaml", "tools/bundle.zip", "tools/hello.js"}, names)
+
+	code, err := ioutil.ReadFile(filepath.Join(dir, "tools", "bundle.zip"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("PK\x03\x04"), code)
+
+	// wskdeploy reads back what the namespace has
+	manifest, err := parsers.NewYAMLParser().ParseManifest(filepath.Join(dir, "manifest.yaml"))
+	assert.Nil(t, err)
+	project := manifest.GetProject()
+	assert.Equal(t, "demo", project.Name)
+
+	tools := project.Packages["tools"]
+	assert.True(t, tools.Public)
+	assert.Equal(t, "eu", tools.Inputs["region"].Value)
+	hello := tools.Actions["hello"]
+	assert.Equal(t, "tools/hello.js", hello.Function)
+	assert.Equal(t, "nodejs:default", hello.Runtime)
+	assert.Equal(t, parsers.INTEGER, hello.Inputs["retries"].Type)
+	assert.Equal(t, "json", hello.Inputs["mode"].Value)
+	assert.Equal(t, map[string]interface{}{"web-export": true}, hello.Annotations)
+	assert.Equal(t, 256, *hello.Limits.Memory)
+	assert.Equal(t, "run", tools.Actions["bundle"].Main)
+	assert.Equal(t, "tools/hello,other", tools.Sequences["steps"].Actions)
+	assert.Equal(t, parsers.APIMethodResponse{Method: "GET", Response: "http"}, tools.Apis["books-api"]["books"]["list"]["hello"])
+
+	root := project.Packages[parsers.DEFAULT_PACKAGE]
+	assert.Equal(t, "tools/hello,other", root.Sequences["pipeline"].Actions)
+	assert.Equal(t, "/whisk.system/utils", root.Dependencies["kit"].Location)
+	assert.Equal(t, parsers.FLOAT, root.Triggers["ticks"].Inputs["every"].Type)
+	assert.Equal(t, parsers.Rule{Trigger: "ticks", Action: "tools/hello"}, root.Rules["greet"])
+
+	deployment, err := parsers.NewYAMLParser().ParseDeployment(filepath.Join(dir, "deployment.yaml"))
+	assert.Nil(t, err)
+	assert.Equal(t, "guest", deployment.GetProject().Namespace)
+}
@@ -0,0 +1,254 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-cli/wsktemplates"
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/apache/openwhisk-wskdeploy/utils"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const PROJECT_INIT_TEMPLATE = "webapi"
+const PROJECT_INIT_KIND = "nodejs:default"
+
+// Runtimes that templates have sample actions for, with the extension of their files. The files of a
+// template in a directory named after a runtime, e.g. actions/nodejs/hello.js, are only created for
+// that runtime, without the directory, e.g. actions/hello.js.
+var PROJECT_INIT_RUNTIMES = map[string]string{"nodejs": "js", "python": "py"}
+
+// projectTemplateData are the values that files of a template ending in .tmpl are given
+type projectTemplateData struct {
+	Project string
+	Package string
+	Runtime string // a kind, or a runtime for its default kind
+	Ext     string
+}
+
+var projectInitCmd = &cobra.Command{
+	Use:   "init [PATH]",
+	Short: wski18n.T("create a project from a template"),
+	Long: wski18n.T("Create a manifest, a deployment file, sample actions, a .wskignore and a test harness in PATH, " +
+		"the current directory by default, from the template webapi, pipeline, sequence or scheduled, or from a template directory."),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 0, 1, "Project init", wski18n.T("A project path is optional.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		files, err := loadProjectTemplate(Flags.project.template)
+		if err != nil {
+			return err
+		}
+		data, err := newProjectTemplateData(dir, Flags.project.kind)
+		if err != nil {
+			return err
+		}
+		rendered, err := renderProjectTemplate(files, data)
+		if err != nil {
+			return err
+		}
+		names, err := writeProjectFiles(dir, rendered)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} created project {{.name}} from template {{.template}} in {{.dir}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(data.Project),
+				"template": Flags.project.template, "dir": dir}))
+		for _, name := range names {
+			fmt.Fprintf(color.Output, "  %s\n", name)
+		}
+		return nil
+	},
+}
+
+// loadProjectTemplate returns the files of a template directory, or of an embedded template, with the
+// common files it does not replace
+func loadProjectTemplate(name string) (map[string][]byte, error) {
+	files, err := wsktemplates.Files(wsktemplates.COMMON)
+	if err != nil {
+		return nil, projectTemplateError(name, err)
+	}
+
+	var templateFiles map[string][]byte
+	if info, statErr := os.Stat(name); statErr == nil && info.IsDir() {
+		templateFiles = make(map[string][]byte)
+		err = filepath.Walk(name, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			relative, err := filepath.Rel(name, path)
+			if err == nil {
+				templateFiles[filepath.ToSlash(relative)], err = ioutil.ReadFile(path)
+			}
+			return err
+		})
+	} else if templateFiles, err = wsktemplates.Files(name); err == nil && templateFiles == nil {
+		errStr := wski18n.T("There is no template '{{.name}}'; the templates are {{.names}}",
+			map[string]interface{}{"name": name, "names": strings.Join(wsktemplates.Names(), ", ")})
+		return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_USAGE, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	if err != nil {
+		return nil, projectTemplateError(name, err)
+	}
+
+	if files == nil {
+		files = make(map[string][]byte)
+	}
+	for path, contents := range templateFiles {
+		files[path] = contents
+	}
+	return files, nil
+}
+
+// newProjectTemplateData names the project after --projectname or its directory, and its package after
+// the project
+func newProjectTemplateData(dir string, kind string) (*projectTemplateData, error) {
+	runtime := strings.SplitN(kind, ":", 2)[0]
+	ext, ok := PROJECT_INIT_RUNTIMES[runtime]
+	if !ok {
+		return nil, projectInitRuntimeError(kind)
+	}
+
+	data := &projectTemplateData{Project: utils.Flags.ProjectName, Runtime: kind, Ext: ext}
+	if !strings.Contains(kind, ":") || strings.HasSuffix(kind, ":default") {
+		data.Runtime = runtime
+	}
+	if len(data.Project) == 0 {
+		absolute, _ := filepath.Abs(dir)
+		data.Project = filepath.Base(absolute)
+	}
+
+	data.Package = strings.Trim(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(data.Project)), "-")
+	return data, nil
+}
+
+// renderProjectTemplate keeps the files of the runtime of the project and executes the ones ending
+// in .tmpl, which lose the suffix
+func renderProjectTemplate(files map[string][]byte, data *projectTemplateData) (map[string][]byte, error) {
+	rendered := make(map[string][]byte)
+	runtimes := make(map[string]bool)
+	runtime := strings.SplitN(data.Runtime, ":", 2)[0]
+
+	for name, contents := range files {
+		if parts := strings.SplitN(name, "/", 3); len(parts) == 3 && len(PROJECT_INIT_RUNTIMES[parts[1]]) > 0 {
+			runtimes[parts[1]] = true
+			if parts[1] != runtime {
+				continue
+			}
+			name = parts[0] + "/" + parts[2]
+		}
+
+		if strings.HasSuffix(name, ".tmpl") {
+			var buffer bytes.Buffer
+			tmpl, err := template.New(name).Option("missingkey=error").Parse(string(contents))
+			if err == nil {
+				err = tmpl.Execute(&buffer, data)
+			}
+			if err != nil {
+				whisk.Debug(whisk.DbgError, "Template of '%s' failed: %s\n", name, err)
+				errStr := wski18n.T("Unable to create '{{.name}}' from the template: {{.err}}", map[string]interface{}{"name": name, "err": err})
+				return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			}
+			name, contents = strings.TrimSuffix(name, ".tmpl"), buffer.Bytes()
+		}
+		rendered[name] = contents
+	}
+
+	if !runtimes[runtime] {
+		return nil, projectInitRuntimeError(data.Runtime)
+	}
+	return rendered, nil
+}
+
+// writeProjectFiles writes the files of a project, unless one of them already exists, and returns
+// their names in order
+func writeProjectFiles(dir string, files map[string][]byte) ([]string, error) {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+		if exists, err := FileExists(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return nil, err
+		} else if exists {
+			return nil, fileExistsError(filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, files[name], 0644)
+		}
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "Write of '%s' failed: %s\n", path, err)
+			errStr := wski18n.T("Unable to write '{{.name}}': {{.err}}", map[string]interface{}{"name": path, "err": err})
+			return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+	}
+	return names, nil
+}
+
+func projectTemplateError(name string, err error) error {
+	whisk.Debug(whisk.DbgError, "Read of template '%s' failed: %s\n", name, err)
+	errStr := wski18n.T("Unable to read the template '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func projectInitRuntimeError(kind string) error {
+	var runtimes []string
+	for runtime := range PROJECT_INIT_RUNTIMES {
+		runtimes = append(runtimes, runtime)
+	}
+	sort.Strings(runtimes)
+
+	errStr := wski18n.T("The template has no sample actions for '{{.kind}}'; the runtimes with sample actions are {{.runtimes}}",
+		map[string]interface{}{"kind": kind, "runtimes": strings.Join(runtimes, ", ")})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_USAGE, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func init() {
+	projectInitCmd.Flags().StringVar(&Flags.project.template, "template", PROJECT_INIT_TEMPLATE, wski18n.T("the `NAME` of a template, or a template directory"))
+	projectInitCmd.Flags().StringVar(&Flags.project.kind, "kind", PROJECT_INIT_KIND, wski18n.T("the `KIND` of runtime of the sample actions"))
+}
@@ -0,0 +1,61 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectInit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Text Tools")
+
+	files, err := loadProjectTemplate("sequence")
+	assert.Nil(t, err)
+	data, err := newProjectTemplateData(dir, "python:default")
+	assert.Nil(t, err)
+	assert.Equal(t, &projectTemplateData{Project: "Text Tools", Package: "text-tools", Runtime: "python", Ext: "py"}, data)
+
+	rendered, err := renderProjectTemplate(files, data)
+	assert.Nil(t, err)
+	names, err := writeProjectFiles(dir, rendered)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{".wskignore", "actions/format.py", "actions/parse.py", "actions/transform.py",
+		"deployment.yaml", "manifest.yaml", "test/cases.json", "test/run.py"}, names)
+
+	manifest, err := ioutil.ReadFile(filepath.Join(dir, "manifest.yaml"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(manifest), "name: Text Tools")
+	assert.Contains(t, string(manifest), "text-tools:")
+	assert.Contains(t, string(manifest), "function: actions/parse.py")
+	assert.NotContains(t, string(manifest), "{{")
+
+	// Nothing is written over existing files
+	_, err = writeProjectFiles(dir, rendered)
+	assert.NotNil(t, err)
+
+	_, err = loadProjectTemplate("nope")
+	assert.NotNil(t, err)
+	_, err = newProjectTemplateData(dir, "java:default")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,882 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/apache/openwhisk-wskdeploy/dependencies"
+	"github.com/apache/openwhisk-wskdeploy/deployers"
+	"github.com/apache/openwhisk-wskdeploy/parsers"
+	"github.com/apache/openwhisk-wskdeploy/runtimes"
+	"github.com/apache/openwhisk-wskdeploy/utils"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const PROJECT_PLAN_VERSION = 1
+const PROJECT_PLAN_FILE = "wskplan.json"
+
+// Operations of a project plan
+const PLAN_CREATE = "create"
+const PLAN_UPDATE = "update"
+const PLAN_DELETE = "delete"
+const PLAN_NOOP = "no-op"
+
+// Kinds of entities a project plan manages
+const PLAN_PACKAGE = "package"
+const PLAN_ACTION = "action"
+const PLAN_TRIGGER = "trigger"
+const PLAN_RULE = "rule"
+
+// Annotations that the controller or wskdeploy maintain, which a plan does not compare
+var PLAN_IGNORED_ANNOTATIONS = []string{"exec", "provide-api-key", utils.MANAGED}
+
+type projectPlan struct {
+	Version   int           `json:"version"`
+	Project   string        `json:"project,omitempty"`
+	APIHost   string        `json:"apihost"`
+	Namespace string        `json:"namespace"`
+	Changes   []*planChange `json:"changes"`
+}
+
+// planChange is one operation of a plan, holding the entity as it is to be deployed. Remote is the
+// version of the deployed entity when the plan was made, or nil when there was none, so that apply
+// can tell whether it changed since.
+type planChange struct {
+	Operation string         `json:"operation"`
+	Kind      string         `json:"kind"`
+	Name      string         `json:"name"`
+	Diffs     []planDiff     `json:"diffs,omitempty"`
+	Remote    *planRemote    `json:"remote,omitempty"`
+	Package   *whisk.Package `json:"package,omitempty"`
+	Action    *whisk.Action  `json:"action,omitempty"`
+	Trigger   *whisk.Trigger `json:"trigger,omitempty"`
+	Rule      *whisk.Rule    `json:"rule,omitempty"`
+}
+
+// planDiff is a field that differs, with a nil Old when it is added and a nil New when it is removed
+type planDiff struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+type planRemote struct {
+	Version string `json:"version"`
+	Updated int64  `json:"updated"`
+}
+
+var projectPlanCmd = &cobra.Command{
+	Use:           "plan",
+	Short:         wski18n.T("show the changes a deployment of the project would make and save them as a plan"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deployer, err := loadProjectDeployment()
+		if err != nil {
+			return err
+		}
+
+		plan, err := planProject(deployer.ProjectName, deployer.Deployment)
+		if err != nil {
+			return err
+		}
+
+		unplanned := len(deployer.Deployment.Apis) > 0 || deployer.Deployment.SwaggerApi != nil
+		for _, deployed := range deployer.Deployment.Packages {
+			unplanned = unplanned || len(deployed.Dependencies) > 0
+		}
+		if unplanned {
+			printWarning(wski18n.T("APIs and package dependencies are not part of the plan; deploy them with 'wsk project deploy'"))
+		}
+
+		printProjectPlan(plan)
+		if err := writeProjectPlan(Flags.project.out, plan); err != nil {
+			return err
+		}
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} saved the plan to {{.file}}; run 'wsk project apply {{.file}}' to execute it\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "file": Flags.project.out}))
+		return nil
+	},
+}
+
+var projectApplyCmd = &cobra.Command{
+	Use:           "apply PLANFILE",
+	Short:         wski18n.T("execute a plan saved by 'wsk project plan', unless the deployed entities changed since"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 1, 1, "Project apply", wski18n.T("A plan file is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		plan, err := readProjectPlan(args[0])
+		if err != nil {
+			return err
+		}
+
+		config, err := deployers.NewWhiskConfig(utils.Flags.CfgFile, utils.Flags.DeploymentPath, utils.Flags.ManifestPath)
+		if err != nil {
+			return projectConfigError(err)
+		}
+		if err := setProjectClient(config); err != nil {
+			return err
+		}
+		if Client.Config.Host != plan.APIHost {
+			errStr := wski18n.T("The plan was made for API host '{{.planned}}', not '{{.host}}'",
+				map[string]interface{}{"planned": plan.APIHost, "host": Client.Config.Host})
+			return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+		Client.Namespace = plan.Namespace
+
+		drifted, err := checkPlanDrift(plan)
+		if err != nil {
+			return err
+		}
+		if len(drifted) > 0 {
+			errStr := wski18n.T("The deployed entities changed since the plan was made, run 'wsk project plan' again: {{.entities}}",
+				map[string]interface{}{"entities": strings.Join(drifted, ", ")})
+			return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+
+		if err := applyProjectPlan(plan); err != nil {
+			return err
+		}
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} applied plan {{.file}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "file": boldString(args[0])}))
+		return nil
+	},
+}
+
+// loadProjectDeployment reads the manifest and deployment files the way 'wsk project deploy' does,
+// pointing the client at the credentials they resolve to
+func loadProjectDeployment() (*deployers.ServiceDeployer, error) {
+	projectPath := strings.TrimSpace(utils.Flags.ProjectPath)
+	if len(projectPath) == 0 {
+		projectPath = utils.DEFAULT_PROJECT_PATH
+	}
+	projectPath, _ = filepath.Abs(projectPath)
+
+	if len(utils.Flags.ManifestPath) == 0 {
+		utils.Flags.ManifestPath = utils.GetManifestFilePath(projectPath)
+	}
+	if len(utils.Flags.DeploymentPath) == 0 {
+		utils.Flags.DeploymentPath = utils.GetDeploymentFilePath(projectPath)
+	}
+	if len(utils.Flags.ManifestPath) == 0 || !utils.MayExists(utils.Flags.ManifestPath) {
+		errStr := wski18n.T("No manifest file found in '{{.path}}'", map[string]interface{}{"path": projectPath})
+		return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	config, err := deployers.NewWhiskConfig(utils.Flags.CfgFile, utils.Flags.DeploymentPath, utils.Flags.ManifestPath)
+	if err != nil {
+		return nil, projectConfigError(err)
+	}
+	if err := setProjectClient(config); err != nil {
+		return nil, err
+	}
+
+	// The manifest parser infers the kinds of actions from the runtimes the API host supports
+	if op, err := runtimes.ParseOpenWhisk(config.Host); err == nil {
+		runtimes.SupportedRunTimes = runtimes.ConvertToMap(op)
+		runtimes.DefaultRunTimes = runtimes.DefaultRuntimes(op)
+		runtimes.FileExtensionRuntimeKindMap = runtimes.FileExtensionRuntimes(op)
+		runtimes.FileRuntimeExtensionsMap = runtimes.FileRuntimeExtensions(op)
+	}
+
+	deployer := deployers.NewServiceDeployer()
+	deployer.ProjectPath = projectPath
+	deployer.ManifestPath = utils.Flags.ManifestPath
+	deployer.DeploymentPath = utils.Flags.DeploymentPath
+	deployer.DependencyMaster = make(map[string]dependencies.DependencyRecord)
+	deployer.Client = Client
+	deployer.ClientConfig = Client.Config
+
+	if err := deployer.ConstructDeploymentPlan(); err != nil {
+		whisk.Debug(whisk.DbgError, "ConstructDeploymentPlan() failed: %s\n", err)
+		errStr := wski18n.T("Unable to read the project: {{.err}}", map[string]interface{}{"err": err})
+		return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return deployer, nil
+}
+
+// setProjectClient points the client at the API host, credentials and namespace of a project
+func setProjectClient(config *whisk.Config) error {
+	baseURL, err := whisk.GetURLBase(config.Host, DefaultOpenWhiskApiPath)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "whisk.GetURLBase(%s, %s) error: %s\n", config.Host, DefaultOpenWhiskApiPath, err)
+		errStr := wski18n.T("The API host is not valid: {{.err}}", map[string]interface{}{"err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	config.BaseURL = baseURL
+	config.UserAgent = UserAgent + "/1.0 (" + Properties.CLIVersion + ") " + runtime.GOOS + " " + runtime.GOARCH
+	if Client, err = whisk.NewClient(http.DefaultClient, config); err != nil {
+		whisk.Debug(whisk.DbgError, "whisk.NewClient(%#v, %#v) error: %s\n", http.DefaultClient, config, err)
+		errStr := wski18n.T("Unable to initialize server connection: {{.err}}", map[string]interface{}{"err": err})
+		return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return nil
+}
+
+func projectConfigError(err error) error {
+	whisk.Debug(whisk.DbgError, "deployers.NewWhiskConfig() failed: %s\n", err)
+	errStr := wski18n.T("Unable to read the project credentials: {{.err}}", map[string]interface{}{"err": err})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+// planProject compares the entities of a deployment with the deployed ones. Changes are in the order
+// they are applied: packages, actions, sequences, triggers and rules are created or updated, then
+// entities of the project that the manifest no longer declares are deleted, rules first.
+func planProject(project string, deployment *deployers.DeploymentProject) (*projectPlan, error) {
+	plan := &projectPlan{Version: PROJECT_PLAN_VERSION, Project: project, APIHost: Client.Config.Host, Namespace: Client.Namespace}
+	var actions, sequences, triggers, rules []*planChange
+
+	var packageNames []string
+	for name := range deployment.Packages {
+		packageNames = append(packageNames, name)
+	}
+	sort.Strings(packageNames)
+
+	for _, packageName := range packageNames {
+		deployed := deployment.Packages[packageName]
+
+		var inherited whisk.KeyValueArr
+		if packageName != parsers.DEFAULT_PACKAGE {
+			change, err := planEntity(PLAN_PACKAGE, packageName, &planChange{Package: deployed.Package}, nil)
+			if err != nil {
+				return nil, err
+			}
+			plan.Changes = append(plan.Changes, change)
+			inherited = deployed.Package.Parameters
+		}
+
+		for _, records := range []map[string]utils.ActionRecord{deployed.Actions, deployed.Sequences} {
+			var names []string
+			for name := range records {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				action := records[name].Action
+				fullName := action.Name
+				if packageName != parsers.DEFAULT_PACKAGE {
+					fullName = packageName + "/" + action.Name
+				}
+				change, err := planEntity(PLAN_ACTION, fullName, &planChange{Action: action}, inherited)
+				if err != nil {
+					return nil, err
+				}
+				if action.Exec != nil && action.Exec.Kind == SEQUENCE {
+					sequences = append(sequences, change)
+				} else {
+					actions = append(actions, change)
+				}
+			}
+		}
+	}
+
+	for name, trigger := range deployment.Triggers {
+		change, err := planEntity(PLAN_TRIGGER, name, &planChange{Trigger: trigger}, nil)
+		if err != nil {
+			return nil, err
+		}
+		triggers = append(triggers, change)
+	}
+	for name, rule := range deployment.Rules {
+		change, err := planEntity(PLAN_RULE, name, &planChange{Rule: rule}, nil)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, change)
+	}
+	sort.Slice(triggers, func(i, j int) bool { return triggers[i].Name < triggers[j].Name })
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	plan.Changes = append(plan.Changes, actions...)
+	plan.Changes = append(plan.Changes, sequences...)
+	plan.Changes = append(plan.Changes, triggers...)
+	plan.Changes = append(plan.Changes, rules...)
+
+	if len(project) > 0 {
+		deletes, err := planProjectDeletes(project, plan.Changes)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, deletes...)
+	}
+	return plan, nil
+}
+
+// planEntity completes a change holding the entity to deploy, comparing it with the deployed one. An
+// action inherits the parameters of its package.
+func planEntity(kind string, name string, change *planChange, inherited whisk.KeyValueArr) (*planChange, error) {
+	change.Kind, change.Name = kind, name
+
+	remote, version, err := getRemoteEntity(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	change.Remote = version
+
+	switch remote := remote.(type) {
+	case *whisk.Package:
+		change.Diffs = diffPackage(remote, change.Package)
+	case *whisk.Action:
+		change.Diffs = diffAction(remote, change.Action, inherited)
+	case *whisk.Trigger:
+		change.Diffs = diffTrigger(remote, change.Trigger)
+	case *whisk.Rule:
+		change.Diffs = diffRule(remote, change.Rule)
+	}
+
+	if remote == nil {
+		change.Operation = PLAN_CREATE
+	} else if len(change.Diffs) > 0 {
+		change.Operation = PLAN_UPDATE
+	} else {
+		change.Operation = PLAN_NOOP
+	}
+	return change, nil
+}
+
+// getRemoteEntity gets a deployed entity with its version, or nothing when it does not exist
+func getRemoteEntity(kind string, name string) (interface{}, *planRemote, error) {
+	var entity interface{}
+	var version *planRemote
+	var response *http.Response
+	var err error
+
+	switch kind {
+	case PLAN_PACKAGE:
+		var xPackage *whisk.Package
+		if xPackage, response, err = Client.Packages.Get(name); err == nil {
+			entity, version = xPackage, &planRemote{xPackage.Version, xPackage.Updated}
+		}
+	case PLAN_ACTION:
+		var action *whisk.Action
+		if action, response, err = Client.Actions.Get(name, FETCH_CODE); err == nil {
+			entity, version = action, &planRemote{action.Version, action.Updated}
+		}
+	case PLAN_TRIGGER:
+		var trigger *whisk.Trigger
+		if trigger, response, err = Client.Triggers.Get(name); err == nil {
+			entity, version = trigger, &planRemote{trigger.Version, trigger.Updated}
+		}
+	case PLAN_RULE:
+		var rule *whisk.Rule
+		if rule, response, err = Client.Rules.Get(name); err == nil {
+			entity, version = rule, &planRemote{rule.Version, rule.Updated}
+		}
+	}
+
+	if err != nil && response != nil && response.StatusCode == http.StatusNotFound {
+		return nil, nil, nil
+	} else if err != nil {
+		whisk.Debug(whisk.DbgError, "Get of %s '%s' failed: %s\n", kind, name, err)
+		errStr := wski18n.T("Unable to get {{.kind}} '{{.name}}': {{.err}}", map[string]interface{}{"kind": kind, "name": name, "err": err})
+		return nil, nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return entity, version, nil
+}
+
+// planProjectDeletes returns deletions of the entities annotated as managed by the project that are
+// not among the planned changes
+func planProjectDeletes(project string, planned []*planChange) ([]*planChange, error) {
+	declared := make(map[string]bool)
+	for _, change := range planned {
+		declared[change.Kind+" "+change.Name] = true
+	}
+
+	var names [4][]string
+	kinds := [4]string{PLAN_RULE, PLAN_TRIGGER, PLAN_ACTION, PLAN_PACKAGE}
+	managed := func(i int, name string, annotations whisk.KeyValueArr) {
+		annotation, _ := annotations.GetValue(utils.MANAGED).(map[string]interface{})
+		if annotation != nil && annotation[utils.OW_PROJECT_NAME] == project && !declared[kinds[i]+" "+name] {
+			names[i] = append(names[i], name)
+		}
+	}
+
+	err := listAllPages(0, func(skip int, limit int) (int, error) {
+		options := &whisk.RuleListOptions{Skip: skip, Limit: limit}
+		page, _, err := Client.Rules.List(options)
+		if err != nil {
+			return 0, ruleListError(options, err)
+		}
+		for _, rule := range page {
+			managed(0, rule.Name, rule.Annotations)
+		}
+		return len(page), nil
+	})
+	if err == nil {
+		err = listAllPages(0, func(skip int, limit int) (int, error) {
+			page, err := listTriggers(&whisk.TriggerListOptions{Skip: skip, Limit: limit})
+			for _, trigger := range page {
+				managed(1, trigger.Name, trigger.Annotations)
+			}
+			return len(page), err
+		})
+	}
+	if err == nil {
+		err = listAllPages(0, func(skip int, limit int) (int, error) {
+			options := &whisk.ActionListOptions{Skip: skip, Limit: limit}
+			page, _, err := Client.Actions.List("", options)
+			if err != nil {
+				return 0, actionListError("", options, err)
+			}
+			for _, action := range page {
+				managed(2, entityPathName(action.Namespace, action.Name), action.Annotations)
+			}
+			return len(page), nil
+		})
+	}
+	if err == nil {
+		err = listAllPages(0, func(skip int, limit int) (int, error) {
+			page, err := listPackages(&whisk.PackageListOptions{Skip: skip, Limit: limit})
+			for _, xPackage := range page {
+				managed(3, xPackage.Name, xPackage.Annotations)
+			}
+			return len(page), err
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var deletes []*planChange
+	for i, kind := range kinds {
+		sort.Strings(names[i])
+		for _, name := range names[i] {
+			_, version, err := getRemoteEntity(kind, name)
+			if err != nil {
+				return nil, err
+			}
+			if version != nil {
+				deletes = append(deletes, &planChange{Operation: PLAN_DELETE, Kind: kind, Name: name, Remote: version})
+			}
+		}
+	}
+	return deletes, nil
+}
+
+func diffPackage(remote *whisk.Package, desired *whisk.Package) []planDiff {
+	var diffs []planDiff
+	diffs = appendPlanDiff(diffs, "publish", remote.Publish != nil && *remote.Publish, desired.Publish != nil && *desired.Publish)
+	diffs = append(diffs, diffKeyValues("parameters", remote.Parameters, desired.Parameters, nil)...)
+	return append(diffs, diffAnnotations(remote.Annotations, desired.Annotations)...)
+}
+
+// diffAction compares the code, limits, parameters and annotations of an action. Code is compared by
+// digest, and sequence components by their path within the namespace. The deployed action holds the
+// parameters of its package as well, which are not taken as removed.
+func diffAction(remote *whisk.Action, desired *whisk.Action, inherited whisk.KeyValueArr) []planDiff {
+	var diffs []planDiff
+	remoteExec, desiredExec := remote.Exec, desired.Exec
+	if remoteExec == nil {
+		remoteExec = &whisk.Exec{}
+	}
+	if desiredExec == nil {
+		desiredExec = &whisk.Exec{}
+	}
+
+	diffs = appendPlanDiff(diffs, "exec.kind", remoteExec.Kind, desiredExec.Kind)
+	diffs = appendPlanDiff(diffs, "exec.code", codeDigest(remoteExec.Code), codeDigest(desiredExec.Code))
+	diffs = appendPlanDiff(diffs, "exec.image", remoteExec.Image, desiredExec.Image)
+	diffs = appendPlanDiff(diffs, "exec.main", remoteExec.Main, desiredExec.Main)
+	diffs = appendPlanDiff(diffs, "exec.components", componentPaths(remoteExec.Components), componentPaths(desiredExec.Components))
+
+	if desired.Limits != nil {
+		remoteLimits := remote.Limits
+		if remoteLimits == nil {
+			remoteLimits = &whisk.Limits{}
+		}
+		limits := []struct {
+			field           string
+			remote, desired *int
+		}{
+			{"limits.timeout", remoteLimits.Timeout, desired.Limits.Timeout},
+			{"limits.memory", remoteLimits.Memory, desired.Limits.Memory},
+			{"limits.logsize", remoteLimits.Logsize, desired.Limits.Logsize},
+			{"limits.concurrency", remoteLimits.Concurrency, desired.Limits.Concurrency},
+		}
+		for _, limit := range limits {
+			if limit.desired != nil {
+				var old interface{}
+				if limit.remote != nil {
+					old = *limit.remote
+				}
+				diffs = appendPlanDiff(diffs, limit.field, old, *limit.desired)
+			}
+		}
+	}
+
+	diffs = append(diffs, diffKeyValues("parameters", remote.Parameters, desired.Parameters, inherited)...)
+	return append(diffs, diffAnnotations(remote.Annotations, desired.Annotations)...)
+}
+
+// diffTrigger compares the annotations of a trigger and, unless it has a feed that is given them
+// instead, its parameters
+func diffTrigger(remote *whisk.Trigger, desired *whisk.Trigger) []planDiff {
+	var diffs []planDiff
+	if desired.Annotations.FindKeyValue("feed") < 0 {
+		diffs = diffKeyValues("parameters", remote.Parameters, desired.Parameters, nil)
+	}
+	return append(diffs, diffAnnotations(remote.Annotations, desired.Annotations)...)
+}
+
+func diffRule(remote *whisk.Rule, desired *whisk.Rule) []planDiff {
+	var diffs []planDiff
+	diffs = appendPlanDiff(diffs, "trigger", planReferenceName(remote.Trigger), planReferenceName(desired.Trigger))
+	diffs = appendPlanDiff(diffs, "action", planReferenceName(remote.Action), planReferenceName(desired.Action))
+	diffs = appendPlanDiff(diffs, "status", remote.Status, "active")
+	return append(diffs, diffAnnotations(remote.Annotations, desired.Annotations)...)
+}
+
+func diffAnnotations(remote whisk.KeyValueArr, desired whisk.KeyValueArr) []planDiff {
+	ignored := func(annotations whisk.KeyValueArr) whisk.KeyValueArr {
+		var compared whisk.KeyValueArr
+		for _, annotation := range annotations {
+			if !contains(PLAN_IGNORED_ANNOTATIONS, annotation.Key) {
+				compared = append(compared, annotation)
+			}
+		}
+		return compared
+	}
+	return diffKeyValues("annotations", ignored(remote), ignored(desired), nil)
+}
+
+// diffKeyValues compares two lists of key values by key. A deployed key that is not desired is
+// removed, unless it is one of the inherited keys.
+func diffKeyValues(field string, remote whisk.KeyValueArr, desired whisk.KeyValueArr, inherited whisk.KeyValueArr) []planDiff {
+	var diffs []planDiff
+	for _, kv := range desired {
+		diffs = appendPlanDiff(diffs, field+"."+kv.Key, remote.GetValue(kv.Key), kv.Value)
+	}
+	for _, kv := range remote {
+		if desired.FindKeyValue(kv.Key) < 0 && inherited.FindKeyValue(kv.Key) < 0 {
+			diffs = append(diffs, planDiff{Field: field + "." + kv.Key, Old: kv.Value})
+		}
+	}
+	return diffs
+}
+
+// appendPlanDiff appends a diff when the values differ once encoded as JSON, so that numbers read
+// from a manifest compare equal to the ones returned by the controller
+func appendPlanDiff(diffs []planDiff, field string, old interface{}, new interface{}) []planDiff {
+	old, new = normalizePlanValue(old), normalizePlanValue(new)
+	if reflect.DeepEqual(old, new) {
+		return diffs
+	}
+	return append(diffs, planDiff{Field: field, Old: old, New: new})
+}
+
+func normalizePlanValue(value interface{}) interface{} {
+	var normalized interface{}
+	if encoded, err := json.Marshal(value); err == nil && json.Unmarshal(encoded, &normalized) == nil {
+		value = normalized
+	}
+	if text, ok := value.(string); ok && len(text) == 0 {
+		return nil
+	}
+	if list, ok := value.([]interface{}); ok && len(list) == 0 {
+		return nil
+	}
+	return value
+}
+
+func codeDigest(code *string) interface{} {
+	if code == nil || len(*code) == 0 {
+		return nil
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(*code)))[:19]
+}
+
+// componentPaths returns sequence components without their namespace, e.g. "/guest/utils/date" is
+// "utils/date"
+func componentPaths(components []string) []string {
+	var paths []string
+	for _, component := range components {
+		paths = append(paths, planReferenceName(component))
+	}
+	return paths
+}
+
+// planReferenceName returns the name within the namespace of a reference to an entity, either a name
+// as a manifest gives it or a path and a name as the controller returns it
+func planReferenceName(reference interface{}) string {
+	if name, ok := reference.(string); ok {
+		if parts := strings.SplitN(strings.TrimPrefix(name, "/"), "/", 2); strings.HasPrefix(name, "/") && len(parts) > 1 {
+			return parts[1]
+		}
+		return name
+	}
+
+	_, pkg, name := ruleEntityPath(reference)
+	if len(pkg) > 0 {
+		return pkg + "/" + name
+	}
+	return name
+}
+
+// checkPlanDrift returns the entities of a plan whose deployed version is no longer the one planned
+func checkPlanDrift(plan *projectPlan) ([]string, error) {
+	var drifted []string
+	for _, change := range plan.Changes {
+		_, version, err := getRemoteEntity(change.Kind, change.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(version, change.Remote) {
+			drifted = append(drifted, change.Kind+" "+change.Name)
+		}
+	}
+	return drifted, nil
+}
+
+func applyProjectPlan(plan *projectPlan) error {
+	for _, change := range plan.Changes {
+		if change.Operation == PLAN_NOOP {
+			continue
+		}
+
+		var err error
+		overwrite := change.Operation == PLAN_UPDATE
+		switch change.Kind + " " + change.Operation {
+		case PLAN_PACKAGE + " " + PLAN_DELETE:
+			err = deletePackage(plan.Namespace, change.Name)
+		case PLAN_ACTION + " " + PLAN_DELETE:
+			err = deleteAction(plan.Namespace, change.Name)
+		case PLAN_TRIGGER + " " + PLAN_DELETE:
+			err = applyTriggerDelete(change.Name)
+		case PLAN_RULE + " " + PLAN_DELETE:
+			ruleStatusCache.forget(change.Name)
+			if _, err = Client.Rules.Delete(change.Name); err != nil {
+				err = planApplyError(change, err)
+			}
+		case PLAN_PACKAGE + " " + PLAN_CREATE, PLAN_PACKAGE + " " + PLAN_UPDATE:
+			if _, _, err = Client.Packages.Insert(change.Package, overwrite); err != nil {
+				err = planApplyError(change, err)
+			}
+		case PLAN_ACTION + " " + PLAN_CREATE, PLAN_ACTION + " " + PLAN_UPDATE:
+			action := *change.Action
+			action.Name = change.Name
+			if _, _, err = Client.Actions.Insert(&action, overwrite); err != nil {
+				err = actionInsertError(&action, err)
+			}
+		case PLAN_TRIGGER + " " + PLAN_CREATE, PLAN_TRIGGER + " " + PLAN_UPDATE:
+			err = applyTrigger(change.Trigger, overwrite)
+		case PLAN_RULE + " " + PLAN_CREATE, PLAN_RULE + " " + PLAN_UPDATE:
+			rule := *change.Rule
+			rule.Trigger = fmt.Sprintf("/%s/%s", plan.Namespace, planReferenceName(rule.Trigger))
+			rule.Action = fmt.Sprintf("/%s/%s", plan.Namespace, planReferenceName(rule.Action))
+			ruleStatusCache.forget(rule.Name)
+			if _, _, err = Client.Rules.Insert(&rule, overwrite); err != nil {
+				err = planApplyError(change, err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} {{.operation}} {{.kind}} {{.name}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "operation": planOperationDone(change.Operation),
+				"kind": change.Kind, "name": boldString(change.Name)}))
+	}
+	return nil
+}
+
+func planOperationDone(operation string) string {
+	switch operation {
+	case PLAN_CREATE:
+		return wski18n.T("created")
+	case PLAN_UPDATE:
+		return wski18n.T("updated")
+	}
+	return wski18n.T("deleted")
+}
+
+// applyTrigger creates or updates a trigger. A trigger with a feed is created without parameters and
+// given them by the feed action; as feeds cannot be updated, an existing one is deleted first.
+func applyTrigger(trigger *whisk.Trigger, overwrite bool) error {
+	feed := getValueString(trigger.Annotations, "feed")
+	if len(feed) == 0 {
+		if _, _, err := Client.Triggers.Insert(trigger, overwrite); err != nil {
+			return planApplyError(&planChange{Kind: PLAN_TRIGGER, Name: trigger.Name}, err)
+		}
+		return nil
+	}
+
+	if overwrite {
+		if err := applyTriggerDelete(trigger.Name); err != nil {
+			return err
+		}
+	}
+
+	created := &whisk.Trigger{Name: trigger.Name, Annotations: trigger.Annotations}
+	if _, _, err := Client.Triggers.Insert(created, false); err != nil {
+		return planApplyError(&planChange{Kind: PLAN_TRIGGER, Name: trigger.Name}, err)
+	}
+	if _, err := invokeTriggerFeed(trigger.Name, feed, FEED_CREATE, trigger.Parameters); err != nil {
+		Client.Triggers.Delete(trigger.Name)
+		return err
+	}
+	return nil
+}
+
+// applyTriggerDelete deletes a trigger, removing it from its feed first
+func applyTriggerDelete(name string) error {
+	trigger, _, err := Client.Triggers.Get(name)
+	if err != nil {
+		return planApplyError(&planChange{Kind: PLAN_TRIGGER, Name: name}, err)
+	}
+	if feed := getValueString(trigger.Annotations, "feed"); len(feed) > 0 {
+		if _, err := invokeTriggerFeed(name, feed, FEED_DELETE, nil); err != nil {
+			return err
+		}
+	}
+	if _, _, err := Client.Triggers.Delete(name); err != nil {
+		return planApplyError(&planChange{Kind: PLAN_TRIGGER, Name: name}, err)
+	}
+	return nil
+}
+
+// invokeTriggerFeed invokes the feed action of a trigger for a lifecycle event, with the parameters trigger
+// create passes to it, and returns its result
+func invokeTriggerFeed(triggerName string, feed string, lifecycle string, parameters whisk.KeyValueArr) (map[string]interface{}, error) {
+	if _, err := NewQualifiedName(feed); err != nil {
+		return nil, NewQualifiedNameError(feed, err)
+	}
+	qualifiedName, err := NewQualifiedName(fmt.Sprintf("/%s/%s", Client.Namespace, triggerName))
+	if err != nil {
+		return nil, NewQualifiedNameError(triggerName, err)
+	}
+
+	feedName, feedParams := feedParameters(feed, lifecycle, qualifiedName, Client.Config.AuthToken)
+	payload := make(map[string]interface{})
+	for _, kv := range parameters {
+		payload[kv.Key] = kv.Value
+	}
+	for key, value := range getParameters(feedParams, false, false).(map[string]interface{}) {
+		payload[key] = value
+	}
+
+	namespace := Client.Namespace
+	result, err := invokeAction(*feedName, payload, true, true)
+	Client.Namespace = namespace
+
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Invoke of feed '%s' for trigger '%s' failed: %s\n", feed, triggerName, err)
+		errStr := wski18n.T(FEED_CONFIGURATION_FAILURE, map[string]interface{}{"feedname": feed, "err": err})
+		return nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	response, _ := result.(map[string]interface{})
+	return response, nil
+}
+
+func planApplyError(change *planChange, err error) error {
+	whisk.Debug(whisk.DbgError, "Apply of %s %s '%s' failed: %s\n", change.Operation, change.Kind, change.Name, err)
+	errStr := wski18n.T("Unable to apply the plan to {{.kind}} '{{.name}}': {{.err}}",
+		map[string]interface{}{"kind": change.Kind, "name": change.Name, "err": err})
+	return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func printProjectPlan(plan *projectPlan) {
+	counts := make(map[string]int)
+	for _, change := range plan.Changes {
+		counts[change.Operation]++
+
+		var symbol string
+		switch change.Operation {
+		case PLAN_CREATE:
+			symbol = color.GreenString("+")
+		case PLAN_UPDATE:
+			symbol = color.YellowString("~")
+		case PLAN_DELETE:
+			symbol = color.RedString("-")
+		default:
+			continue
+		}
+
+		fmt.Fprintf(color.Output, "  %s %s %s\n", symbol, change.Kind, boldString(change.Name))
+		for _, diff := range change.Diffs {
+			fmt.Fprintf(color.Output, "      %s: %s => %s\n", diff.Field, planValueString(diff.Old), planValueString(diff.New))
+		}
+	}
+
+	fmt.Fprintf(color.Output, wski18n.T("Plan: {{.create}} to create, {{.update}} to update, {{.delete}} to delete, {{.noop}} unchanged.\n",
+		map[string]interface{}{"create": counts[PLAN_CREATE], "update": counts[PLAN_UPDATE], "delete": counts[PLAN_DELETE], "noop": counts[PLAN_NOOP]}))
+}
+
+func planValueString(value interface{}) string {
+	if value == nil {
+		return wski18n.T("(none)")
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// writeProjectPlan saves a plan readable by its owner only, as it holds the code and parameters of
+// the entities
+func writeProjectPlan(filename string, plan *projectPlan) error {
+	encoded, err := json.MarshalIndent(plan, "", "    ")
+	if err == nil {
+		err = ioutil.WriteFile(filename, encoded, 0600)
+	}
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Write of plan '%s' failed: %s\n", filename, err)
+		errStr := wski18n.T("Unable to save the plan to '{{.name}}': {{.err}}", map[string]interface{}{"name": filename, "err": err})
+		return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return nil
+}
+
+func readProjectPlan(filename string) (*projectPlan, error) {
+	var plan projectPlan
+	contents, err := ioutil.ReadFile(filename)
+	if err == nil {
+		err = json.Unmarshal(contents, &plan)
+	}
+	if err == nil && plan.Version != PROJECT_PLAN_VERSION {
+		err = errors.New(wski18n.T("unsupported plan version {{.version}}", map[string]interface{}{"version": plan.Version}))
+	}
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Read of plan '%s' failed: %s\n", filename, err)
+		errStr := wski18n.T("Unable to read the plan '{{.name}}': {{.err}}", map[string]interface{}{"name": filename, "err": err})
+		return nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+	return &plan, nil
+}
+
+func init() {
+	projectPlanCmd.Flags().StringVar(&Flags.project.out, "out", PROJECT_PLAN_FILE, wski18n.T("save the plan to `FILE`"))
+}
@@ -0,0 +1,106 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/apache/openwhisk-wskdeploy/deployers"
+	"github.com/apache/openwhisk-wskdeploy/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func projectPlanTestDeployment() *deployers.DeploymentProject {
+	code := "function main() { return {} }"
+	tools := deployers.NewDeploymentPackage()
+	tools.Package = &whisk.Package{Name: "tools", Parameters: whisk.KeyValueArr{{Key: "region", Value: "eu"}}}
+	tools.Actions["hello"] = utils.ActionRecord{Action: &whisk.Action{Name: "hello",
+		Exec: &whisk.Exec{Kind: "nodejs:default", Code: &code}}}
+	tools.Actions["fresh"] = utils.ActionRecord{Action: &whisk.Action{Name: "fresh",
+		Exec: &whisk.Exec{Kind: "nodejs:default", Code: &code}}}
+	tools.Sequences["steps"] = utils.ActionRecord{Action: &whisk.Action{Name: "steps",
+		Exec: &whisk.Exec{Kind: SEQUENCE, Components: []string{"/_/tools/hello"}}}}
+
+	deployment := deployers.NewDeploymentProject()
+	deployment.Packages["tools"] = tools
+	deployment.Rules["greet"] = &whisk.Rule{Name: "greet", Trigger: "ticks", Action: "tools/hello"}
+	return deployment
+}
+
+func TestProjectPlan(t *testing.T) {
+	entities := entityStoreTestEntities()
+	entities["packages/tools"] = `{"publish": false, "parameters": [{"key": "region", "value": "eu"}],
+		"annotations": [{"key": "whisk-managed", "value": {"projectName": "demo"}}]}`
+	entities["actions/tools/hello"] = `{"exec": {"kind": "nodejs:default", "code": "function main() {}"},
+		"parameters": [{"key": "region", "value": "us"}, {"key": "debug", "value": true}]}`
+	entities["actions/tools/old"] = `{"exec": {"kind": "nodejs:default", "code": "function main() {}"},
+		"annotations": [{"key": "whisk-managed", "value": {"projectName": "demo"}}]}`
+	store := newEntityStore(t, entities)
+
+	plan, err := planProject("demo", projectPlanTestDeployment())
+	assert.Nil(t, err)
+
+	var operations []string
+	for _, change := range plan.Changes {
+		operations = append(operations, change.Operation+" "+change.Kind+" "+change.Name)
+	}
+	assert.Equal(t, []string{
+		"no-op package tools",
+		"create action tools/fresh",
+		"update action tools/hello",
+		"no-op action tools/steps",
+		"update rule greet",
+		"delete action tools/old",
+	}, operations)
+	assert.Equal(t, []planDiff{
+		{Field: "exec.code", Old: codeDigest(stringPointer("function main() {}")), New: codeDigest(stringPointer("function main() { return {} }"))},
+		{Field: "parameters.debug", Old: true},
+	}, plan.Changes[2].Diffs)
+	assert.Equal(t, []planDiff{{Field: "status", Old: "inactive", New: "active"}}, plan.Changes[4].Diffs)
+
+	filename := filepath.Join(t.TempDir(), PROJECT_PLAN_FILE)
+	assert.Nil(t, writeProjectPlan(filename, plan))
+	plan, err = readProjectPlan(filename)
+	assert.Nil(t, err)
+
+	// A plan is refused once an entity it covers changed
+	store.put("actions/other/fresh", map[string]interface{}{})
+	store.put("actions/tools/fresh", map[string]interface{}{})
+	drifted, err := checkPlanDrift(plan)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"action tools/fresh"}, drifted)
+	delete(store.entities, "actions/tools/fresh")
+
+	drifted, err = checkPlanDrift(plan)
+	assert.Nil(t, err)
+	assert.Empty(t, drifted)
+	assert.Nil(t, applyProjectPlan(plan))
+
+	assert.Equal(t, []string{"other", "other/fresh", "pipeline", "tools/fresh", "tools/hello", "tools/steps"}, store.names("actions"))
+	assert.Equal(t, "function main() { return {} }", store.entities["actions/tools/hello"]["exec"].(map[string]interface{})["code"])
+	assert.Equal(t, "active", store.entities["rules/greet"]["status"])
+	assert.Equal(t, map[string]interface{}{"path": "guest", "name": "ticks"}, store.entities["rules/greet"]["trigger"])
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
@@ -64,6 +64,13 @@ const (
 	propDisplayCLIVersion = "whisk CLI version"
 	propDisplayAPIBuild   = "whisk API build"
 	propDisplayAPIBuildNo = "whisk API build number"
+
+	propDisplayMemoryLimit      = "whisk memory (MB)"
+	propDisplayTimeoutLimit     = "whisk timeout (ms)"
+	propDisplayLogSizeLimit     = "whisk log size (MB)"
+	propDisplayConcurrencyLimit = "whisk concurrency"
+	propDisplaySequenceLimit    = "whisk sequence length"
+	propDisplayCodeSizeLimit    = "whisk code size (MB)"
 )
 
 var propertyCmd = &cobra.Command{
@@ -329,6 +336,11 @@ var propertyGetCmd = &cobra.Command{
 			if Flags.property.all {
 				fmt.Fprintf(color.Output, "%s\t\t%s\n", wski18n.T(propDisplayAPIBuild), boldString(info.Build))
 				fmt.Fprintf(color.Output, "%s\t%s\n", wski18n.T(propDisplayAPIBuildNo), boldString(info.BuildNo))
+				if hostInfo, err := getHostInfo(); err != nil {
+					whisk.Debug(whisk.DbgError, "getHostInfo() failed: %s\n", err)
+				} else if hostInfo.Limits != nil {
+					printHostLimits(hostInfo.Limits)
+				}
 			}
 			if Flags.property.apibuild && !Flags.property.all {
 				printProperty(info.Build, propDisplayAPIBuild, outputFormat)
@@ -20,6 +20,9 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/apache/openwhisk-cli/wski18n"
 	"github.com/apache/openwhisk-client-go/whisk"
@@ -28,6 +31,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Rule statuses are fetched this many at a time, as the list API does not return them
+const RULE_STATUS_CONCURRENCY = 8
+
+// Fetched rules are reused by later lookups in the same process for this long
+const RULE_CACHE_TTL = 30 * time.Second
+
+// RULE_STATUS_UNKNOWN is listed for a rule whose status could not be fetched
+const RULE_STATUS_UNKNOWN = "unknown"
+
 // ruleCmd represents the rule command
 var ruleCmd = &cobra.Command{
 	Use:   "rule",
@@ -64,6 +76,7 @@ var ruleEnableCmd = &cobra.Command{
 			return werr
 		}
 
+		ruleStatusCache.forget(ruleName)
 		fmt.Fprintf(color.Output,
 			wski18n.T("{{.ok}} enabled rule {{.name}}\n",
 				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(ruleName)}))
@@ -101,6 +114,7 @@ var ruleDisableCmd = &cobra.Command{
 			return werr
 		}
 
+		ruleStatusCache.forget(ruleName)
 		fmt.Fprintf(color.Output,
 			wski18n.T("{{.ok}} disabled rule {{.name}}\n",
 				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(ruleName)}))
@@ -187,6 +201,7 @@ var ruleCreateCmd = &cobra.Command{
 		}
 		whisk.Debug(whisk.DbgInfo, "Inserted rule:\n%+v\n", retRule)
 
+		ruleStatusCache.forget(ruleName)
 		fmt.Fprintf(color.Output,
 			wski18n.T("{{.ok}} created rule {{.name}}\n",
 				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(ruleName)}))
@@ -233,6 +248,7 @@ var ruleUpdateCmd = &cobra.Command{
 			return werr
 		}
 
+		ruleStatusCache.forget(ruleName)
 		fmt.Fprintf(color.Output,
 			wski18n.T("{{.ok}} updated rule {{.name}}\n",
 				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(ruleName)}))
@@ -282,14 +298,18 @@ var ruleGetCmd = &cobra.Command{
 			return werr
 		}
 
-		if Flags.rule.summary {
+		if len(Flags.common.template) > 0 {
+			return printTemplate(rule, Flags.common.template)
+		} else if Flags.rule.summary {
 			printRuleSummary(rule)
 		} else {
 			if len(field) > 0 {
-				fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got rule {{.name}}, displaying field {{.field}}\n",
-					map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(ruleName),
-						"field": field}))
-				printField(rule, field)
+				if !printField(rule, field) {
+					errMsg := wski18n.T("Invalid field filter '{{.arg}}'.", map[string]interface{}{"arg": field})
+					whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+						whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+					return whiskErr
+				}
 			} else {
 				fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got rule {{.name}}\n",
 					map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(ruleName)}))
@@ -342,6 +362,7 @@ var ruleDeleteCmd = &cobra.Command{
 			return werr
 		}
 
+		ruleStatusCache.forget(ruleName)
 		fmt.Fprintf(color.Output,
 			wski18n.T("{{.ok}} deleted rule {{.name}}\n",
 				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(ruleName)}))
@@ -376,47 +397,178 @@ var ruleListCmd = &cobra.Command{
 			Client.Namespace = qualifiedName.GetNamespace()
 		}
 
-		ruleListOptions := &whisk.RuleListOptions{
-			Skip:  Flags.common.skip,
-			Limit: Flags.common.limit,
+		var rules []whisk.Rule
+		query, err := newListQuery(Flags.common.filter, Flags.common.sortBy)
+		if err != nil {
+			return err
 		}
 
-		rules, _, err := Client.Rules.List(ruleListOptions)
-		if err != nil {
-			whisk.Debug(whisk.DbgError, "Client.Rules.List(%#v) error: %s\n", ruleListOptions, err)
-			errStr := wski18n.T("Unable to obtain the list of rules for namespace '{{.name}}': {{.err}}",
-				map[string]interface{}{"name": getClientNamespace(), "err": err})
-			werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-			return werr
-		} else {
-			//No errors, lets attempt to retrieve the status of each rule #312
-			for index, rule := range rules {
-				ruleStatus, _, err := Client.Rules.Get(rule.Name)
+		// An explicit --sort-by order takes precedence over --name-sort
+		sortByName := Flags.common.nameSort && !query.sorted()
+
+		if Flags.common.all {
+			// Stream each page as it arrives unless the complete set is needed for sorting
+			stream := !sortByName && !query.sorted()
+			err = listAllPages(Flags.common.skip, func(skip int, limit int) (int, error) {
+				page, err := listRules(&whisk.RuleListOptions{Skip: skip, Limit: limit})
 				if err != nil {
-					errStr := wski18n.T("Unable to get status of rule '{{.name}}': {{.err}}",
-						map[string]interface{}{"name": rule.Name, "err": err})
-					fmt.Println(errStr)
-					werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-					return werr
+					return 0, err
+				}
+
+				if stream {
+					printListPage(query.apply(page), skip == Flags.common.skip)
+				} else {
+					rules = append(rules, page...)
 				}
-				rules[index].Status = ruleStatus.Status
+				return len(page), nil
+			})
+			if err != nil || stream {
+				return err
+			}
+		} else {
+			rules, err = listRules(&whisk.RuleListOptions{Skip: Flags.common.skip, Limit: Flags.common.limit})
+			if err != nil {
+				return err
 			}
 		}
 
-		sortByName := Flags.common.nameSort
-		printList(rules, sortByName)
+		printList(query.apply(rules), sortByName)
 		return nil
 	},
 }
 
+// listRules(options) returns the rules of a list request with the status of each rule filled in
+func listRules(options *whisk.RuleListOptions) ([]whisk.Rule, error) {
+	rules, _, err := Client.Rules.List(options)
+	if err != nil {
+		return nil, ruleListError(options, err)
+	}
+
+	getRuleStatuses(rules)
+	return rules, nil
+}
+
+func ruleListError(options *whisk.RuleListOptions, err error) error {
+	whisk.Debug(whisk.DbgError, "Client.Rules.List(%#v) error: %s\n", options, err)
+	errStr := wski18n.T("Unable to obtain the list of rules for namespace '{{.name}}': {{.err}}",
+		map[string]interface{}{"name": getClientNamespace(), "err": err})
+	return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+// ruleCache keeps rules fetched by getRule() for RULE_CACHE_TTL, so that commands which look up the
+// same rules more than once in a process do not fetch them again
+type ruleCache struct {
+	mutex   sync.Mutex
+	entries map[string]ruleCacheEntry
+}
+
+type ruleCacheEntry struct {
+	rule    *whisk.Rule
+	fetched time.Time
+}
+
+var ruleStatusCache = &ruleCache{entries: make(map[string]ruleCacheEntry)}
+
+func (cache *ruleCache) get(namespace string, name string) (*whisk.Rule, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, ok := cache.entries[namespace+"/"+name]
+	if !ok || time.Since(entry.fetched) > RULE_CACHE_TTL {
+		return nil, false
+	}
+	return entry.rule, true
+}
+
+func (cache *ruleCache) put(namespace string, name string, rule *whisk.Rule) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries[namespace+"/"+name] = ruleCacheEntry{rule: rule, fetched: time.Now()}
+}
+
+// forget drops a rule that has changed from every namespace it was cached under, as "_" and the
+// namespace it stands for are different keys
+func (cache *ruleCache) forget(name string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for key := range cache.entries {
+		if strings.HasSuffix(key, "/"+name) {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+// getRule(namespace, name) gets a rule through the rule cache. It uses the client namespace for the
+// request, which callers must set to the namespace of the rule and not change while it runs.
+func getRule(namespace string, name string) (*whisk.Rule, error) {
+	if rule, ok := ruleStatusCache.get(namespace, name); ok {
+		return rule, nil
+	}
+
+	rule, _, err := Client.Rules.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleStatusCache.put(namespace, name, rule)
+	return rule, nil
+}
+
+// getRuleStatuses(rules) retrieves the status of each rule, as the list API does not return it #312.
+// The rules are fetched RULE_STATUS_CONCURRENCY at a time; a rule that cannot be fetched is listed
+// with an unknown status rather than failing the whole list.
+func getRuleStatuses(list []whisk.Rule) {
+	var wait sync.WaitGroup
+	var mutex sync.Mutex
+	var failed []string
+	slots := make(chan struct{}, RULE_STATUS_CONCURRENCY)
+
+	for index := range list {
+		wait.Add(1)
+		slots <- struct{}{}
+
+		go func(rule *whisk.Rule) {
+			defer func() {
+				<-slots
+				wait.Done()
+			}()
+
+			ruleStatus, err := getRule(rule.Namespace, rule.Name)
+			if err != nil {
+				whisk.Debug(whisk.DbgError, "Client.Rules.Get(%s) failed: %s\n", rule.Name, err)
+				rule.Status = RULE_STATUS_UNKNOWN
+
+				mutex.Lock()
+				failed = append(failed, rule.Name)
+				mutex.Unlock()
+				return
+			}
+			rule.Status = ruleStatus.Status
+		}(&list[index])
+	}
+	wait.Wait()
+
+	if len(failed) > 0 {
+		printWarning(wski18n.T("Unable to get the status of {{.count}} rules, which are listed as {{.status}}: {{.names}}",
+			map[string]interface{}{"count": len(failed), "status": RULE_STATUS_UNKNOWN, "names": strings.Join(failed, ", ")}))
+	}
+}
+
 func init() {
 	ruleDeleteCmd.Flags().BoolVar(&Flags.rule.disable, "disable", false, wski18n.T("automatically disable rule before deleting it"))
 
 	ruleGetCmd.Flags().BoolVarP(&Flags.rule.summary, "summary", "s", false, wski18n.T("summarize rule details"))
+	ruleGetCmd.Flags().StringVar(&Flags.common.template, "template", "", wski18n.T("format the rule with the Go `TEMPLATE`, e.g. {{.example}}",
+		map[string]interface{}{"example": "'{{.name}} {{.status}}'"}))
 
 	ruleListCmd.Flags().IntVarP(&Flags.common.skip, "skip", "s", 0, wski18n.T("exclude the first `SKIP` number of rules from the result"))
 	ruleListCmd.Flags().IntVarP(&Flags.common.limit, "limit", "l", 30, wski18n.T("only return `LIMIT` number of rules from the collection"))
 	ruleListCmd.Flags().BoolVarP(&Flags.common.nameSort, "name-sort", "n", false, wski18n.T("sorts a list alphabetically by entity name; only applicable within the limit/skip returned entity block"))
+	ruleListCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("page through all rules in the collection instead of a single LIMIT block; --name-sort then applies to the complete list"))
+	ruleListCmd.Flags().StringArrayVar(&Flags.common.filter, "filter", []string{}, wski18n.T("only list rules matching the FIELD OPERATOR VALUE `EXPRESSION`, e.g. 'status=inactive'; may be repeated"))
+	ruleListCmd.Flags().StringVar(&Flags.common.sortBy, "sort-by", "", wski18n.T("sort the list by `FIELD`, ascending or, with FIELD:desc, descending"))
 
 	ruleCmd.AddCommand(
 		ruleCreateCmd,
@@ -427,6 +579,7 @@ func init() {
 		ruleGetCmd,
 		ruleDeleteCmd,
 		ruleListCmd,
+		ruleCheckCmd,
 	)
 
 }
@@ -0,0 +1,273 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Severities of the findings of `wsk rule check`; only errors fail the check
+const RULE_CHECK_OK = "ok"
+const RULE_CHECK_WARNING = "warning"
+const RULE_CHECK_ERROR = "error"
+
+type ruleDiagnostic struct {
+	severity string
+	message  string
+}
+
+var ruleCheckCmd = &cobra.Command{
+	Use:           "check (RULE_NAME | --all)",
+	Short:         wski18n.T("check that rules reference a reachable trigger and action and can fire"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var rules []string
+
+		if Flags.common.all {
+			if whiskErr := CheckArgs(args, 0, 0, "Rule check",
+				wski18n.T("A rule name cannot be combined with --all.")); whiskErr != nil {
+				return whiskErr
+			}
+
+			err := listAllPages(0, func(skip int, limit int) (int, error) {
+				// listRules fetches every rule through the rule cache, so the checks below do not fetch them again
+				page, err := listRules(&whisk.RuleListOptions{Skip: skip, Limit: limit})
+				if err != nil {
+					return 0, err
+				}
+				for _, rule := range page {
+					rules = append(rules, fmt.Sprintf("/%s/%s", rule.Namespace, rule.Name))
+				}
+				return len(page), nil
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			if whiskErr := CheckArgs(args, 1, 1, "Rule check", wski18n.T("A rule name is required.")); whiskErr != nil {
+				return whiskErr
+			}
+			rules = args
+		}
+
+		failed := 0
+		for _, name := range rules {
+			qualifiedName, err := NewQualifiedName(name)
+			if err != nil {
+				return NewQualifiedNameError(name, err)
+			}
+
+			Client.Namespace = qualifiedName.GetNamespace()
+			rule, err := getRule(qualifiedName.GetNamespace(), qualifiedName.GetEntityName())
+			if err != nil {
+				whisk.Debug(whisk.DbgError, "getRule(%s) failed: %s\n", qualifiedName.GetEntityName(), err)
+				errStr := wski18n.T("Unable to get rule '{{.name}}': {{.err}}",
+					map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": err})
+				return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			}
+
+			diagnostics := checkRule(rule)
+			printRuleDiagnostics(rule, diagnostics)
+			for _, diagnostic := range diagnostics {
+				if diagnostic.severity == RULE_CHECK_ERROR {
+					failed++
+					break
+				}
+			}
+		}
+
+		if failed > 0 {
+			errStr := wski18n.T("{{.failed}} of {{.total}} rules failed the check",
+				map[string]interface{}{"failed": failed, "total": len(rules)})
+			return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} {{.total}} rules passed the check\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "total": len(rules)}))
+		return nil
+	},
+}
+
+// ruleEntityPath splits the {"name": ..., "path": "namespace[/package]"} reference that a rule holds
+// for its trigger and action
+func ruleEntityPath(entity interface{}) (string, string, string) {
+	reference, _ := entity.(map[string]interface{})
+	name, _ := reference["name"].(string)
+	path, _ := reference["path"].(string)
+
+	parts := strings.SplitN(strings.Trim(path, "/"), "/", 2)
+	if len(parts) > 1 {
+		return parts[0], parts[1], name
+	}
+	return parts[0], "", name
+}
+
+func ruleEntityFullName(namespace string, pkg string, name string) string {
+	if len(pkg) > 0 {
+		return fmt.Sprintf("/%s/%s/%s", namespace, pkg, name)
+	}
+	return fmt.Sprintf("/%s/%s", namespace, name)
+}
+
+// checkRule runs every check on a rule and returns the findings, in the order of the checks
+func checkRule(rule *whisk.Rule) []ruleDiagnostic {
+	var diagnostics []ruleDiagnostic
+
+	if rule.Status != "active" {
+		diagnostics = append(diagnostics, ruleDiagnostic{RULE_CHECK_WARNING,
+			wski18n.T("the rule is {{.status}} and does not invoke its action; enable it with 'wsk rule enable {{.name}}'",
+				map[string]interface{}{"status": rule.Status, "name": rule.Name})})
+	}
+
+	diagnostics = append(diagnostics, checkRuleTrigger(rule)...)
+	return append(diagnostics, checkRuleAction(rule)...)
+}
+
+func checkRuleTrigger(rule *whisk.Rule) []ruleDiagnostic {
+	namespace, _, name := ruleEntityPath(rule.Trigger)
+	fullName := ruleEntityFullName(namespace, "", name)
+
+	if namespace != rule.Namespace {
+		return []ruleDiagnostic{{RULE_CHECK_ERROR,
+			wski18n.T("trigger {{.trigger}} is not in the namespace of the rule, {{.namespace}}; rules can only use triggers of their own namespace",
+				map[string]interface{}{"trigger": fullName, "namespace": rule.Namespace})}}
+	}
+
+	Client.Namespace = namespace
+	trigger, _, err := Client.Triggers.Get(name)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Triggers.Get(%s) failed: %s\n", name, err)
+		return []ruleDiagnostic{{RULE_CHECK_ERROR,
+			wski18n.T("trigger {{.trigger}} cannot be read: {{.err}}; create it with 'wsk trigger create' or point the rule at another trigger with 'wsk rule update'",
+				map[string]interface{}{"trigger": fullName, "err": err})}}
+	}
+
+	if feed := getValueString(trigger.Annotations, "feed"); len(feed) > 0 {
+		return []ruleDiagnostic{{RULE_CHECK_OK,
+			wski18n.T("trigger {{.trigger}} is fed by {{.feed}}; 'wsk trigger feed status {{.name}}' shows the registration",
+				map[string]interface{}{"trigger": fullName, "feed": feed, "name": name})}}
+	}
+
+	// Without a feed, the trigger only fires when something outside OpenWhisk fires it
+	activations, _, err := Client.Activations.List(&whisk.ActivationListOptions{Name: name, Limit: 1})
+	if err != nil {
+		whisk.Debug(whisk.DbgWarn, "Client.Activations.List(%s) failed: %s\n", name, err)
+	}
+	if len(activations) == 0 {
+		return []ruleDiagnostic{{RULE_CHECK_WARNING,
+			wski18n.T("trigger {{.trigger}} has no feed and no recorded activations, so nothing appears to fire it; attach a feed with 'wsk trigger create --feed' or fire it with 'wsk trigger fire'",
+				map[string]interface{}{"trigger": fullName})}}
+	}
+
+	return []ruleDiagnostic{{RULE_CHECK_OK,
+		wski18n.T("trigger {{.trigger}} has no feed and was last fired at {{.time}}",
+			map[string]interface{}{"trigger": fullName, "time": time.Unix(activations[0].Start/1000, 0).Format(time.RFC3339)})}}
+}
+
+func checkRuleAction(rule *whisk.Rule) []ruleDiagnostic {
+	var diagnostics []ruleDiagnostic
+
+	namespace, pkg, name := ruleEntityPath(rule.Action)
+	fullName := ruleEntityFullName(namespace, pkg, name)
+	shared := false
+
+	if len(pkg) > 0 {
+		Client.Namespace = namespace
+		retPackage, _, err := Client.Packages.Get(pkg)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "Client.Packages.Get(%s) failed: %s\n", pkg, err)
+			return []ruleDiagnostic{{RULE_CHECK_ERROR,
+				wski18n.T("package {{.package}} of action {{.action}} cannot be read: {{.err}}",
+					map[string]interface{}{"package": ruleEntityFullName(namespace, "", pkg), "action": fullName, "err": err})}}
+		}
+		shared = retPackage.Publish != nil && *retPackage.Publish
+
+		if retPackage.Binding != nil && len(retPackage.Binding.Name) > 0 {
+			binding := ruleEntityFullName(retPackage.Binding.Namespace, "", retPackage.Binding.Name)
+			Client.Namespace = retPackage.Binding.Namespace
+			if _, _, err = Client.Packages.Get(retPackage.Binding.Name); err != nil {
+				whisk.Debug(whisk.DbgError, "Client.Packages.Get(%s) failed: %s\n", retPackage.Binding.Name, err)
+				return []ruleDiagnostic{{RULE_CHECK_ERROR,
+					wski18n.T("package binding {{.package}} refers to {{.binding}}, which does not exist or is not shared: {{.err}}; bind to another package with 'wsk package bind'",
+						map[string]interface{}{"package": ruleEntityFullName(namespace, "", pkg), "binding": binding, "err": err})}}
+			}
+			diagnostics = append(diagnostics, ruleDiagnostic{RULE_CHECK_OK,
+				wski18n.T("package binding {{.package}} resolves to {{.binding}}",
+					map[string]interface{}{"package": ruleEntityFullName(namespace, "", pkg), "binding": binding})})
+		}
+	}
+
+	if namespace != rule.Namespace && !shared {
+		return append(diagnostics, ruleDiagnostic{RULE_CHECK_ERROR,
+			wski18n.T("action {{.action}} is not reachable from the namespace of the rule, {{.namespace}}; use an action of that namespace, or of a shared package through a binding",
+				map[string]interface{}{"action": fullName, "namespace": rule.Namespace})})
+	}
+
+	actionName := name
+	if len(pkg) > 0 {
+		actionName = pkg + "/" + name
+	}
+	Client.Namespace = namespace
+	action, _, err := Client.Actions.Get(actionName, false)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Actions.Get(%s) failed: %s\n", actionName, err)
+		return append(diagnostics, ruleDiagnostic{RULE_CHECK_ERROR,
+			wski18n.T("action {{.action}} cannot be read: {{.err}}; create it with 'wsk action create' or point the rule at another action with 'wsk rule update'",
+				map[string]interface{}{"action": fullName, "err": err})})
+	}
+
+	// A raw HTTP web action reads its input from __ow_body and __ow_query, which a trigger event does not provide
+	if getValueBool(action.Annotations, WEB_EXPORT_ANNOT) && getValueBool(action.Annotations, RAW_HTTP_ANNOT) {
+		return append(diagnostics, ruleDiagnostic{RULE_CHECK_WARNING,
+			wski18n.T("action {{.action}} is a raw HTTP web action; trigger events reach it as plain parameters, not as __ow_body and __ow_query, so turn off raw HTTP with 'wsk action update {{.name}} --web true'",
+				map[string]interface{}{"action": fullName, "name": actionName})})
+	}
+
+	return append(diagnostics, ruleDiagnostic{RULE_CHECK_OK,
+		wski18n.T("action {{.action}} exists", map[string]interface{}{"action": fullName})})
+}
+
+func printRuleDiagnostics(rule *whisk.Rule, diagnostics []ruleDiagnostic) {
+	fmt.Fprintf(color.Output, "%s %s\n", boldString(fmt.Sprintf("%4s", "rule")), getFullName(rule.Namespace, "", rule.Name))
+
+	for _, diagnostic := range diagnostics {
+		severity := color.GreenString(diagnostic.severity + ":")
+		switch diagnostic.severity {
+		case RULE_CHECK_WARNING:
+			severity = color.YellowString(diagnostic.severity + ":")
+		case RULE_CHECK_ERROR:
+			severity = color.RedString(diagnostic.severity + ":")
+		}
+		fmt.Fprintf(color.Output, "   %s %s\n", severity, diagnostic.message)
+	}
+}
+
+func init() {
+	ruleCheckCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("check all rules in the namespace"))
+}
@@ -0,0 +1,108 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func ruleCheckTestController(w http.ResponseWriter, r *http.Request) {
+	entities := map[string]string{
+		"/guest/triggers/alarm":           `{"name": "alarm", "namespace": "guest", "annotations": [{"key": "feed", "value": "/whisk.system/alarms/alarm"}]}`,
+		"/guest/triggers/manual":          `{"name": "manual", "namespace": "guest"}`,
+		"/guest/triggers/webhook":         `{"name": "webhook", "namespace": "guest"}`,
+		"/guest/actions/hello":            `{"name": "hello", "namespace": "guest", "exec": {"kind": "nodejs:default"}}`,
+		"/guest/actions/raw":              `{"name": "raw", "namespace": "guest", "annotations": [{"key": "web-export", "value": true}, {"key": "raw-http", "value": true}]}`,
+		"/guest/packages/stale":           `{"name": "stale", "namespace": "guest", "binding": {"namespace": "other", "name": "gone"}}`,
+		"/whisk.system/packages/util":     `{"name": "util", "namespace": "whisk.system", "publish": true}`,
+		"/whisk.system/actions/util/date": `{"name": "date", "namespace": "whisk.system/util"}`,
+		"/other/packages/private":         `{"name": "private", "namespace": "other", "publish": false}`,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	path := r.URL.Path[strings.Index(r.URL.Path, "/namespaces/")+len("/namespaces"):]
+	if path == "/_/activations" {
+		if r.URL.Query().Get("name") == "webhook" {
+			w.Write([]byte(`[{"activationId": "1", "name": "webhook", "start": 1760000000000}]`))
+		} else {
+			w.Write([]byte(`[]`))
+		}
+		return
+	}
+	if body, ok := entities[path]; ok {
+		w.Write([]byte(body))
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte(`{"error": "The requested resource does not exist.", "code": 1}`))
+}
+
+func ruleCheckTestRule(status string, trigger string, action string) *whisk.Rule {
+	split := func(name string) map[string]interface{} {
+		i := strings.LastIndex(name, "/")
+		return map[string]interface{}{"path": name[:i], "name": name[i+1:]}
+	}
+	return &whisk.Rule{Namespace: "guest", Name: "r", Status: status, Trigger: split(trigger), Action: split(action)}
+}
+
+func ruleCheckSeverities(diagnostics []ruleDiagnostic) []string {
+	var severities []string
+	for _, diagnostic := range diagnostics {
+		severities = append(severities, diagnostic.severity)
+	}
+	return severities
+}
+
+func TestCheckRule(t *testing.T) {
+	newTestClient(t, ruleCheckTestController)
+
+	diagnostics := checkRule(ruleCheckTestRule("active", "guest/alarm", "guest/hello"))
+	assert.Equal(t, []string{RULE_CHECK_OK, RULE_CHECK_OK}, ruleCheckSeverities(diagnostics))
+	assert.Contains(t, diagnostics[0].message, "is fed by /whisk.system/alarms/alarm")
+
+	diagnostics = checkRule(ruleCheckTestRule("inactive", "guest/manual", "whisk.system/util/date"))
+	assert.Equal(t, []string{RULE_CHECK_WARNING, RULE_CHECK_WARNING, RULE_CHECK_OK}, ruleCheckSeverities(diagnostics))
+	assert.Contains(t, diagnostics[0].message, "wsk rule enable r")
+	assert.Contains(t, diagnostics[1].message, "nothing appears to fire it")
+
+	diagnostics = checkRule(ruleCheckTestRule("active", "guest/webhook", "guest/raw"))
+	assert.Equal(t, []string{RULE_CHECK_OK, RULE_CHECK_WARNING}, ruleCheckSeverities(diagnostics))
+	assert.Contains(t, diagnostics[0].message, "was last fired at")
+	assert.Contains(t, diagnostics[1].message, "raw HTTP web action")
+
+	diagnostics = checkRule(ruleCheckTestRule("active", "other/alarm", "guest/stale/hello"))
+	assert.Equal(t, []string{RULE_CHECK_ERROR, RULE_CHECK_ERROR}, ruleCheckSeverities(diagnostics))
+	assert.Contains(t, diagnostics[0].message, "not in the namespace of the rule")
+	assert.Contains(t, diagnostics[1].message, "refers to /other/gone")
+
+	diagnostics = checkRule(ruleCheckTestRule("active", "guest/missing", "other/private/hello"))
+	assert.Equal(t, []string{RULE_CHECK_ERROR, RULE_CHECK_ERROR}, ruleCheckSeverities(diagnostics))
+	assert.Contains(t, diagnostics[0].message, "trigger /guest/missing cannot be read")
+	assert.Contains(t, diagnostics[1].message, "is not reachable from the namespace of the rule")
+
+	diagnostics = checkRule(ruleCheckTestRule("active", "guest/alarm", "guest/missing"))
+	assert.Equal(t, RULE_CHECK_ERROR, diagnostics[1].severity)
+	assert.Contains(t, diagnostics[1].message, "action /guest/missing cannot be read")
+}
@@ -0,0 +1,85 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRuleStatuses(t *testing.T) {
+	var mutex sync.Mutex
+	running, peak, requests := 0, 0, 0
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		running++
+		requests++
+		peak = max(peak, running)
+		mutex.Unlock()
+		defer func() {
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		if name == "r3" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": "The server encountered an error."}`))
+			return
+		}
+		w.Write([]byte(`{"name": "` + name + `", "namespace": "guest", "status": "active"}`))
+	})
+
+	savedCache := ruleStatusCache
+	ruleStatusCache = &ruleCache{entries: make(map[string]ruleCacheEntry)}
+	t.Cleanup(func() { ruleStatusCache = savedCache })
+
+	var list []whisk.Rule
+	for i := 0; i < 3*RULE_STATUS_CONCURRENCY; i++ {
+		list = append(list, whisk.Rule{Namespace: "guest", Name: fmt.Sprintf("r%d", i)})
+	}
+
+	getRuleStatuses(list)
+	assert.Equal(t, len(list), requests)
+	assert.True(t, peak > 1 && peak <= RULE_STATUS_CONCURRENCY, "peak concurrency %d", peak)
+	assert.Equal(t, RULE_STATUS_UNKNOWN, list[3].Status)
+	for i, rule := range list {
+		if i != 3 {
+			assert.Equal(t, "active", rule.Status)
+		}
+	}
+
+	getRuleStatuses(list)
+	assert.Equal(t, len(list)+1, requests)
+
+	ruleStatusCache.forget("r0")
+	getRuleStatuses(list[:1])
+	assert.Equal(t, len(list)+2, requests)
+}
@@ -0,0 +1,358 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DefaultHostInfoCache = "~/.wskhostinfo"
+	HOST_INFO_CACHE_TTL  = 24 * time.Hour
+	MAX_KIND_SUGGESTIONS = 3
+)
+
+// hostInfo is the part of the information the API host serves at its root that the CLI uses
+type hostInfo struct {
+	Runtimes map[string][]hostRuntime `json:"runtimes"`
+	Limits   *hostLimits              `json:"limits,omitempty"`
+	fetched  bool                     // fetched from the API host by this process rather than read from the cache
+}
+
+type hostRuntime struct {
+	Kind       string `json:"kind"`
+	Image      string `json:"image,omitempty"`
+	Default    bool   `json:"default,omitempty"`
+	Deprecated bool   `json:"deprecated,omitempty"`
+}
+
+type hostInfoCacheEntry struct {
+	Fetched int64     `json:"fetched"`
+	Info    *hostInfo `json:"info"`
+}
+
+// The host information fetched by this process, by API host
+var fetchedHostInfo = make(map[string]*hostInfo)
+
+var runtimesCmd = &cobra.Command{
+	Use:   "runtimes",
+	Short: wski18n.T("work with the runtimes of the API host"),
+}
+
+var runtimesListCmd = &cobra.Command{
+	Use:           "list",
+	Short:         wski18n.T("list the action kinds the API host supports"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := getHostInfo()
+		if err != nil {
+			return err
+		}
+
+		printRuntimes(info)
+		return nil
+	},
+}
+
+// getHostInfoCachePath() returns the path of the host information cache. The WSK_HOST_INFO_CACHE
+// environment variable overrides the default path; an empty value turns the cache off.
+func getHostInfoCachePath() string {
+	if cachePath, envExists := os.LookupEnv("WSK_HOST_INFO_CACHE"); envExists {
+		return cachePath
+	}
+
+	cachePath, err := homedir.Expand(DefaultHostInfoCache)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "homedir.Expand(%s) failed: %s\n", DefaultHostInfoCache, err)
+		return ""
+	}
+
+	return cachePath
+}
+
+func readHostInfoCache(cachePath string) map[string]hostInfoCacheEntry {
+	entries := make(map[string]hostInfoCacheEntry)
+	if len(cachePath) == 0 {
+		return entries
+	}
+
+	content, err := ioutil.ReadFile(cachePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			whisk.Debug(whisk.DbgWarn, "ioutil.ReadFile(%s) failed: %s\n", cachePath, err)
+		}
+		return entries
+	}
+
+	if err = json.Unmarshal(content, &entries); err != nil {
+		whisk.Debug(whisk.DbgWarn, "Host information cache %s is malformed: %s\n", cachePath, err)
+		return make(map[string]hostInfoCacheEntry)
+	}
+	return entries
+}
+
+func writeHostInfoCache(cachePath string, entries map[string]hostInfoCacheEntry) {
+	if len(cachePath) == 0 {
+		return
+	}
+
+	content, err := json.Marshal(entries)
+	if err != nil {
+		whisk.Debug(whisk.DbgWarn, "json.Marshal(%#v) failed: %s\n", entries, err)
+		return
+	}
+	if err = ioutil.WriteFile(cachePath, content, 0600); err != nil {
+		whisk.Debug(whisk.DbgWarn, "ioutil.WriteFile(%s) failed: %s\n", cachePath, err)
+	}
+}
+
+// getHostInfo() returns the runtimes and limits of the API host. They are cached on disk for
+// HOST_INFO_CACHE_TTL; a stale copy is used when the API host cannot be reached.
+func getHostInfo() (*hostInfo, error) {
+	host := Client.BaseURL.Host
+	if info, ok := fetchedHostInfo[host]; ok {
+		return info, nil
+	}
+
+	cachePath := getHostInfoCachePath()
+	entries := readHostInfoCache(cachePath)
+	entry, cached := entries[host]
+	if cached && entry.Info != nil && time.Since(time.Unix(entry.Fetched, 0)) < HOST_INFO_CACHE_TTL {
+		whisk.Debug(whisk.DbgInfo, "Using the cached information of API host %s\n", host)
+		fetchedHostInfo[host] = entry.Info
+		return entry.Info, nil
+	}
+
+	info, err := refreshHostInfo()
+	if err != nil {
+		if cached && entry.Info != nil {
+			whisk.Debug(whisk.DbgWarn, "Using the stale cached information of API host %s: %s\n", host, err)
+			fetchedHostInfo[host] = entry.Info
+			return entry.Info, nil
+		}
+		return nil, err
+	}
+	return info, nil
+}
+
+// refreshHostInfo() fetches the runtimes and limits of the API host, bypassing the cache, and caches
+// them
+func refreshHostInfo() (*hostInfo, error) {
+	info, err := fetchHostInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	host := Client.BaseURL.Host
+	fetchedHostInfo[host] = info
+	cachePath := getHostInfoCachePath()
+	entries := readHostInfoCache(cachePath)
+	entries[host] = hostInfoCacheEntry{Fetched: time.Now().Unix(), Info: info}
+	writeHostInfoCache(cachePath, entries)
+	return info, nil
+}
+
+// fetchHostInfo() gets the information the API host serves at its root, next to the API paths
+// whose build 'wsk property get' shows
+func fetchHostInfo() (*hostInfo, error) {
+	rootURL := Client.BaseURL.ResolveReference(&url.URL{Path: "/"})
+	req, err := http.NewRequest("GET", rootURL.String(), nil)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "http.NewRequest(GET, %s) error: %s\n", rootURL, err)
+		return nil, hostInfoError(err)
+	}
+
+	info := &hostInfo{fetched: true}
+	if _, err = Client.Do(req, info, whisk.ExitWithSuccessOnTimeout); err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Do(GET, %s) error: %s\n", rootURL, err)
+		return nil, hostInfoError(err)
+	}
+	return info, nil
+}
+
+func hostInfoError(err error) error {
+	errStr := wski18n.T("Unable to obtain the runtimes of the API host: {{.err}}", map[string]interface{}{"err": err})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+// sortedRuntimeFamilies returns the runtime families of the API host in alphabetical order
+func (info *hostInfo) sortedRuntimeFamilies() []string {
+	var families []string
+	for family := range info.Runtimes {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	return families
+}
+
+// findRuntime returns the runtime of a kind, where "<family>:default" stands for the default runtime
+// of the family
+func (info *hostInfo) findRuntime(kind string) *hostRuntime {
+	family := strings.Split(kind, ":")[0]
+	for i, runtime := range info.Runtimes[family] {
+		if runtime.Kind == kind || (kind == family+":"+DEFAULT && runtime.Default) {
+			return &info.Runtimes[family][i]
+		}
+	}
+	return nil
+}
+
+// suggestKinds returns up to MAX_KIND_SUGGESTIONS kinds close to an unknown one, the closest first. The
+// kinds of its family are suggested when none is close.
+func (info *hostInfo) suggestKinds(kind string) []string {
+	type suggestion struct {
+		kind     string
+		distance int
+	}
+
+	var suggestions []suggestion
+	for _, family := range info.sortedRuntimeFamilies() {
+		for _, runtime := range info.Runtimes[family] {
+			if distance := editDistance(kind, runtime.Kind); distance <= 2 {
+				suggestions = append(suggestions, suggestion{runtime.Kind, distance})
+			}
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool { return suggestions[i].distance < suggestions[j].distance })
+
+	var kinds []string
+	for _, suggestion := range suggestions {
+		kinds = append(kinds, suggestion.kind)
+	}
+	if len(kinds) == 0 {
+		for _, runtime := range info.Runtimes[strings.Split(kind, ":")[0]] {
+			kinds = append(kinds, runtime.Kind)
+		}
+	}
+	return kinds[:min(len(kinds), MAX_KIND_SUGGESTIONS)]
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// checkActionKind checks the kind of an action against the runtimes of the API host, warning when it
+// is deprecated, and replaces a "<family>:default" kind with the kind of the default runtime when
+// resolveDefault is set. The kind is left to the API host to check when its runtimes are unknown.
+// Cached runtimes may be out of date, so a kind they do not know is checked again against runtimes
+// fetched from the API host, or only warned about when the API host cannot be reached.
+func checkActionKind(exec *whisk.Exec, resolveDefault bool) error {
+	if exec == nil || len(exec.Kind) == 0 || exec.Kind == BLACKBOX || exec.Kind == SEQUENCE {
+		return nil
+	}
+
+	info, err := getHostInfo()
+	if err != nil {
+		if resolveDefault {
+			return err
+		}
+		whisk.Debug(whisk.DbgWarn, "The kind '%s' is not checked: %s\n", exec.Kind, err)
+		return nil
+	}
+
+	runtime := info.findRuntime(exec.Kind)
+	if runtime == nil && !info.fetched {
+		whisk.Debug(whisk.DbgInfo, "The cached runtimes do not have kind '%s'; fetching them again\n", exec.Kind)
+		fresh, refreshErr := refreshHostInfo()
+		if refreshErr != nil {
+			printWarning(unsupportedKindError(info, exec.Kind).Error())
+			return nil
+		}
+		info = fresh
+		runtime = info.findRuntime(exec.Kind)
+	}
+	if runtime == nil {
+		return unsupportedKindError(info, exec.Kind)
+	}
+
+	if runtime.Deprecated {
+		printWarning(wski18n.T("The kind '{{.kind}}' is deprecated", map[string]interface{}{"kind": runtime.Kind}))
+	}
+	if resolveDefault && exec.Kind != runtime.Kind {
+		whisk.Debug(whisk.DbgInfo, "Resolved kind '%s' to '%s'\n", exec.Kind, runtime.Kind)
+		exec.Kind = runtime.Kind
+	}
+	return nil
+}
+
+func unsupportedKindError(info *hostInfo, kind string) error {
+	errStr := wski18n.T("The kind '{{.kind}}' is not supported by the API host.", map[string]interface{}{"kind": kind})
+	if suggestions := info.suggestKinds(kind); len(suggestions) > 0 {
+		errStr = errStr + " " + wski18n.T("Did you mean {{.kinds}}?", map[string]interface{}{"kinds": strings.Join(suggestions, ", ")})
+	}
+	errStr = errStr + " " + wski18n.T("Run 'wsk runtimes list' to see the supported kinds.")
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func printRuntimes(info *hostInfo) {
+	fmt.Fprintf(color.Output, "%s\n", boldString("runtimes"))
+	for _, family := range info.sortedRuntimeFamilies() {
+		for _, runtime := range info.Runtimes[family] {
+			var markers []string
+			if runtime.Default {
+				markers = append(markers, wski18n.T("default"))
+			}
+			if runtime.Deprecated {
+				markers = append(markers, wski18n.T("deprecated"))
+			}
+			fmt.Fprintf(color.Output, "%-20s %-50s %s\n", runtime.Kind, runtime.Image, strings.Join(markers, ", "))
+		}
+	}
+}
+
+func init() {
+	runtimesCmd.AddCommand(
+		runtimesListCmd,
+	)
+}
@@ -0,0 +1,136 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+const runtimesTestHostInfo = `{
+	"description": "OpenWhisk",
+	"runtimes": {
+		"nodejs": [
+			{"kind": "nodejs:12", "image": "openwhisk/action-nodejs-v12:nightly", "deprecated": true},
+			{"kind": "nodejs:14", "image": "openwhisk/action-nodejs-v14:nightly", "default": true}
+		],
+		"python": [
+			{"kind": "python:3", "image": "openwhisk/action-python-v3.7:nightly", "default": true}
+		]
+	}
+}`
+
+// newHostInfoTestCache points the host information cache to a temporary file for the test
+func newHostInfoTestCache(t *testing.T) string {
+	cachePath := filepath.Join(t.TempDir(), "hostinfo")
+	savedCache, envExists := os.LookupEnv("WSK_HOST_INFO_CACHE")
+	os.Setenv("WSK_HOST_INFO_CACHE", cachePath)
+	fetchedHostInfo = make(map[string]*hostInfo)
+	t.Cleanup(func() {
+		fetchedHostInfo = make(map[string]*hostInfo)
+		if envExists {
+			os.Setenv("WSK_HOST_INFO_CACHE", savedCache)
+		} else {
+			os.Unsetenv("WSK_HOST_INFO_CACHE")
+		}
+	})
+	return cachePath
+}
+
+func TestHostInfoRuntimes(t *testing.T) {
+	requests := 0
+	failing := false
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/", r.URL.Path)
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(runtimesTestHostInfo))
+	})
+
+	cachePath := newHostInfoTestCache(t)
+
+	info, err := getHostInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"nodejs", "python"}, info.sortedRuntimeFamilies())
+	assert.Equal(t, "nodejs:14", info.findRuntime("nodejs:default").Kind)
+	assert.Nil(t, info.findRuntime("nodejs:16"))
+
+	// The information is cached on disk, and a stale copy is used when the API host fails
+	fetchedHostInfo = make(map[string]*hostInfo)
+	_, err = getHostInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, requests)
+
+	entries := readHostInfoCache(cachePath)
+	entry := entries[Client.BaseURL.Host]
+	entry.Fetched = 0
+	entries[Client.BaseURL.Host] = entry
+	writeHostInfoCache(cachePath, entries)
+	fetchedHostInfo = make(map[string]*hostInfo)
+	failing = true
+	info, err = getHostInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Len(t, info.Runtimes["nodejs"], 2)
+
+	// A kind the stale runtimes do not know is only warned about while the API host cannot be reached
+	assert.Nil(t, checkActionKind(&whisk.Exec{Kind: "nodejs:15"}, false))
+	assert.Equal(t, 3, requests)
+
+	// A kind added since the runtimes were cached is found in the runtimes fetched again
+	writeHostInfoCache(cachePath, map[string]hostInfoCacheEntry{Client.BaseURL.Host: {
+		Fetched: time.Now().Unix(),
+		Info:    &hostInfo{Runtimes: map[string][]hostRuntime{"nodejs": {{Kind: "nodejs:12", Default: true}}}},
+	}})
+	fetchedHostInfo = make(map[string]*hostInfo)
+	failing = false
+	assert.Nil(t, checkActionKind(&whisk.Exec{Kind: "nodejs:14"}, false))
+	assert.Equal(t, 4, requests)
+
+	exec := &whisk.Exec{Kind: "nodejs:default"}
+	assert.Nil(t, checkActionKind(exec, false))
+	assert.Equal(t, "nodejs:default", exec.Kind)
+	assert.Nil(t, checkActionKind(exec, true))
+	assert.Equal(t, "nodejs:14", exec.Kind)
+
+	// Runtimes fetched by this process are not fetched again
+	err = checkActionKind(&whisk.Exec{Kind: "nodejs:15"}, false)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "The kind 'nodejs:15' is not supported by the API host. Did you mean nodejs:12, nodejs:14?")
+
+	err = checkActionKind(&whisk.Exec{Kind: "python:2.7"}, false)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Did you mean python:3?")
+
+	assert.Nil(t, checkActionKind(&whisk.Exec{Kind: BLACKBOX}, false))
+	assert.Nil(t, checkActionKind(&whisk.Exec{Kind: "nodejs:12"}, false))
+	assert.Equal(t, 4, requests)
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
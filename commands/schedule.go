@@ -0,0 +1,449 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+)
+
+const DefaultAlarmFeed = "/whisk.system/alarms/alarm"
+
+// ALARM_FEED_PROPERTY names the properties file entry that overrides the alarm feed action
+const ALARM_FEED_PROPERTY = "ALARM_FEED"
+const SCHEDULE_ANNOTATION = "schedule"
+const SCHEDULE_CRON = "cron"
+const SCHEDULE_TIMEZONE = "timezone"
+const SCHEDULE_START = "startDate"
+const SCHEDULE_STOP = "stopDate"
+const SCHEDULE_PAYLOAD = "trigger_payload"
+
+// Fire times are searched at most this far ahead; a schedule such as "0 0 30 2 *" never fires
+const SCHEDULE_SEARCH_LIMIT = 5 * 366 * 24 * time.Hour
+
+var scheduleDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day-of-week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// cronSchedule is a parsed five field cron expression; each field is a bit set of the matching values
+type cronSchedule struct {
+	expression string
+	fields     [5]uint64
+	anyDay     bool
+	anyWeekday bool
+	location   *time.Location
+}
+
+func parseCronSchedule(expression string, location *time.Location) (*cronSchedule, error) {
+	values := strings.Fields(expression)
+	if len(values) != len(cronFields) {
+		errMsg := wski18n.T("expected 5 fields (minute hour day-of-month month day-of-week) but found {{.count}}",
+			map[string]interface{}{"count": len(values)})
+		return nil, cronScheduleError(expression, errMsg)
+	}
+
+	schedule := &cronSchedule{expression: strings.Join(values, " "), location: location}
+	for i, value := range values {
+		bits, err := parseCronField(value, cronFields[i])
+		if err != nil {
+			return nil, cronScheduleError(expression, err.Error())
+		}
+		schedule.fields[i] = bits
+	}
+
+	// Sunday may be written as 0 or 7
+	if schedule.fields[4]&(1<<7) != 0 {
+		schedule.fields[4] |= 1
+	}
+	schedule.anyDay = strings.HasPrefix(values[2], "*")
+	schedule.anyWeekday = strings.HasPrefix(values[4], "*")
+
+	return schedule, nil
+}
+
+func cronScheduleError(expression string, reason string) error {
+	errMsg := wski18n.T("Invalid schedule '{{.schedule}}': {{.err}}",
+		map[string]interface{}{"schedule": expression, "err": reason})
+	return whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+// parseCronField accepts "*", values, names, ranges and steps, separated by commas, e.g. "1-5,*/15,MON"
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+
+	for _, item := range strings.Split(value, ",") {
+		var err error
+		rangeExpr, step := item, 1
+
+		if i := strings.Index(item, "/"); i >= 0 {
+			rangeExpr = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step < 1 {
+				return 0, errors.New(wski18n.T("invalid step '{{.value}}' in the {{.field}} field",
+					map[string]interface{}{"value": item[i+1:], "field": field.name}))
+			}
+		}
+
+		low, high := field.min, field.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			if low, err = cronFieldValue(bounds[0], field); err != nil {
+				return 0, err
+			}
+			high = low
+			if len(bounds) > 1 {
+				if high, err = cronFieldValue(bounds[1], field); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "5/15" is short for "5-max/15"
+				high = field.max
+			}
+			if low > high {
+				return 0, errors.New(wski18n.T("range '{{.value}}' in the {{.field}} field ends before it starts",
+					map[string]interface{}{"value": rangeExpr, "field": field.name}))
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func cronFieldValue(value string, field cronField) (int, error) {
+	for i, name := range field.names {
+		if strings.EqualFold(value, name) {
+			if field.min == 1 {
+				return i + 1, nil
+			}
+			return i, nil
+		}
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(wski18n.T("invalid value '{{.value}}' in the {{.field}} field",
+			map[string]interface{}{"value": value, "field": field.name}))
+	}
+	if number < field.min || number > field.max {
+		return 0, errors.New(wski18n.T("value {{.value}} in the {{.field}} field is outside {{.min}}-{{.max}}",
+			map[string]interface{}{"value": number, "field": field.name, "min": field.min, "max": field.max}))
+	}
+
+	return number, nil
+}
+
+func (s *cronSchedule) has(field int, value int) bool {
+	return s.fields[field]&(1<<uint(value)) != 0
+}
+
+// A restricted day-of-month and day-of-week match when either one does, as in crontab(5)
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	day := s.has(2, t.Day())
+	weekday := s.has(4, int(t.Weekday()))
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// next returns the first fire time strictly after the given time, or the zero time if there is none
+func (s *cronSchedule) next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(SCHEDULE_SEARCH_LIMIT)
+
+	for t.Before(limit) {
+		if !s.has(3, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.has(1, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.has(0, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// nextTimes returns up to count fire times after the given time that fall before stop, if stop is set
+func (s *cronSchedule) nextTimes(after time.Time, count int, stop time.Time) []time.Time {
+	var times []time.Time
+
+	for len(times) < count {
+		after = s.next(after)
+		if after.IsZero() || (!stop.IsZero() && !after.Before(stop)) {
+			break
+		}
+		times = append(times, after)
+	}
+
+	return times
+}
+
+// everyToCron converts an interval such as "10m" or "2h" into the equivalent cron expression;
+// only intervals that divide an hour or a day evenly have one
+func everyToCron(every string) (string, error) {
+	interval, err := time.ParseDuration(every)
+	if err == nil && interval%time.Minute == 0 && interval > 0 {
+		minutes := int(interval / time.Minute)
+		switch {
+		case minutes < 60 && 60%minutes == 0:
+			return fmt.Sprintf("*/%d * * * *", minutes), nil
+		case minutes%60 == 0 && minutes < 24*60 && (24*60)%minutes == 0:
+			return fmt.Sprintf("0 */%d * * *", minutes/60), nil
+		case minutes == 24*60:
+			return "0 0 * * *", nil
+		}
+	}
+
+	errMsg := wski18n.T("Invalid interval '{{.every}}': use whole minutes that divide an hour, or whole hours that divide a day, e.g. 10m or 2h",
+		map[string]interface{}{"every": every})
+	return "", whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func parseScheduleDate(flag string, value string, location *time.Location) (time.Time, error) {
+	for _, layout := range scheduleDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date, nil
+		}
+	}
+
+	errMsg := wski18n.T("Invalid --{{.flag}} date '{{.date}}': use RFC 3339 or YYYY-MM-DD[THH:MM[:SS]]",
+		map[string]interface{}{"flag": flag, "date": value})
+	return time.Time{}, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+}
+
+func loadScheduleLocation(timezone string) (*time.Location, error) {
+	if len(timezone) == 0 {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "time.LoadLocation(%s) failed: %s\n", timezone, err)
+		errMsg := wski18n.T("Invalid timezone '{{.timezone}}': {{.err}}", map[string]interface{}{"timezone": timezone, "err": err})
+		return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	return location, nil
+}
+
+// triggerSchedule builds the alarm feed parameters from the --schedule, --every, --timezone, --start
+// and --stop flags. It returns nil when no schedule was requested.
+func triggerSchedule() (map[string]interface{}, error) {
+	var err error
+	var location *time.Location
+
+	expression := Flags.trigger.schedule
+	if len(expression) == 0 && len(Flags.trigger.every) == 0 {
+		if len(Flags.trigger.timezone) > 0 || len(Flags.trigger.start) > 0 || len(Flags.trigger.stop) > 0 {
+			errMsg := wski18n.T("The --timezone, --start and --stop flags require --schedule or --every.")
+			return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+		}
+		return nil, nil
+	}
+	if len(expression) > 0 && len(Flags.trigger.every) > 0 {
+		errMsg := wski18n.T("The --schedule and --every flags cannot be combined.")
+		return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+	}
+
+	if len(Flags.trigger.every) > 0 {
+		if expression, err = everyToCron(Flags.trigger.every); err != nil {
+			return nil, err
+		}
+	}
+
+	if location, err = loadScheduleLocation(Flags.trigger.timezone); err != nil {
+		return nil, err
+	}
+	if _, err = parseCronSchedule(expression, location); err != nil {
+		return nil, err
+	}
+
+	schedule := map[string]interface{}{SCHEDULE_CRON: expression}
+	if len(Flags.trigger.timezone) > 0 {
+		schedule[SCHEDULE_TIMEZONE] = Flags.trigger.timezone
+	}
+
+	var start, stop time.Time
+	if len(Flags.trigger.start) > 0 {
+		if start, err = parseScheduleDate("start", Flags.trigger.start, location); err != nil {
+			return nil, err
+		}
+		schedule[SCHEDULE_START] = start.UTC().Format(time.RFC3339)
+	}
+	if len(Flags.trigger.stop) > 0 {
+		if stop, err = parseScheduleDate("stop", Flags.trigger.stop, location); err != nil {
+			return nil, err
+		}
+		if !start.IsZero() && !stop.After(start) {
+			errMsg := wski18n.T("The --stop date must be after the --start date.")
+			return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+		schedule[SCHEDULE_STOP] = stop.UTC().Format(time.RFC3339)
+	}
+
+	return schedule, nil
+}
+
+// alarmFeed returns the feed action that implements schedules: --feed when given, then the
+// ALARM_FEED entry of the properties file, then the system alarms package
+func alarmFeed() string {
+	if len(Flags.common.feed) > 0 {
+		return Flags.common.feed
+	}
+
+	props, err := ReadProps(Properties.PropsFile)
+	if err != nil {
+		whisk.Debug(whisk.DbgWarn, "ReadProps(%s) failed: %s\n", Properties.PropsFile, err)
+	} else if feed := props[ALARM_FEED_PROPERTY]; len(feed) > 0 {
+		return feed
+	}
+
+	return DefaultAlarmFeed
+}
+
+// applyTriggerSchedule turns a requested schedule into a feed invocation of the alarm feed; the
+// schedule is also recorded as an annotation so that trigger get --summary can show fire times.
+func applyTriggerSchedule() error {
+	schedule, err := triggerSchedule()
+	if err != nil || schedule == nil {
+		return err
+	}
+
+	content, _ := json.Marshal(schedule)
+	Flags.common.feed = alarmFeed()
+	Flags.common.annotation = append(Flags.common.annotation, getFormattedJSON(SCHEDULE_ANNOTATION, string(content)))
+
+	var feedParams []string
+	for _, key := range []string{SCHEDULE_CRON, SCHEDULE_TIMEZONE, SCHEDULE_START, SCHEDULE_STOP} {
+		if value, ok := schedule[key]; ok {
+			feedParams = append(feedParams, getFormattedJSON(key, value.(string)))
+		}
+	}
+
+	if userIndicatesToUseOldTriggerCommand() {
+		// --param values are what the trigger fires with, so they become the alarm payload
+		if len(Flags.common.param) > 0 {
+			payload, err := getJSONFromStrings(Flags.common.param, false)
+			if err != nil {
+				return err
+			}
+			content, _ := json.Marshal(payload)
+			feedParams = append(feedParams, getFormattedJSON(SCHEDULE_PAYLOAD, string(content)))
+		}
+		Flags.common.param = feedParams
+	} else {
+		Flags.trigger.feedParam = append(Flags.trigger.feedParam, feedParams...)
+	}
+
+	return nil
+}
+
+// printTriggerSchedule prints the schedule recorded on a trigger followed by its next fire times
+func printTriggerSchedule(trigger *whisk.Trigger, count int) error {
+	content := trigger.Annotations.GetValue(SCHEDULE_ANNOTATION)
+	schedule, ok := content.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	expression, _ := schedule[SCHEDULE_CRON].(string)
+	timezone, _ := schedule[SCHEDULE_TIMEZONE].(string)
+	location, err := loadScheduleLocation(timezone)
+	if err != nil {
+		return err
+	}
+	cron, err := parseCronSchedule(expression, location)
+	if err != nil {
+		return err
+	}
+
+	after := time.Now()
+	var stop time.Time
+	if start, ok := schedule[SCHEDULE_START].(string); ok {
+		if date, err := time.Parse(time.RFC3339, start); err == nil && date.After(after) {
+			after = date.Add(-time.Minute)
+		}
+	}
+	if value, ok := schedule[SCHEDULE_STOP].(string); ok {
+		stop, _ = time.Parse(time.RFC3339, value)
+	}
+
+	fireTimes := wski18n.T("none")
+	if times := cron.nextTimes(after, count, stop); len(times) > 0 {
+		var formatted []string
+		for _, t := range times {
+			formatted = append(formatted, t.Format(time.RFC3339))
+		}
+		fireTimes = strings.Join(formatted, ", ")
+	}
+
+	fmt.Fprintf(color.Output, "   (%s: %s, %s)\n", boldString(wski18n.T("schedule")), cron.expression, location)
+	fmt.Fprintf(color.Output, "   (%s: %s)\n", boldString(wski18n.T("next fire times")), fireTimes)
+
+	return nil
+}
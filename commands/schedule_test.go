@@ -0,0 +1,147 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	tests := map[string]string{
+		"* * * *":         "expected 5 fields",
+		"60 * * * *":      "value 60 in the minute field is outside 0-59",
+		"* * 0 * *":       "value 0 in the day-of-month field is outside 1-31",
+		"* * * FOO *":     "invalid value 'FOO' in the month field",
+		"*/0 * * * *":     "invalid step '0' in the minute field",
+		"* 10-2 * * *":    "range '10-2' in the hour field ends before it starts",
+		"* * * * MON-SUN": "range 'MON-SUN' in the day-of-week field ends before it starts",
+	}
+
+	for expression, reason := range tests {
+		_, err := parseCronSchedule(expression, time.UTC)
+		if assert.NotNil(t, err, expression) {
+			assert.Contains(t, err.Error(), reason, expression)
+			assert.Contains(t, err.Error(), "Invalid schedule '"+expression+"'")
+		}
+	}
+}
+
+func TestCronScheduleNextTimes(t *testing.T) {
+	after := time.Date(2026, time.October, 19, 10, 3, 30, 0, time.UTC)
+
+	schedule, err := parseCronSchedule("*/5 * * * *", time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2026, time.October, 19, 10, 5, 0, 0, time.UTC),
+		time.Date(2026, time.October, 19, 10, 10, 0, 0, time.UTC),
+	}, schedule.nextTimes(after, 2, time.Time{}))
+
+	// day-of-month and day-of-week match when either does; Sunday may be written as 7
+	schedule, err = parseCronSchedule("30 9 1 * 7", time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2026, time.October, 25, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, time.November, 1, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, time.November, 8, 9, 30, 0, 0, time.UTC),
+	}, schedule.nextTimes(after, 3, time.Time{}))
+
+	schedule, err = parseCronSchedule("0 8 * JAN,jun MON-FRI", time.UTC)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2027, time.January, 1, 8, 0, 0, 0, time.UTC), schedule.next(after))
+
+	stop := time.Date(2026, time.October, 19, 10, 10, 0, 0, time.UTC)
+	schedule, _ = parseCronSchedule("*/5 * * * *", time.UTC)
+	assert.Equal(t, 1, len(schedule.nextTimes(after, 5, stop)))
+
+	schedule, _ = parseCronSchedule("0 0 30 2 *", time.UTC)
+	assert.Empty(t, schedule.nextTimes(after, 5, time.Time{}))
+
+	location, err := time.LoadLocation("Asia/Kolkata")
+	if assert.Nil(t, err) {
+		schedule, _ = parseCronSchedule("0 * * * *", location)
+		assert.Equal(t, time.Date(2026, time.October, 19, 16, 0, 0, 0, location), schedule.next(after))
+	}
+}
+
+func TestEveryToCron(t *testing.T) {
+	tests := map[string]string{
+		"10m": "*/10 * * * *",
+		"1m":  "*/1 * * * *",
+		"2h":  "0 */2 * * *",
+		"24h": "0 0 * * *",
+	}
+	for every, expression := range tests {
+		cron, err := everyToCron(every)
+		assert.Nil(t, err, every)
+		assert.Equal(t, expression, cron, every)
+	}
+
+	for _, every := range []string{"7m", "30s", "5h", "48h", "soon"} {
+		_, err := everyToCron(every)
+		assert.NotNil(t, err, every)
+	}
+}
+
+func TestApplyTriggerSchedule(t *testing.T) {
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+
+	Flags.common.feed = "/guest/alarms/alarm"
+	Flags.common.param = []string{`{"name": "cron job"}`}
+	Flags.trigger.every = "15m"
+	Flags.trigger.timezone = "Europe/Paris"
+	Flags.trigger.start = "2026-11-01"
+	assert.Nil(t, applyTriggerSchedule())
+
+	assert.Equal(t, "/guest/alarms/alarm", Flags.common.feed)
+	assert.Equal(t, []string{
+		`{"cron": "*/15 * * * *"}`,
+		`{"timezone": "Europe/Paris"}`,
+		`{"startDate": "2026-10-31T23:00:00Z"}`,
+		`{"trigger_payload": {"name":"cron job"}}`,
+	}, Flags.common.param)
+	assert.Equal(t, []string{`{"schedule": {"cron":"*/15 * * * *","startDate":"2026-10-31T23:00:00Z","timezone":"Europe/Paris"}}`},
+		Flags.common.annotation)
+
+	Flags = saved
+	Flags.trigger.triggerParam = []string{`{"name": "cron job"}`}
+	Flags.trigger.schedule = "0 9 * * MON"
+	assert.Nil(t, applyTriggerSchedule())
+	assert.Equal(t, []string{`{"cron": "0 9 * * MON"}`}, Flags.trigger.feedParam)
+	assert.Empty(t, Flags.common.param)
+
+	Flags = saved
+	Flags.trigger.schedule = "0 9 * * MON"
+	Flags.trigger.every = "10m"
+	assert.NotNil(t, applyTriggerSchedule())
+
+	Flags = saved
+	Flags.trigger.stop = "2026-11-01"
+	assert.NotNil(t, applyTriggerSchedule())
+
+	Flags = saved
+	Flags.trigger.schedule = "0 9 * * MON"
+	Flags.trigger.start = "2026-11-02"
+	Flags.trigger.stop = "2026-11-01"
+	assert.NotNil(t, applyTriggerSchedule())
+}
@@ -97,7 +97,7 @@ func createOrUpdate(Client *whisk.Client, fqn *QualifiedName, trigger *whisk.Tri
 }
 
 var triggerFireCmd = &cobra.Command{
-	Use:           "fire TRIGGER_NAME [PAYLOAD]",
+	Use:           "fire TRIGGER_NAME [PAYLOAD | -]",
 	Short:         wski18n.T("fire trigger event"),
 	SilenceUsage:  true,
 	SilenceErrors: true,
@@ -106,7 +106,7 @@ var triggerFireCmd = &cobra.Command{
 		var err error
 		var qualifiedName = new(QualifiedName)
 
-		if whiskErr := CheckArgs(args, 1, 1, "Trigger fire",
+		if whiskErr := CheckArgs(args, 1, 2, "Trigger fire",
 			wski18n.T("A trigger name is required.")); whiskErr != nil {
 			return whiskErr
 		}
@@ -115,28 +115,12 @@ var triggerFireCmd = &cobra.Command{
 			return NewQualifiedNameError(args[0], err)
 		}
 
-		parameters := getParameters(Flags.common.param, false, false)
-
-		// TODO get rid of these global modifiers
-		Client.Namespace = qualifiedName.GetNamespace()
-		trigResp, _, err := Client.Triggers.Fire(qualifiedName.GetEntityName(), parameters)
+		events, err := triggerFireEvents(args)
 		if err != nil {
-			whisk.Debug(whisk.DbgError, "Client.Triggers.Fire(%s, %#v) failed: %s\n", qualifiedName.GetEntityName(), parameters, err)
-			errStr := wski18n.T("Unable to fire trigger '{{.name}}': {{.err}}",
-				map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": err})
-			werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL,
-				whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-			return werr
+			return err
 		}
 
-		fmt.Fprintf(color.Output,
-			wski18n.T("{{.ok}} triggered /{{.namespace}}/{{.name}} with id {{.id}}\n",
-				map[string]interface{}{
-					"ok":        color.GreenString("ok:"),
-					"namespace": boldString(qualifiedName.GetNamespace()),
-					"name":      boldString(qualifiedName.GetEntityName()),
-					"id":        boldString(trigResp.ActivationId)}))
-		return nil
+		return fireTriggerEvents(qualifiedName, events)
 	},
 }
 
@@ -152,8 +136,11 @@ var triggerCreateCmd = &cobra.Command{
 }
 
 var triggerUpdateCmd = &cobra.Command{
-	Use:           "update TRIGGER_NAME",
-	Short:         wski18n.T("update an existing trigger, or create a trigger if it does not exist"),
+	Use:   "update TRIGGER_NAME",
+	Short: wski18n.T("update an existing trigger, or create a trigger if it does not exist"),
+	Long: wski18n.T("Update an existing trigger, or create a trigger if it does not exist. " +
+		"The schedule of a trigger created with --schedule or --every cannot be changed by an update; " +
+		"delete the trigger and create it again with the new schedule."),
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	PreRunE:       SetupClientConfig,
@@ -203,11 +190,22 @@ var triggerGetCmd = &cobra.Command{
 			return werr
 		}
 
+		// A template formats the trigger itself rather than the feed details
+		if len(Flags.common.template) > 0 {
+			return printTemplate(retTrigger, Flags.common.template)
+		}
+
 		// Get full feed name from trigger get request as it is needed to get the feed
 		if retTrigger != nil && retTrigger.Annotations != nil {
 			fullFeedName = getValueString(retTrigger.Annotations, "feed")
 		}
 
+		// A scheduled trigger is summarized from its schedule annotation rather than by reading the feed
+		if Flags.trigger.summary && retTrigger.Annotations.GetValue(SCHEDULE_ANNOTATION) != nil {
+			printSummary(retTrigger)
+			return printTriggerSchedule(retTrigger, Flags.trigger.next)
+		}
+
 		if len(fullFeedName) > 0 {
 			fullTriggerName := fmt.Sprintf("/%s/%s", qualifiedName.GetNamespace(), qualifiedName.GetEntityName())
 			Flags.common.param = append(Flags.common.param, getFormattedJSON(FEED_LIFECYCLE_EVENT, FEED_READ))
@@ -223,10 +221,12 @@ var triggerGetCmd = &cobra.Command{
 				printSummary(retTrigger)
 			} else {
 				if len(field) > 0 {
-					fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got trigger {{.name}}, displaying field {{.field}}\n",
-						map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName()),
-							"field": boldString(field)}))
-					printField(retTrigger, field)
+					if !printField(retTrigger, field) {
+						errMsg := wski18n.T("Invalid field filter '{{.arg}}'.", map[string]interface{}{"arg": field})
+						whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL,
+							whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+						return whiskErr
+					}
 				} else {
 					fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got trigger {{.name}}\n",
 						map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName())}))
@@ -337,25 +337,60 @@ var triggerListCmd = &cobra.Command{
 			Client.Namespace = qualifiedName.GetNamespace()
 		}
 
-		options := &whisk.TriggerListOptions{
-			Skip:  Flags.common.skip,
-			Limit: Flags.common.limit,
-		}
-		triggers, _, err := Client.Triggers.List(options)
+		var triggers []whisk.Trigger
+		query, err := newListQuery(Flags.common.filter, Flags.common.sortBy)
 		if err != nil {
-			whisk.Debug(whisk.DbgError, "Client.Triggers.List(%#v) for namespace '%s' failed: %s\n", options,
-				Client.Namespace, err)
-			errStr := wski18n.T("Unable to obtain the list of triggers for namespace '{{.name}}': {{.err}}",
-				map[string]interface{}{"name": getClientNamespace(), "err": err})
-			werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-			return werr
+			return err
+		}
+
+		// An explicit --sort-by order takes precedence over --name-sort
+		sortByName := Flags.common.nameSort && !query.sorted()
+
+		if Flags.common.all {
+			// Stream each page as it arrives unless the complete set is needed for sorting
+			stream := !sortByName && !query.sorted()
+			err = listAllPages(Flags.common.skip, func(skip int, limit int) (int, error) {
+				page, err := listTriggers(&whisk.TriggerListOptions{Skip: skip, Limit: limit})
+				if err != nil {
+					return 0, err
+				}
+
+				if stream {
+					printListPage(query.apply(page), skip == Flags.common.skip)
+				} else {
+					triggers = append(triggers, page...)
+				}
+				return len(page), nil
+			})
+			if err != nil || stream {
+				return err
+			}
+		} else {
+			triggers, err = listTriggers(&whisk.TriggerListOptions{Skip: Flags.common.skip, Limit: Flags.common.limit})
+			if err != nil {
+				return err
+			}
 		}
-		sortByName := Flags.common.nameSort
-		printList(triggers, sortByName)
+
+		printList(query.apply(triggers), sortByName)
 		return nil
 	},
 }
 
+func listTriggers(options *whisk.TriggerListOptions) ([]whisk.Trigger, error) {
+	triggers, _, err := Client.Triggers.List(options)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Triggers.List(%#v) for namespace '%s' failed: %s\n", options,
+			Client.Namespace, err)
+		errStr := wski18n.T("Unable to obtain the list of triggers for namespace '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": getClientNamespace(), "err": err})
+		werr := whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		return nil, werr
+	}
+
+	return triggers, nil
+}
+
 func configureFeed(triggerName string, feedName string, parameters interface{}) error {
 	var fullFeedName *QualifiedName
 	var err error
@@ -399,6 +434,11 @@ func init() {
 	triggerCreateCmd.Flags().StringVarP(&Flags.common.feed, "feed", "f", "", wski18n.T("trigger feed `ACTION_NAME`"))
 	triggerCreateCmd.Flags().StringSliceVarP(&Flags.trigger.feedParam, "feed-param", "F", []string{}, wski18n.T("feed parameter values in `KEY VALUE` format"))
 	triggerCreateCmd.Flags().StringSliceVarP(&Flags.trigger.triggerParam, "trigger-param", "T", []string{}, wski18n.T("trigger parameter values in `KEY VALUE` format"))
+	triggerCreateCmd.Flags().StringVar(&Flags.trigger.schedule, "schedule", "", wski18n.T("fire the trigger on the five field cron `SCHEDULE`, e.g. '*/5 * * * *', using the alarm feed"))
+	triggerCreateCmd.Flags().StringVar(&Flags.trigger.every, "every", "", wski18n.T("fire the trigger every `INTERVAL`, e.g. 10m or 2h, using the alarm feed"))
+	triggerCreateCmd.Flags().StringVar(&Flags.trigger.timezone, "timezone", "", wski18n.T("evaluate the schedule in the IANA `TIMEZONE`, e.g. America/New_York; defaults to UTC"))
+	triggerCreateCmd.Flags().StringVar(&Flags.trigger.start, "start", "", wski18n.T("do not fire the scheduled trigger before `DATE`"))
+	triggerCreateCmd.Flags().StringVar(&Flags.trigger.stop, "stop", "", wski18n.T("stop firing the scheduled trigger at `DATE`"))
 
 	triggerUpdateCmd.Flags().StringSliceVarP(&Flags.common.annotation, "annotation", "a", []string{}, wski18n.T("annotation values in `KEY VALUE` format"))
 	triggerUpdateCmd.Flags().StringVarP(&Flags.common.annotFile, "annotation-file", "A", "", wski18n.T("`FILE` containing annotation values in JSON format"))
@@ -408,13 +448,24 @@ func init() {
 	triggerUpdateCmd.Flags().StringSliceVarP(&Flags.trigger.triggerParam, "trigger-param", "T", []string{}, wski18n.T("trigger parameter values in `KEY VALUE` format"))
 
 	triggerGetCmd.Flags().BoolVarP(&Flags.trigger.summary, "summary", "s", false, wski18n.T("summarize trigger details; parameters with prefix \"*\" are bound"))
+	triggerGetCmd.Flags().IntVar(&Flags.trigger.next, "next", 5, wski18n.T("show the next `COUNT` fire times of a scheduled trigger in the summary"))
+	triggerGetCmd.Flags().StringVar(&Flags.common.template, "template", "", wski18n.T("format the trigger with the Go `TEMPLATE`, e.g. {{.example}}",
+		map[string]interface{}{"example": "'{{.name}} {{field \"annotations[key=feed].value\"}}'"}))
 
 	triggerFireCmd.Flags().StringSliceVarP(&Flags.common.param, "param", "p", []string{}, wski18n.T("parameter values in `KEY VALUE` format"))
 	triggerFireCmd.Flags().StringVarP(&Flags.common.paramFile, "param-file", "P", "", wski18n.T("`FILE` containing parameter values in JSON format"))
+	triggerFireCmd.Flags().StringVar(&Flags.trigger.payloadFile, "payload-file", "", wski18n.T("`FILE` containing the event payload as a JSON object, or - for standard input"))
+	triggerFireCmd.Flags().StringVar(&Flags.trigger.batch, "batch", "", wski18n.T("fire one event for each JSON object line of the newline delimited `FILE`, or - for standard input"))
+	triggerFireCmd.Flags().Float64Var(&Flags.trigger.rate, "rate", 0, wski18n.T("fire at most `EVENTS` per second"))
+	triggerFireCmd.Flags().BoolVar(&Flags.trigger.wait, "wait", false, wski18n.T("wait for the actions invoked by the rules of the trigger to complete and summarize their activations"))
+	triggerFireCmd.Flags().IntVar(&Flags.trigger.waitTimeout, "wait-timeout", TRIGGER_WAIT_TIMEOUT, wski18n.T("stop waiting for activations after `SECONDS` seconds"))
 
 	triggerListCmd.Flags().IntVarP(&Flags.common.skip, "skip", "s", 0, wski18n.T("exclude the first `SKIP` number of triggers from the result"))
 	triggerListCmd.Flags().IntVarP(&Flags.common.limit, "limit", "l", 30, wski18n.T("only return `LIMIT` number of triggers from the collection"))
 	triggerListCmd.Flags().BoolVarP(&Flags.common.nameSort, "name-sort", "n", false, wski18n.T("sorts a list alphabetically by entity name; only applicable within the limit/skip returned entity block"))
+	triggerListCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("page through all triggers in the collection instead of a single LIMIT block; --name-sort then applies to the complete list"))
+	triggerListCmd.Flags().StringArrayVar(&Flags.common.filter, "filter", []string{}, wski18n.T("only list triggers matching the FIELD OPERATOR VALUE `EXPRESSION`, e.g. 'updated<7d'; may be repeated"))
+	triggerListCmd.Flags().StringVar(&Flags.common.sortBy, "sort-by", "", wski18n.T("sort the list by `FIELD`, ascending or, with FIELD:desc, descending"))
 
 	triggerCmd.AddCommand(
 		triggerFireCmd,
@@ -423,6 +474,7 @@ func init() {
 		triggerGetCmd,
 		triggerDeleteCmd,
 		triggerListCmd,
+		triggerFeedCmd,
 	)
 }
 
@@ -435,6 +487,10 @@ func (t *Trigger) Create(Client *whisk.Client, args []string) error {
 		return whiskErr
 	}
 
+	if err := applyTriggerSchedule(); err != nil {
+		return err
+	}
+
 	//1. if the command line arguments user provides contains only --param flags
 	//2. if the command line arguments user provides contains no --param flags at all
 	//we should process the trigger create command in the old way.
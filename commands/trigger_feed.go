@@ -0,0 +1,271 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Registration states reported by `wsk trigger feed status`
+const FEED_STATUS_ACTIVE = "active"
+const FEED_STATUS_INACTIVE = "inactive"
+const FEED_STATUS_UNREGISTERED = "not registered"
+
+// triggerFeedState is what the feed action reports about a trigger in response to a READ
+type triggerFeedState struct {
+	feed   *QualifiedName
+	status string
+	reason string
+	result interface{}
+}
+
+var triggerFeedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: wski18n.T("inspect and repair the feed registration of a trigger"),
+}
+
+var triggerFeedStatusCmd = &cobra.Command{
+	Use:           "status TRIGGER_NAME",
+	Short:         wski18n.T("show the configuration and status a feed reports for a trigger"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 1, 1, "Trigger feed status", wski18n.T("A trigger name is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		qualifiedName, retTrigger, err := getFeedTrigger(args[0])
+		if err != nil {
+			return err
+		}
+
+		state, err := readTriggerFeed(qualifiedName, retTrigger)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(color.Output, wski18n.T("{{.ok}} got feed status for trigger {{.name}}\n",
+			map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName())}))
+		printTriggerFeedState(retTrigger, state)
+
+		return nil
+	},
+}
+
+var triggerFeedReconcileCmd = &cobra.Command{
+	Use:           "reconcile TRIGGER_NAME",
+	Short:         wski18n.T("register a trigger with its feed again if the feed has lost it, or update the registration"),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PreRunE:       SetupClientConfig,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if whiskErr := CheckArgs(args, 1, 1, "Trigger feed reconcile", wski18n.T("A trigger name is required.")); whiskErr != nil {
+			return whiskErr
+		}
+
+		qualifiedName, retTrigger, err := getFeedTrigger(args[0])
+		if err != nil {
+			return err
+		}
+
+		state, err := readTriggerFeed(qualifiedName, retTrigger)
+		if err != nil {
+			return err
+		}
+
+		var lifecycle string
+		switch {
+		case state.status == FEED_STATUS_UNREGISTERED:
+			// Without the schedule of trigger create --schedule or --param values, the feed would be
+			// registered again with no configuration at all
+			if len(Flags.common.param) == 0 && retTrigger.Annotations.GetValue(SCHEDULE_ANNOTATION) == nil {
+				errStr := wski18n.T("The feed no longer knows trigger '{{.name}}'. Pass --param with the feed configuration to register it again.",
+					map[string]interface{}{"name": qualifiedName.GetEntityName()})
+				return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+			}
+			lifecycle = FEED_CREATE
+		case len(Flags.common.param) > 0:
+			lifecycle = FEED_UPDATE
+		default:
+			fmt.Fprintf(color.Output, wski18n.T("{{.ok}} feed registration of trigger {{.name}} is {{.status}}; nothing to reconcile\n",
+				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName()),
+					"status": state.status}))
+			if state.status == FEED_STATUS_INACTIVE {
+				fmt.Fprintf(color.Output, wski18n.T("The feed reports the trigger as inactive: {{.reason}}\nPass --param to update the registration.\n",
+					map[string]interface{}{"reason": state.reason}))
+			}
+			return nil
+		}
+
+		_, params := feedParameters(state.feed.GetFullQualifiedName(), lifecycle, qualifiedName, Client.Config.AuthToken)
+		params = append(reconcileFeedParameters(retTrigger, lifecycle), params...)
+
+		res, err := invokeAction(*state.feed, getParameters(params, false, false), true, false)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "%s of feed '%s' failed: %s\n", lifecycle, state.feed.GetFullQualifiedName(), err)
+			printFailedBlockingInvocationResponse(*state.feed, false, res, err)
+			reason := wski18n.T(FEED_CONFIGURATION_FAILURE,
+				map[string]interface{}{"feedname": state.feed.GetFullQualifiedName(), "err": err})
+			errStr := wski18n.T("Unable to reconcile the feed of trigger '{{.name}}': {{.err}}",
+				map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": reason})
+			return whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+
+		if lifecycle == FEED_CREATE {
+			fmt.Fprintf(color.Output, wski18n.T("{{.ok}} registered trigger {{.name}} with feed {{.feed}} again\n",
+				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName()),
+					"feed": boldString(state.feed.GetFullQualifiedName())}))
+		} else {
+			fmt.Fprintf(color.Output, wski18n.T("{{.ok}} updated the registration of trigger {{.name}} with feed {{.feed}}\n",
+				map[string]interface{}{"ok": color.GreenString("ok:"), "name": boldString(qualifiedName.GetEntityName()),
+					"feed": boldString(state.feed.GetFullQualifiedName())}))
+		}
+
+		return nil
+	},
+}
+
+// getFeedTrigger fetches a trigger and fails unless it has a feed annotation
+func getFeedTrigger(name string) (*QualifiedName, *whisk.Trigger, error) {
+	qualifiedName, err := NewQualifiedName(name)
+	if err != nil {
+		return nil, nil, NewQualifiedNameError(name, err)
+	}
+
+	Client.Namespace = qualifiedName.GetNamespace()
+	retTrigger, _, err := Client.Triggers.Get(qualifiedName.GetEntityName())
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Triggers.Get(%s) failed: %s\n", qualifiedName.GetEntityName(), err)
+		errStr := wski18n.T("Unable to get trigger '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": err})
+		return nil, nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	if len(getValueString(retTrigger.Annotations, "feed")) == 0 {
+		errStr := wski18n.T("Trigger '{{.name}}' does not have a feed.", map[string]interface{}{"name": qualifiedName.GetEntityName()})
+		return nil, nil, whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	return qualifiedName, retTrigger, nil
+}
+
+// readTriggerFeed invokes the feed action of the trigger with a READ lifecycle event. An application
+// error with a 404 status means that the feed no longer knows the trigger; any other failure is returned.
+func readTriggerFeed(qualifiedName *QualifiedName, retTrigger *whisk.Trigger) (*triggerFeedState, error) {
+	feedName := getValueString(retTrigger.Annotations, "feed")
+	feedQualifiedName, params := feedParameters(feedName, FEED_READ, qualifiedName, Client.Config.AuthToken)
+	state := &triggerFeedState{feed: feedQualifiedName}
+
+	res, err := invokeAction(*feedQualifiedName, getParameters(params, false, false), true, true)
+	if err != nil {
+		if !isApplicationError(err) || !feedNotFound(res) {
+			whisk.Debug(whisk.DbgError, "READ of feed '%s' failed: %s\n", feedName, err)
+			errStr := wski18n.T("Unable to read the feed of trigger '{{.name}}': {{.err}}",
+				map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": err})
+			return nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+
+		whisk.Debug(whisk.DbgInfo, "Feed '%s' does not know trigger '%s': %s\n", feedName, qualifiedName.GetEntityName(), err)
+		state.status = FEED_STATUS_UNREGISTERED
+		state.reason = err.Error()
+		return state, nil
+	}
+
+	state.result = res
+	state.status, state.reason = feedStatus(res)
+	return state, nil
+}
+
+// feedNotFound reports whether the error result of a feed READ carries a 404 status, as the alarm and
+// cloudant feeds return for a trigger they do not know, e.g. {"error": {"statusCode": 404, "body": "..."}}
+func feedNotFound(result interface{}) bool {
+	res, _ := result.(map[string]interface{})
+	feedErr, _ := res["error"].(map[string]interface{})
+
+	// The result is decoded with json.Number values
+	return fmt.Sprint(feedErr["statusCode"]) == strconv.Itoa(http.StatusNotFound)
+}
+
+// feedStatus interprets the "status" the feed includes in its READ result, as the alarm, message hub
+// and cloudant feeds do, e.g. {"config": {...}, "status": {"active": false, "reason": {"message": "..."}}}
+func feedStatus(result interface{}) (string, string) {
+	res, _ := result.(map[string]interface{})
+	status, _ := res["status"].(map[string]interface{})
+
+	if active, ok := status["active"].(bool); ok && !active {
+		var reason string
+		switch value := status["reason"].(type) {
+		case string:
+			reason = value
+		case map[string]interface{}:
+			reason, _ = value["message"].(string)
+		}
+		return FEED_STATUS_INACTIVE, reason
+	}
+
+	return FEED_STATUS_ACTIVE, ""
+}
+
+// reconcileFeedParameters returns the parameters to configure the feed with: a CREATE starts from the
+// schedule recorded by trigger create --schedule, and --param values apply on top
+func reconcileFeedParameters(retTrigger *whisk.Trigger, lifecycle string) []string {
+	var params []string
+
+	if schedule, ok := retTrigger.Annotations.GetValue(SCHEDULE_ANNOTATION).(map[string]interface{}); ok && lifecycle == FEED_CREATE {
+		for _, key := range []string{SCHEDULE_CRON, SCHEDULE_TIMEZONE, SCHEDULE_START, SCHEDULE_STOP} {
+			if value, ok := schedule[key].(string); ok {
+				params = append(params, getFormattedJSON(key, value))
+			}
+		}
+	}
+
+	return append(params, Flags.common.param...)
+}
+
+func printTriggerFeedState(retTrigger *whisk.Trigger, state *triggerFeedState) {
+	fmt.Fprintf(color.Output, "%s %s\n", boldString(fmt.Sprintf("%7s", "trigger")),
+		getFullName(retTrigger.Namespace, "", retTrigger.Name))
+	fmt.Fprintf(color.Output, "   (%s: %s)\n", boldString(wski18n.T("feed")), state.feed.GetFullQualifiedName())
+	fmt.Fprintf(color.Output, "   (%s: %s)\n", boldString(wski18n.T("status")), state.status)
+	if len(state.reason) > 0 {
+		fmt.Fprintf(color.Output, "   (%s: %s)\n", boldString(wski18n.T("reason")), state.reason)
+	}
+	if state.result != nil {
+		printJSON(state.result)
+	}
+}
+
+func init() {
+	triggerFeedReconcileCmd.Flags().StringSliceVarP(&Flags.common.param, "param", "p", []string{}, wski18n.T("feed parameter values in `KEY VALUE` format"))
+	triggerFeedReconcileCmd.Flags().StringVarP(&Flags.common.paramFile, "param-file", "P", "", wski18n.T("`FILE` containing feed parameter values in JSON format"))
+
+	triggerFeedCmd.AddCommand(
+		triggerFeedStatusCmd,
+		triggerFeedReconcileCmd,
+	)
+}
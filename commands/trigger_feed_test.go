@@ -0,0 +1,99 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/apache/openwhisk-client-go/whisk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedStatus(t *testing.T) {
+	status, reason := feedStatus(map[string]interface{}{
+		"config": map[string]interface{}{"cron": "*/5 * * * *"},
+		"status": map[string]interface{}{"active": true},
+	})
+	assert.Equal(t, FEED_STATUS_ACTIVE, status)
+	assert.Equal(t, "", reason)
+
+	status, reason = feedStatus(map[string]interface{}{
+		"status": map[string]interface{}{"active": false, "reason": map[string]interface{}{"kind": "AUTO", "message": "Auth key revoked"}},
+	})
+	assert.Equal(t, FEED_STATUS_INACTIVE, status)
+	assert.Equal(t, "Auth key revoked", reason)
+
+	status, reason = feedStatus(map[string]interface{}{"status": map[string]interface{}{"active": false, "reason": "disabled"}})
+	assert.Equal(t, FEED_STATUS_INACTIVE, status)
+	assert.Equal(t, "disabled", reason)
+
+	status, _ = feedStatus("configured")
+	assert.Equal(t, FEED_STATUS_ACTIVE, status)
+}
+
+func TestReconcileFeedParameters(t *testing.T) {
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+
+	retTrigger := &whisk.Trigger{
+		Name: "every-five",
+		Annotations: whisk.KeyValueArr{
+			{Key: "feed", Value: DefaultAlarmFeed},
+			{Key: SCHEDULE_ANNOTATION, Value: map[string]interface{}{"cron": "*/5 * * * *", "timezone": "UTC"}},
+		},
+	}
+	Flags.common.param = []string{`{"trigger_payload": {"name": "five"}}`}
+
+	assert.Equal(t, []string{
+		`{"cron": "*/5 * * * *"}`,
+		`{"timezone": "UTC"}`,
+		`{"trigger_payload": {"name": "five"}}`,
+	}, reconcileFeedParameters(retTrigger, FEED_CREATE))
+	assert.Equal(t, []string{`{"trigger_payload": {"name": "five"}}`}, reconcileFeedParameters(retTrigger, FEED_UPDATE))
+
+	Flags.common.param = nil
+	assert.Empty(t, reconcileFeedParameters(&whisk.Trigger{}, FEED_CREATE))
+}
+
+func TestReadTriggerFeed(t *testing.T) {
+	var result string
+	newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(result))
+	})
+
+	qualifiedName, _ := NewQualifiedName("every-five")
+	retTrigger := &whisk.Trigger{Name: "every-five", Annotations: whisk.KeyValueArr{{Key: "feed", Value: DefaultAlarmFeed}}}
+
+	result = `{"error": {"statusCode": 404, "body": "could not find trigger /guest/every-five in the database"}}`
+	state, err := readTriggerFeed(qualifiedName, retTrigger)
+	assert.Nil(t, err)
+	assert.Equal(t, FEED_STATUS_UNREGISTERED, state.status)
+
+	// Any other application error leaves the registration unknown
+	result = `{"error": {"statusCode": 500, "body": "database unavailable"}}`
+	_, err = readTriggerFeed(qualifiedName, retTrigger)
+	assert.NotNil(t, err)
+
+	result = `{"error": "feed is busy"}`
+	_, err = readTriggerFeed(qualifiedName, retTrigger)
+	assert.NotNil(t, err)
+}
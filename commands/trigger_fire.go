@@ -0,0 +1,402 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apache/openwhisk-cli/wski18n"
+	"github.com/apache/openwhisk-client-go/whisk"
+
+	"github.com/fatih/color"
+)
+
+// STDIN_SOURCE names standard input wherever a payload or batch file is expected
+const STDIN_SOURCE = "-"
+
+// Default for --wait-timeout, in seconds
+const TRIGGER_WAIT_TIMEOUT = 60
+
+// Results are cut to this many characters in the --wait summary
+const TRIGGER_RESULT_SNIPPET_LENGTH = 60
+
+// Events are limited to the 1 MB the controller accepts for a trigger payload
+const TRIGGER_BATCH_MAX_LINE = 1024 * 1024
+
+// Activation records appear some time after the fact, so waiting polls for them
+var triggerWaitPollInterval = time.Second
+
+// triggerEvent is one payload to fire the trigger with; line is its position in a batch file
+type triggerEvent struct {
+	line    int
+	payload map[string]interface{}
+}
+
+// triggerRuleActivation is one entry of a trigger activation's logs, which record for each
+// active rule the activation of the action that the rule invoked
+type triggerRuleActivation struct {
+	StatusCode   int    `json:"statusCode"`
+	Success      bool   `json:"success"`
+	ActivationId string `json:"activationId"`
+	Rule         string `json:"rule"`
+	Action       string `json:"action"`
+	Error        string `json:"error"`
+
+	activation *whisk.Activation
+	err        error
+}
+
+// succeeded is true when the rule invoked its action and the action completed successfully
+func (rule *triggerRuleActivation) succeeded() bool {
+	return rule.activation != nil && rule.activation.Response.Success
+}
+
+func (rule *triggerRuleActivation) status() string {
+	switch {
+	case rule.activation != nil:
+		return rule.activation.Response.Status
+	case rule.err != nil:
+		return wski18n.T("timed out")
+	default:
+		return wski18n.T("not invoked")
+	}
+}
+
+func (rule *triggerRuleActivation) duration() string {
+	if rule.activation == nil {
+		return "-"
+	}
+	return (time.Duration(rule.activation.Duration) * time.Millisecond).String()
+}
+
+func (rule *triggerRuleActivation) result() string {
+	var snippet string
+
+	switch {
+	case rule.activation != nil:
+		content, _ := json.Marshal(rule.activation.Response.Result)
+		snippet = string(content)
+	case rule.err != nil:
+		snippet = rule.err.Error()
+	default:
+		snippet = rule.Error
+	}
+
+	return truncateSnippet(snippet, TRIGGER_RESULT_SNIPPET_LENGTH)
+}
+
+func readPayloadSource(source string) ([]byte, error) {
+	if source == STDIN_SOURCE {
+		content, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			whisk.Debug(whisk.DbgError, "ioutil.ReadAll(os.Stdin) failed: %s\n", err)
+			errMsg := wski18n.T("Unable to read the payload from standard input: {{.err}}", map[string]interface{}{"err": err})
+			return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+		return content, nil
+	}
+
+	content, err := ReadFile(source)
+	return []byte(content), err
+}
+
+// parseTriggerPayload decodes a payload, which must be a JSON object since it becomes the event parameters
+func parseTriggerPayload(content []byte, origin string) (map[string]interface{}, error) {
+	var payload map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil || payload == nil {
+		whisk.Debug(whisk.DbgError, "Decoding the payload from %s failed: %v\n", origin, err)
+		errMsg := wski18n.T("The payload from {{.origin}} is not a JSON object", map[string]interface{}{"origin": origin})
+		return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	return payload, nil
+}
+
+// mergeTriggerParameters applies the --param values on top of a payload
+func mergeTriggerParameters(payload map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for key, value := range payload {
+		merged[key] = value
+	}
+	for key, value := range getParameters(Flags.common.param, false, false).(map[string]interface{}) {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// triggerFireEvents collects the events to fire from the PAYLOAD argument, --payload-file or --batch
+func triggerFireEvents(args []string) ([]triggerEvent, error) {
+	var sources []string
+	if len(args) > 1 {
+		sources = append(sources, "PAYLOAD")
+	}
+	if len(Flags.trigger.payloadFile) > 0 {
+		sources = append(sources, "--payload-file")
+	}
+	if len(Flags.trigger.batch) > 0 {
+		sources = append(sources, "--batch")
+	}
+	if len(sources) > 1 {
+		errMsg := wski18n.T("Only one of the PAYLOAD argument, --payload-file and --batch may be given, not {{.sources}}.",
+			map[string]interface{}{"sources": strings.Join(sources, " and ")})
+		return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+	}
+
+	var err error
+	var content []byte
+	payload := make(map[string]interface{})
+
+	switch {
+	case len(Flags.trigger.batch) > 0:
+		if content, err = readPayloadSource(Flags.trigger.batch); err != nil {
+			return nil, err
+		}
+		return readTriggerBatch(bytes.NewReader(content), Flags.trigger.batch)
+	case len(Flags.trigger.payloadFile) > 0:
+		if content, err = readPayloadSource(Flags.trigger.payloadFile); err != nil {
+			return nil, err
+		}
+		payload, err = parseTriggerPayload(content, Flags.trigger.payloadFile)
+	case len(args) > 1 && args[1] == STDIN_SOURCE:
+		if content, err = readPayloadSource(STDIN_SOURCE); err != nil {
+			return nil, err
+		}
+		payload, err = parseTriggerPayload(content, wski18n.T("standard input"))
+	case len(args) > 1:
+		payload, err = parseTriggerPayload([]byte(args[1]), wski18n.T("the PAYLOAD argument"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []triggerEvent{{payload: mergeTriggerParameters(payload)}}, nil
+}
+
+// readTriggerBatch reads newline delimited JSON, one event per line. Every line is checked before
+// any event is fired so that a malformed file does not fire half of its events.
+func readTriggerBatch(reader io.Reader, name string) ([]triggerEvent, error) {
+	var events []triggerEvent
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), TRIGGER_BATCH_MAX_LINE)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 {
+			continue
+		}
+
+		payload, err := parseTriggerPayload([]byte(text), fmt.Sprintf("%s:%d", name, line))
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, triggerEvent{line: line, payload: mergeTriggerParameters(payload)})
+	}
+	if err := scanner.Err(); err != nil {
+		whisk.Debug(whisk.DbgError, "Reading the batch file '%s' failed: %s\n", name, err)
+		errMsg := wski18n.T("Unable to read the file '{{.name}}': {{.err}}", map[string]interface{}{"name": name, "err": err})
+		return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	return events, nil
+}
+
+func fireTrigger(qualifiedName *QualifiedName, payload map[string]interface{}) (string, error) {
+	// TODO get rid of these global modifiers
+	Client.Namespace = qualifiedName.GetNamespace()
+	trigResp, _, err := Client.Triggers.Fire(qualifiedName.GetEntityName(), payload)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "Client.Triggers.Fire(%s, %#v) failed: %s\n", qualifiedName.GetEntityName(), payload, err)
+		errStr := wski18n.T("Unable to fire trigger '{{.name}}': {{.err}}",
+			map[string]interface{}{"name": qualifiedName.GetEntityName(), "err": err})
+		return "", whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_ERR_GENERAL,
+			whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	return trigResp.ActivationId, nil
+}
+
+// pollActivation gets an activation, retrying until it has been recorded or the deadline passes
+func pollActivation(id string, deadline time.Time) (*whisk.Activation, error) {
+	for {
+		activation, _, err := Client.Activations.Get(id)
+		if err == nil {
+			return activation, nil
+		}
+		if !time.Now().Add(triggerWaitPollInterval).Before(deadline) {
+			whisk.Debug(whisk.DbgError, "Client.Activations.Get(%s) failed: %s\n", id, err)
+			errStr := wski18n.T("Timed out waiting for activation {{.id}}: {{.err}}", map[string]interface{}{"id": id, "err": err})
+			return nil, whisk.MakeWskErrorFromWskError(errors.New(errStr), err, whisk.EXIT_CODE_TIMED_OUT, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+		}
+		time.Sleep(triggerWaitPollInterval)
+	}
+}
+
+// waitForTriggerActivations follows a trigger activation to the action activations of its rules and
+// waits until all of them have completed. An action that does not complete in time is recorded on its
+// rule; only failing to find the trigger activation itself is an error.
+func waitForTriggerActivations(activationId string, timeout time.Duration) ([]*triggerRuleActivation, error) {
+	deadline := time.Now().Add(timeout)
+
+	triggerActivation, err := pollActivation(activationId, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*triggerRuleActivation
+	for _, log := range triggerActivation.Logs {
+		rule := new(triggerRuleActivation)
+		if err := json.Unmarshal([]byte(log), rule); err != nil {
+			whisk.Debug(whisk.DbgWarn, "Ignoring trigger activation log '%s': %s\n", log, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	for _, rule := range rules {
+		if !rule.Success || len(rule.ActivationId) == 0 {
+			continue
+		}
+		rule.activation, rule.err = pollActivation(rule.ActivationId, deadline)
+	}
+
+	return rules, nil
+}
+
+// printTriggerRuleSummary prints one row per rule with the outcome of the action it invoked
+func printTriggerRuleSummary(rules []*triggerRuleActivation) {
+	if len(rules) == 0 {
+		fmt.Fprintf(color.Output, wski18n.T("no active rules were triggered\n"))
+		return
+	}
+
+	ruleWidth, actionWidth, idWidth, statusWidth := len("rule"), len("action"), len("activation"), len("status")
+	for _, rule := range rules {
+		ruleWidth = max(ruleWidth, len(rule.Rule))
+		actionWidth = max(actionWidth, len(rule.Action))
+		idWidth = max(idWidth, len(rule.ActivationId))
+		statusWidth = max(statusWidth, len(rule.status()))
+	}
+
+	rowFmt := fmt.Sprintf("%%-%ds  %%-%ds  %%-%ds  %%-%ds  %%-8s  %%s\n", ruleWidth, actionWidth, idWidth, statusWidth)
+	fmt.Fprint(color.Output, boldString(fmt.Sprintf(rowFmt, "rule", "action", "activation", "status", "duration", "result")))
+	for _, rule := range rules {
+		id := rule.ActivationId
+		if len(id) == 0 {
+			id = "-"
+		}
+		status := rule.status()
+		if rule.succeeded() {
+			status = color.GreenString("%-*s", statusWidth, status)
+		} else {
+			status = color.RedString("%-*s", statusWidth, status)
+		}
+		fmt.Fprintf(color.Output, rowFmt, rule.Rule, rule.Action, id, status, rule.duration(), rule.result())
+	}
+}
+
+// fireTriggerEvents fires each event, spacing them out to --rate events per second, and reports
+// the failures together at the end so that one bad event does not stop a batch
+func fireTriggerEvents(qualifiedName *QualifiedName, events []triggerEvent) error {
+	var interval time.Duration
+	if Flags.trigger.rate > 0 {
+		interval = time.Duration(float64(time.Second) / Flags.trigger.rate)
+	}
+
+	failed := 0
+	var last time.Time
+	for i, event := range events {
+		if i > 0 && interval > 0 {
+			time.Sleep(time.Until(last.Add(interval)))
+		}
+		last = time.Now()
+
+		id, err := fireTrigger(qualifiedName, event.payload)
+		if err != nil {
+			if len(events) == 1 {
+				return err
+			}
+			failed++
+			fmt.Fprintf(color.Output, "%s %s\n", color.RedString("error:"),
+				wski18n.T("line {{.line}}: {{.err}}", map[string]interface{}{"line": event.line, "err": err}))
+			continue
+		}
+
+		fmt.Fprintf(color.Output,
+			wski18n.T("{{.ok}} triggered /{{.namespace}}/{{.name}} with id {{.id}}\n",
+				map[string]interface{}{
+					"ok":        color.GreenString("ok:"),
+					"namespace": boldString(qualifiedName.GetNamespace()),
+					"name":      boldString(qualifiedName.GetEntityName()),
+					"id":        boldString(id)}))
+
+		if Flags.trigger.wait && len(id) > 0 {
+			if err = waitForTriggerEvent(id); err != nil {
+				if len(events) == 1 {
+					return err
+				}
+				failed++
+				fmt.Fprintf(color.Output, "%s %s\n", color.RedString("error:"),
+					wski18n.T("line {{.line}}: {{.err}}", map[string]interface{}{"line": event.line, "err": err}))
+			}
+		}
+	}
+
+	if failed > 0 {
+		errStr := wski18n.T("{{.failed}} of {{.total}} trigger events failed", map[string]interface{}{"failed": failed, "total": len(events)})
+		return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	return nil
+}
+
+// waitForTriggerEvent waits for the rules that a trigger activation fired, prints their summary and
+// fails unless every rule invoked its action and the action succeeded
+func waitForTriggerEvent(id string) error {
+	rules, err := waitForTriggerActivations(id, time.Duration(Flags.trigger.waitTimeout)*time.Second)
+	if err != nil {
+		return err
+	}
+	printTriggerRuleSummary(rules)
+
+	failed := 0
+	for _, rule := range rules {
+		if !rule.succeeded() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		errStr := wski18n.T("{{.failed}} of {{.total}} rules did not complete successfully",
+			map[string]interface{}{"failed": failed, "total": len(rules)})
+		return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+	}
+
+	return nil
+}
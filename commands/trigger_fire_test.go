@@ -0,0 +1,184 @@
+// +build unit
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTriggerTestClient points the global Client at a stand-in controller and polls for the activations
+// of fired triggers without waiting, for the duration of a test
+func newTriggerTestClient(t *testing.T, handler http.HandlerFunc) {
+	newTestClient(t, handler)
+	savedInterval := triggerWaitPollInterval
+	t.Cleanup(func() { triggerWaitPollInterval = savedInterval })
+	triggerWaitPollInterval = time.Millisecond
+}
+
+func TestTriggerFireEvents(t *testing.T) {
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+
+	Flags.common.param = []string{`{"source": "cli"}`}
+	events, err := triggerFireEvents([]string{"t", `{"name": "Dune", "source": "payload"}`})
+	assert.Nil(t, err)
+	assert.Equal(t, []triggerEvent{{payload: map[string]interface{}{"name": "Dune", "source": "cli"}}}, events)
+
+	_, err = triggerFireEvents([]string{"t", `["Dune"]`})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "The payload from the PAYLOAD argument is not a JSON object")
+
+	Flags.trigger.payloadFile = writeApiTestFile(t, "payload.json", `{"name": "Emma"}`)
+	events, err = triggerFireEvents([]string{"t"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Emma", events[0].payload["name"])
+
+	_, err = triggerFireEvents([]string{"t", `{}`})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "not PAYLOAD and --payload-file")
+
+	Flags.trigger.payloadFile = ""
+	Flags.common.param = nil
+	events, err = triggerFireEvents([]string{"t"})
+	assert.Nil(t, err)
+	assert.Equal(t, []triggerEvent{{payload: map[string]interface{}{}}}, events)
+}
+
+func TestReadTriggerBatch(t *testing.T) {
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+	Flags.common.param = []string{`{"source": "batch"}`}
+
+	events, err := readTriggerBatch(strings.NewReader("{\"n\": 1}\n\n  {\"n\": 2}  \n"), "events.ndjson")
+	assert.Nil(t, err)
+	assert.Equal(t, []triggerEvent{
+		{line: 1, payload: map[string]interface{}{"n": json.Number("1"), "source": "batch"}},
+		{line: 3, payload: map[string]interface{}{"n": json.Number("2"), "source": "batch"}},
+	}, events)
+
+	_, err = readTriggerBatch(strings.NewReader("{\"n\": 1}\n{\"n\": \n"), "events.ndjson")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "events.ndjson:2")
+}
+
+func TestWaitForTriggerActivations(t *testing.T) {
+	lookups := 0
+	newTriggerTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/activations/trigger-1"):
+			lookups++
+			if lookups < 3 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error": "The requested resource does not exist."}`))
+				return
+			}
+			w.Write([]byte(`{"activationId": "trigger-1", "logs": [
+				"{\"statusCode\":0,\"success\":true,\"activationId\":\"action-1\",\"rule\":\"guest/r1\",\"action\":\"guest/a1\"}",
+				"{\"statusCode\":1,\"success\":false,\"rule\":\"guest/r2\",\"action\":\"guest/a2\",\"error\":\"The requested resource does not exist.\"}",
+				"{\"statusCode\":0,\"success\":true,\"activationId\":\"action-3\",\"rule\":\"guest/r3\",\"action\":\"guest/a3\"}"
+			]}`))
+		case strings.HasSuffix(r.URL.Path, "/activations/action-1"):
+			w.Write([]byte(`{"activationId": "action-1", "duration": 12, "response": {"status": "success", "success": true, "result": {"ok": true}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "The requested resource does not exist."}`))
+		}
+	})
+
+	rules, err := waitForTriggerActivations("trigger-1", 50*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, lookups)
+	assert.Equal(t, 3, len(rules))
+
+	assert.Equal(t, "guest/r1", rules[0].Rule)
+	assert.True(t, rules[0].succeeded())
+	assert.Equal(t, "success", rules[0].status())
+	assert.Equal(t, "12ms", rules[0].duration())
+	assert.Equal(t, `{"ok":true}`, rules[0].result())
+
+	assert.False(t, rules[1].succeeded())
+	assert.Equal(t, "not invoked", rules[1].status())
+	assert.Equal(t, "-", rules[1].duration())
+	assert.Equal(t, "The requested resource does not exist.", rules[1].result())
+
+	notInvoked := *rules[1]
+	notInvoked.Error = strings.Repeat("é", TRIGGER_RESULT_SNIPPET_LENGTH)
+	assert.Equal(t, strings.Repeat("é", TRIGGER_RESULT_SNIPPET_LENGTH/2)+"...", notInvoked.result())
+
+	assert.False(t, rules[2].succeeded())
+	assert.Equal(t, "timed out", rules[2].status())
+	assert.Contains(t, rules[2].result(), "Timed out waiting for activation action-3")
+
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+	Flags.trigger.waitTimeout = 1
+	err = waitForTriggerEvent("trigger-1")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "2 of 3 rules did not complete successfully")
+
+	_, err = waitForTriggerActivations("trigger-2", 10*time.Millisecond)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Timed out waiting for activation trigger-2")
+}
+
+func TestFireTriggerEvents(t *testing.T) {
+	var fired []string
+	newTriggerTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		fired = append(fired, payload["n"].(string))
+		w.Header().Set("Content-Type", "application/json")
+		if payload["n"] == "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "bad event"}`))
+			return
+		}
+		w.Write([]byte(`{"activationId": "id-` + payload["n"].(string) + `"}`))
+	})
+
+	saved := Flags
+	t.Cleanup(func() { Flags = saved })
+	Flags.trigger.rate = 100
+
+	qualifiedName, _ := NewQualifiedName("t")
+	events := []triggerEvent{
+		{line: 1, payload: map[string]interface{}{"n": "1"}},
+		{line: 2, payload: map[string]interface{}{"n": "2"}},
+		{line: 3, payload: map[string]interface{}{"n": "3"}},
+	}
+
+	start := time.Now()
+	err := fireTriggerEvents(qualifiedName, events)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+	assert.Equal(t, []string{"1", "2", "3"}, fired)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "1 of 3 trigger events failed")
+
+	err = fireTriggerEvents(qualifiedName, events[1:2])
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Unable to fire trigger 't'")
+}
@@ -41,6 +41,8 @@ import (
 	"reflect"
 	"regexp"
 	"sort"
+	"text/template"
+	"unicode/utf8"
 )
 
 func csvToQualifiedActions(artifacts string) []string {
@@ -172,12 +174,6 @@ func isValidJSON(value string) bool {
 
 var boldString = color.New(color.Bold).SprintFunc()
 
-type Sortables []whisk.Sortable
-
-// Uses quickSort to sort commands based on their compare methods
-// Param: Takes in a array of Sortable interfaces which contains a specific command
-func Swap(sortables Sortables, i, j int) { sortables[i], sortables[j] = sortables[j], sortables[i] }
-
 func toPrintable(sortable []whisk.Sortable) []whisk.Printable {
 	sortedPrintable := make([]whisk.Printable, len(sortable), len(sortable))
 	for i := range sortable {
@@ -190,6 +186,30 @@ func toPrintable(sortable []whisk.Sortable) []whisk.Printable {
 // Identifies type and then copies array into an array of interfaces(Sortable) to be sorted and printed
 // Param: Takes in an interface which contains an array of a command(Ex: []Action)
 func printList(collection interface{}, sortByName bool) {
+	commandToSort := toSortables(collection)
+	if sortByName {
+		sort.SliceStable(commandToSort, func(i, j int) bool {
+			return commandToSort[i].Compare(commandToSort[j])
+		})
+	}
+	printCommandsList(toPrintable(commandToSort), makeDefaultHeader(collection))
+}
+
+// Prints one page of a list that is streamed to the console as it is retrieved (e.g. `--all`)
+// Param: Takes in an interface which contains an array of a command(Ex: []Action), and whether
+// the page is the first one; only the first page prints the header
+func printListPage(collection interface{}, firstPage bool) {
+	commands := toPrintable(toSortables(collection))
+	if firstPage {
+		printCommandsList(commands, makeDefaultHeader(collection))
+	} else {
+		for i := range commands {
+			fmt.Print(commands[i].ToSummaryRowString())
+		}
+	}
+}
+
+func toSortables(collection interface{}) []whisk.Sortable {
 	var commandToSort []whisk.Sortable
 	switch collection := collection.(type) {
 	case []whisk.Action:
@@ -225,35 +245,25 @@ func printList(collection interface{}, sortByName bool) {
 			commandToSort = append(commandToSort, collection[i])
 		}
 	}
-	if sortByName && len(commandToSort) > 0 {
-		quickSort(commandToSort, 0, len(commandToSort)-1)
-	}
-	printCommandsList(toPrintable(commandToSort), makeDefaultHeader(collection))
+	return commandToSort
 }
 
-func quickSort(toSort Sortables, left int, right int) {
-	low := left
-	high := right
-	pivot := toSort[(left+right)/2]
+// Maximum number of entities the server returns for a single list request
+const MAX_LIST_LIMIT = 200
 
-	for low <= high {
-		for toSort[low].Compare(pivot) {
-			low++
-		}
-		for pivot.Compare(toSort[high]) {
-			high--
+// listAllPages(skip, fetch) retrieves a collection page by page, starting at the
+// skip offset, until the server returns a page smaller than the maximum page size.
+// fetch returns the number of entities in the page it retrieved.
+func listAllPages(skip int, fetch func(skip int, limit int) (int, error)) error {
+	for {
+		count, err := fetch(skip, MAX_LIST_LIMIT)
+		if err != nil {
+			return err
 		}
-		if low <= high {
-			Swap(toSort, low, high)
-			low++
-			high--
+		if count < MAX_LIST_LIMIT {
+			return nil
 		}
-	}
-	if left < high {
-		quickSort(toSort, left, high)
-	}
-	if low < right {
-		quickSort(toSort, low, right)
+		skip += count
 	}
 }
 
@@ -1010,11 +1020,19 @@ func FileExists(file string) (bool, error) {
 	return true, nil
 }
 
+// fieldExists(value, field) checks that a field filter names a field of the entity; nested
+// paths like exec.kind or annotations[key=web-export].value are checked up to the top-level field
 func fieldExists(value interface{}, field string) bool {
+	segments, err := parseFieldPath(field)
+	if err != nil {
+		whisk.Debug(whisk.DbgError, "parseFieldPath(%s) error: %s\n", field, err)
+		return false
+	}
+
 	element := reflect.ValueOf(value).Elem()
 
 	for i := 0; i < element.NumField(); i++ {
-		if strings.ToLower(element.Type().Field(i).Name) == strings.ToLower(field) {
+		if strings.ToLower(element.Type().Field(i).Name) == strings.ToLower(segments[0].name) {
 			return true
 		}
 	}
@@ -1022,7 +1040,9 @@ func fieldExists(value interface{}, field string) bool {
 	return false
 }
 
-func printField(value interface{}, field string) {
+// printField(value, field) prints a field of an entity and reports whether the entity has it, so that a
+// mistyped nested path fails like an unknown field rather than displaying null
+func printField(value interface{}, field string) bool {
 	var matchFunc = func(structField string) bool {
 		return strings.ToLower(structField) == strings.ToLower(field)
 	}
@@ -1030,7 +1050,53 @@ func printField(value interface{}, field string) {
 	structValue := reflect.ValueOf(value)
 	fieldValue := reflect.Indirect(structValue).FieldByNameFunc(matchFunc)
 
-	printJSON(fieldValue.Interface())
+	if fieldValue.IsValid() {
+		printJSON(fieldValue.Interface())
+		return true
+	}
+
+	// Nested field paths are resolved on the JSON representation of the entity
+	nestedValue, found := getFieldValue(getEntityFields(value), field)
+	if !found {
+		return false
+	}
+	printJSON(nestedValue)
+	return true
+}
+
+// printTemplate(value, text) formats an entity with a Go template. The template is executed on the
+// JSON representation of the entity, e.g. {{.name}} or {{.exec.kind}}, and has two functions:
+// field, which resolves a field path like annotations[key=web-export].value, and json.
+func printTemplate(value interface{}, text string) error {
+	fields := getEntityFields(value)
+	funcs := template.FuncMap{
+		"field": func(path string) interface{} {
+			fieldValue, _ := getFieldValue(fields, path)
+			return fieldValue
+		},
+		"json": func(v interface{}) (string, error) {
+			output, err := json.MarshalIndent(v, "", "    ")
+			return string(output), err
+		},
+	}
+
+	tmpl, err := template.New("template").Funcs(funcs).Parse(text)
+	if err == nil {
+		var output bytes.Buffer
+		if err = tmpl.Execute(&output, fields); err == nil {
+			if !strings.HasSuffix(output.String(), "\n") {
+				output.WriteString("\n")
+			}
+			fmt.Fprint(color.Output, output.String())
+			return nil
+		}
+	}
+
+	whisk.Debug(whisk.DbgError, "template '%s' failed: %s\n", text, err)
+	errStr := wski18n.T("Unable to format output with template '{{.template}}': {{.err}}",
+		map[string]interface{}{"template": text, "err": err})
+	return whisk.MakeWskError(errors.New(errStr), whisk.EXIT_CODE_ERR_GENERAL, whisk.DISPLAY_MSG,
+		whisk.NO_DISPLAY_USAGE)
 }
 
 func parseShared(shared string) (bool, bool, error) {
@@ -1057,6 +1123,24 @@ func parseShared(shared string) (bool, bool, error) {
 	return isShared, isSet, nil
 }
 
+// printWarning prints a warning that does not stop the command to stderr
+func printWarning(message string) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", color.YellowString("warning:"), message)
+}
+
+// truncateSnippet(snippet, length) shortens a snippet to at most length bytes followed by "...",
+// cutting it on a character boundary so that no multi-byte character is split
+func truncateSnippet(snippet string, length int) string {
+	if len(snippet) <= length {
+		return snippet
+	}
+
+	for length > 0 && !utf8.RuneStart(snippet[length]) {
+		length--
+	}
+	return snippet[:length] + "..."
+}
+
 func max(a int, b int) int {
 	if a > b {
 		return a
@@ -1154,6 +1238,82 @@ func getSpaceGuid() (string, error) {
 	return "", werr
 }
 
+// diffJSON(original, current) compares two JSON values and returns one line per difference,
+// "- PATH: VALUE" for removed values, "+ PATH: VALUE" for added ones; changed values produce both
+func diffJSON(original interface{}, current interface{}) []string {
+	var diff []string
+	diffJSONValues(normalizeJSON(original), normalizeJSON(current), "", &diff)
+	return diff
+}
+
+func diffJSONValues(original interface{}, current interface{}, path string, diff *[]string) {
+	originalMap, originalIsMap := original.(map[string]interface{})
+	currentMap, currentIsMap := current.(map[string]interface{})
+
+	if originalIsMap && currentIsMap {
+		var keys []string
+		for key := range originalMap {
+			keys = append(keys, key)
+		}
+		for key := range currentMap {
+			if _, exists := originalMap[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			originalValue, inOriginal := originalMap[key]
+			currentValue, inCurrent := currentMap[key]
+			keyPath := path + "." + key
+
+			if !inCurrent {
+				*diff = append(*diff, fmt.Sprintf("- %s: %s", keyPath, compactJSON(originalValue)))
+			} else if !inOriginal {
+				*diff = append(*diff, fmt.Sprintf("+ %s: %s", keyPath, compactJSON(currentValue)))
+			} else {
+				diffJSONValues(originalValue, currentValue, keyPath, diff)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(original, current) {
+		if len(path) == 0 {
+			path = "."
+		}
+		*diff = append(*diff, fmt.Sprintf("- %s: %s", path, compactJSON(original)))
+		*diff = append(*diff, fmt.Sprintf("+ %s: %s", path, compactJSON(current)))
+	}
+}
+
+// normalizeJSON(value) round trips a value through JSON so that values decoded differently, e.g.
+// structs and maps or int and float64 numbers, compare equal
+func normalizeJSON(value interface{}) interface{} {
+	var normalized interface{}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err = decoder.Decode(&normalized); err != nil {
+		return value
+	}
+
+	return normalized
+}
+
+func compactJSON(value interface{}) string {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(content)
+}
+
 func isBlockingTimeout(err error) bool {
 	var blockingTimeout bool
 
@@ -20,6 +20,7 @@
 package commands
 
 import (
+	"github.com/apache/openwhisk-client-go/whisk"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -47,3 +48,55 @@ func TestStripTimestamp(t *testing.T) {
 		assert.Equal(stripTimestamp(log), expected)
 	}
 }
+
+func TestListAllPages(t *testing.T) {
+	assert := assert.New(t)
+	total := 2*MAX_LIST_LIMIT + 5
+	var skips []int
+
+	err := listAllPages(3, func(skip int, limit int) (int, error) {
+		skips = append(skips, skip)
+		return min(limit, total-skip), nil
+	})
+
+	assert.Nil(err)
+	assert.Equal([]int{3, 3 + MAX_LIST_LIMIT, 3 + 2*MAX_LIST_LIMIT}, skips)
+}
+
+func TestStreamedActivationColumnWidths(t *testing.T) {
+	page := []whisk.Activation{
+		{Annotations: whisk.KeyValueArr{{Key: "kind", Value: "nodejs:20"}}},
+		{Annotations: whisk.KeyValueArr{{Key: "kind", Value: "python:3"}}},
+	}
+
+	assert.Equal(t, activationColumnWidths{kind: 9, status: 7}, getActivationColumnWidths(page))
+
+	// A later page with an application error still fits the Status column of the first one
+	assert.Equal(t, activationColumnWidths{kind: 9, status: 17}, streamedActivationColumnWidths(page))
+	assert.Equal(t, activationColumnWidths{kind: 4, status: 17}, streamedActivationColumnWidths(nil))
+}
+
+func TestTruncateSnippet(t *testing.T) {
+	assert.Equal(t, "short", truncateSnippet("short", 5))
+	assert.Equal(t, "sho...", truncateSnippet("short", 3))
+
+	// "é" takes two bytes, which are never split
+	assert.Equal(t, "caf...", truncateSnippet("café au lait", 4))
+	assert.Equal(t, "café...", truncateSnippet("café au lait", 5))
+	assert.Equal(t, "...", truncateSnippet("日本", 2))
+}
+
+func TestDiffJSON(t *testing.T) {
+	original := map[string]interface{}{"msg": "hi", "count": 1, "nested": map[string]interface{}{"a": 1, "b": 2}}
+	current := map[string]interface{}{"msg": "hello", "count": 1.0, "nested": map[string]interface{}{"a": 1}, "new": true}
+
+	assert.Equal(t, []string{
+		"- .msg: \"hi\"",
+		"+ .msg: \"hello\"",
+		"- .nested.b: 2",
+		"+ .new: true",
+	}, diffJSON(original, current))
+
+	assert.Empty(t, diffJSON(original, original))
+	assert.Equal(t, []string{"- .: 1", "+ .: \"1\""}, diffJSON(1, "1"))
+}
@@ -45,6 +45,7 @@ func init() {
 {{end}}{{if or .Runnable .HasSubCommands}}{{.UsageString}}{{end}}`)
 
 	listCmd.Flags().BoolVarP(&Flags.common.nameSort, "name-sort", "n", false, wski18n.T("sorts a list alphabetically by entity name; only applicable within the limit/skip returned entity block"))
+	listCmd.Flags().BoolVar(&Flags.common.all, "all", false, wski18n.T("page through all entities in the namespace instead of the first block of each entity type"))
 
 	WskCmd.AddCommand(
 		actionCmd,
@@ -58,6 +59,7 @@ func init() {
 		listCmd,
 		apiCmd,
 		projectCmd,
+		runtimesCmd,
 	)
 
 	WskCmd.PersistentFlags().BoolVarP(&Flags.Global.Verbose, "verbose", "v", false, wski18n.T("verbose output"))
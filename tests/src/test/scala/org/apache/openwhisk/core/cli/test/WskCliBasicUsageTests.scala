@@ -677,7 +677,7 @@ class WskCliBasicUsageTests extends TestHelpers with WskTestHelpers {
     wsk.action.create(name, file, web = Some("true"), update = true, kind = Some("nodejs:14"))
 
     val existingAnnots = wsk.action.get(name, fieldFilter = Some("annotations")).stdout
-    assert(existingAnnots.startsWith(s"ok: got action $name, displaying field annotations\n"))
+    assert(!existingAnnots.startsWith("ok:"))
     removeCLIHeader(existingAnnots).parseJson.convertTo[Set[JsObject]] shouldBe createAnnotations.toJsArray
       .convertTo[Set[JsObject]]
 
@@ -691,7 +691,7 @@ class WskCliBasicUsageTests extends TestHelpers with WskTestHelpers {
 
     val updatedAnnots =
       wsk.action.get(name, fieldFilter = Some("annotations")).stdout
-    assert(updatedAnnots.startsWith(s"ok: got action $name, displaying field annotations\n"))
+    assert(!updatedAnnots.startsWith("ok:"))
     removeCLIHeader(updatedAnnots).parseJson.convertTo[Set[JsObject]] shouldBe updateAnnotations.toJsArray
       .convertTo[Set[JsObject]]
   }
@@ -1216,7 +1216,6 @@ class WskCliBasicUsageTests extends TestHelpers with WskTestHelpers {
   it should "create an action, and get its individual fields" in withAssetCleaner(wskprops) {
     val name = "actionFields"
     val paramInput = Map("payload" -> "test".toJson)
-    val successMsg = s"ok: got action $name, displaying field"
     val requireAPIKeyAnnotation = WhiskProperties.getBooleanProperty("whisk.feature.requireApiKeyAnnotation", true)
     val expectedParam = JsObject("payload" -> JsString("test"))
     val ns = wsk.namespace.whois()
@@ -1238,31 +1237,31 @@ class WskCliBasicUsageTests extends TestHelpers with WskTestHelpers {
         action.create(name, defaultAction, parameters = paramInput, kind = Some("nodejs:14"))
       }
 
-      wsk.action.get(name, fieldFilter = Some("name")).stdout should include(s"""$successMsg name\n"$name"""")
-      wsk.action.get(name, fieldFilter = Some("version")).stdout should include(s"""$successMsg version\n"0.0.1"""")
+      wsk.action.get(name, fieldFilter = Some("name")).stdout shouldBe s""""$name"\n"""
+      wsk.action.get(name, fieldFilter = Some("version")).stdout shouldBe s""""0.0.1"\n"""
       wsk.action
         .get(name, fieldFilter = Some("namespace"))
-        .stdout should include regex (s"""(?i)$successMsg namespace\n"$ns"""")
+        .stdout should include regex (s"""(?i)"$ns"""")
       wsk.action.get(name, fieldFilter = Some("invalid"), expectedExitCode = MISUSE_EXIT).stderr should include(
         "error: Invalid field filter 'invalid'.")
-      wsk.action.get(name, fieldFilter = Some("publish")).stdout should include(s"$successMsg publish\nfalse")
+      wsk.action.get(name, fieldFilter = Some("publish")).stdout shouldBe "false\n"
 
       val exec = wsk.action.get(name, fieldFilter = Some("exec")).stdout
-      assert(exec.startsWith(s"$successMsg exec"))
+      assert(!exec.startsWith("ok:"))
       removeCLIHeader(exec).parseJson.asJsObject shouldBe expectedExec
 
       val params = wsk.action.get(name, fieldFilter = Some("parameters")).stdout
-      assert(params.startsWith(s"$successMsg parameters"))
+      assert(!params.startsWith("ok:"))
       removeCLIHeader(params).parseJson.convertTo[Set[JsObject]] shouldBe expectedParams.toJsArray
         .convertTo[Set[JsObject]]
 
       val annots = wsk.action.get(name, fieldFilter = Some("annotations")).stdout
-      assert(annots.startsWith(s"$successMsg annotations"))
+      assert(!annots.startsWith("ok:"))
       removeCLIHeader(annots).parseJson.convertTo[Set[JsObject]] shouldBe expectedAnnots.toJsArray
         .convertTo[Set[JsObject]]
 
       val limits = wsk.action.get(name, fieldFilter = Some("limits")).stdout
-      assert(limits.startsWith(s"$successMsg limits"))
+      assert(!limits.startsWith("ok:"))
       removeCLIHeader(limits).parseJson.asJsObject shouldBe expectedLimits
   }
 
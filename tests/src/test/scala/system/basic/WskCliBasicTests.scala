@@ -129,7 +129,6 @@ class WskCliBasicTests extends TestHelpers with WskTestHelpers {
   it should "create a package, and get its individual fields" in withAssetCleaner(wskprops) {
     val name = "packageFields"
     val paramInput = Map("payload" -> "test".toJson)
-    val successMsg = s"ok: got package $name, displaying field"
 
     (wp, assetHelper) =>
       assetHelper.withCleaner(wsk.pkg, name) { (pkg, _) =>
@@ -141,10 +140,10 @@ class WskCliBasicTests extends TestHelpers with WskTestHelpers {
 
       wsk.pkg
         .get(name, fieldFilter = Some("namespace"))
-        .stdout should include regex (s"""(?i)$successMsg namespace\n"$ns"""")
-      wsk.pkg.get(name, fieldFilter = Some("name")).stdout should include(s"""$successMsg name\n"$name"""")
-      wsk.pkg.get(name, fieldFilter = Some("version")).stdout should include(s"""$successMsg version\n"0.0.1"""")
-      wsk.pkg.get(name, fieldFilter = Some("publish")).stdout should include(s"""$successMsg publish\nfalse""")
+        .stdout should include regex (s"""(?i)"$ns"""")
+      wsk.pkg.get(name, fieldFilter = Some("name")).stdout shouldBe s""""$name"\n"""
+      wsk.pkg.get(name, fieldFilter = Some("version")).stdout shouldBe s""""0.0.1"\n"""
+      wsk.pkg.get(name, fieldFilter = Some("publish")).stdout shouldBe "false\n"
       wsk.pkg.get(name, fieldFilter = Some("binding")).stdout should include regex (s"""\\{\\}""")
       wsk.pkg.get(name, fieldFilter = Some("invalid"), expectedExitCode = ERROR_EXIT).stderr should include(
         "error: Invalid field filter 'invalid'.")
@@ -658,7 +657,6 @@ class WskCliBasicTests extends TestHelpers with WskTestHelpers {
     val ruleName = "triggerFieldsRules"
     val actionName = "triggerFieldsAction"
     val paramInput = Map("payload" -> "test".toJson)
-    val successMsg = s"ok: got trigger $triggerName, displaying field"
 
     (wp, assetHelper) =>
       assetHelper.withCleaner(wsk.trigger, triggerName) { (trigger, name) =>
@@ -676,19 +674,15 @@ class WskCliBasicTests extends TestHelpers with WskTestHelpers {
 
       wsk.trigger
         .get(triggerName, fieldFilter = Some("namespace"))
-        .stdout should include regex (s"""(?i)$successMsg namespace\n"$ns"""")
-      wsk.trigger.get(triggerName, fieldFilter = Some("name")).stdout should include(
-        s"""$successMsg name\n"$triggerName"""")
-      wsk.trigger.get(triggerName, fieldFilter = Some("version")).stdout should include(
-        s"""$successMsg version\n"0.0.1"""")
-      wsk.trigger.get(triggerName, fieldFilter = Some("publish")).stdout should include(
-        s"""$successMsg publish\nfalse""")
-      wsk.trigger.get(triggerName, fieldFilter = Some("annotations")).stdout should include(
-        s"""$successMsg annotations\n[]""")
+        .stdout should include regex (s"""(?i)"$ns"""")
+      wsk.trigger.get(triggerName, fieldFilter = Some("name")).stdout shouldBe s""""$triggerName"\n"""
+      wsk.trigger.get(triggerName, fieldFilter = Some("version")).stdout shouldBe s""""0.0.1"\n"""
+      wsk.trigger.get(triggerName, fieldFilter = Some("publish")).stdout shouldBe "false\n"
+      wsk.trigger.get(triggerName, fieldFilter = Some("annotations")).stdout shouldBe "[]\n"
       wsk.trigger
         .get(triggerName, fieldFilter = Some("parameters"))
-        .stdout should include regex (s"""$successMsg parameters\n\\[\\s+\\{\\s+"key":\\s+"payload",\\s+"value":\\s+"test"\\s+\\}\\s+\\]""")
-      wsk.trigger.get(triggerName, fieldFilter = Some("limits")).stdout should include(s"""$successMsg limits\n{}""")
+        .stdout should include regex (s"""\\[\\s+\\{\\s+"key":\\s+"payload",\\s+"value":\\s+"test"\\s+\\}\\s+\\]""")
+      wsk.trigger.get(triggerName, fieldFilter = Some("limits")).stdout shouldBe "{}\n"
       wsk.trigger.get(triggerName, fieldFilter = Some("invalid"), expectedExitCode = ERROR_EXIT).stderr should include(
         "error: Invalid field filter 'invalid'.")
 
@@ -887,7 +881,6 @@ class WskCliBasicTests extends TestHelpers with WskTestHelpers {
     val triggerName = "ruleTriggerFields"
     val actionName = "ruleActionFields"
     val paramInput = Map("payload" -> "test".toJson)
-    val successMsg = s"ok: got rule $ruleName, displaying field"
 
     (wp, assetHelper) =>
       assetHelper.withCleaner(wsk.trigger, triggerName) { (trigger, name) =>
@@ -903,16 +896,16 @@ class WskCliBasicTests extends TestHelpers with WskTestHelpers {
       val ns = wsk.namespace.whois()
       wsk.rule
         .get(ruleName, fieldFilter = Some("namespace"))
-        .stdout should include regex (s"""(?i)$successMsg namespace\n"$ns"""")
-      wsk.rule.get(ruleName, fieldFilter = Some("name")).stdout should include(s"""$successMsg name\n"$ruleName"""")
-      wsk.rule.get(ruleName, fieldFilter = Some("version")).stdout should include(s"""$successMsg version\n"0.0.1"\n""")
-      wsk.rule.get(ruleName, fieldFilter = Some("status")).stdout should include(s"""$successMsg status\n"active"""")
+        .stdout should include regex (s"""(?i)"$ns"""")
+      wsk.rule.get(ruleName, fieldFilter = Some("name")).stdout shouldBe s""""$ruleName"\n"""
+      wsk.rule.get(ruleName, fieldFilter = Some("version")).stdout shouldBe s""""0.0.1"\n"""
+      wsk.rule.get(ruleName, fieldFilter = Some("status")).stdout shouldBe s""""active"\n"""
       val trigger = wsk.rule.get(ruleName, fieldFilter = Some("trigger")).stdout
-      trigger should include regex (s"""$successMsg trigger\n""")
+      trigger should not include ("ok:")
       trigger should include(triggerName)
       trigger should not include (actionName)
       val action = wsk.rule.get(ruleName, fieldFilter = Some("action")).stdout
-      action should include regex (s"""$successMsg action\n""")
+      action should not include ("ok:")
       action should include(actionName)
       action should not include (triggerName)
   }
@@ -0,0 +1,44 @@
+/*
+ * Runs the actions of the project against the cases of test/cases.json:
+ *
+ *     node test/run.js
+ *
+ * A case gives an action, or a sequence of actions each given the result of the one before, the
+ * parameters to invoke it with and the values the result is expected to hold.
+ */
+const assert = require('assert');
+const fs = require('fs');
+const path = require('path');
+
+const root = path.join(__dirname, '..');
+const cases = JSON.parse(fs.readFileSync(path.join(__dirname, 'cases.json'), 'utf8'));
+
+function invoke(name, params) {
+  return Promise.resolve(require(path.join(root, 'actions', name + '.js')).main(params));
+}
+
+async function run() {
+  let failed = 0;
+  for (const test of cases) {
+    const steps = test.sequence || [test.action];
+    const label = steps.join(' -> ');
+    try {
+      let result = test.params || {};
+      for (const step of steps) {
+        result = await invoke(step, result);
+      }
+      for (const key of Object.keys(test.expect || {})) {
+        assert.deepStrictEqual(result[key], test.expect[key], key);
+      }
+      console.log('ok:     ' + label);
+    } catch (err) {
+      failed++;
+      console.log('failed: ' + label + ': ' + err.message);
+    }
+  }
+
+  console.log(`${cases.length - failed} of ${cases.length} cases passed`);
+  process.exit(failed > 0 ? 1 : 0);
+}
+
+run();
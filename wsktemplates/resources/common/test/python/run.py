@@ -0,0 +1,49 @@
+"""Runs the actions of the project against the cases of test/cases.json:
+
+    python3 test/run.py
+
+A case gives an action, or a sequence of actions each given the result of the one before, the
+parameters to invoke it with and the values the result is expected to hold.
+"""
+import importlib.util
+import json
+import os
+import sys
+
+TEST_DIR = os.path.dirname(os.path.abspath(__file__))
+ACTIONS_DIR = os.path.join(TEST_DIR, '..', 'actions')
+
+
+def invoke(name, params):
+    spec = importlib.util.spec_from_file_location(name.replace('-', '_'), os.path.join(ACTIONS_DIR, name + '.py'))
+    module = importlib.util.module_from_spec(spec)
+    spec.loader.exec_module(module)
+    return module.main(params)
+
+
+def run():
+    with open(os.path.join(TEST_DIR, 'cases.json')) as f:
+        cases = json.load(f)
+
+    failed = 0
+    for case in cases:
+        steps = case.get('sequence') or [case['action']]
+        label = ' -> '.join(steps)
+        try:
+            result = case.get('params', {})
+            for step in steps:
+                result = invoke(step, result)
+            for key, expected in case.get('expect', {}).items():
+                if result.get(key) != expected:
+                    raise AssertionError('%s: expected %r, got %r' % (key, expected, result.get(key)))
+            print('ok:     ' + label)
+        except Exception as err:
+            failed += 1
+            print('failed: %s: %s' % (label, err))
+
+    print('%d of %d cases passed' % (len(cases) - failed, len(cases)))
+    sys.exit(1 if failed else 0)
+
+
+if __name__ == '__main__':
+    run()
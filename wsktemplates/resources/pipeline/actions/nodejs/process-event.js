@@ -0,0 +1,13 @@
+/**
+ * Processes an event fired on the trigger, which gives its type and the fields of the event.
+ */
+function main(params) {
+  if (!params.type) {
+    return { processed: false, error: 'the event has no type' };
+  }
+
+  const fields = Object.keys(params).filter(key => key !== 'type' && key !== 'label');
+  return { processed: true, summary: `${params.label || 'event'} ${params.type}`, fields: fields.sort() };
+}
+
+exports.main = main;
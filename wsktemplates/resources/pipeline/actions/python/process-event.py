@@ -0,0 +1,9 @@
+"""Processes an event fired on the trigger, which gives its type and the fields of the event."""
+
+
+def main(params):
+    if not params.get('type'):
+        return {'processed': False, 'error': 'the event has no type'}
+
+    fields = sorted(key for key in params if key not in ('type', 'label'))
+    return {'processed': True, 'summary': '%s %s' % (params.get('label') or 'event', params['type']), 'fields': fields}
@@ -0,0 +1,11 @@
+/**
+ * Runs on the schedule and works out which records are older than retention_days.
+ */
+function main(params) {
+  const days = parseInt(params.retention_days, 10) || 30;
+  const now = params.now ? new Date(params.now) : new Date();
+  const cutoff = new Date(now.getTime() - days * 24 * 60 * 60 * 1000);
+  return { retention_days: days, cutoff: cutoff.toISOString() };
+}
+
+exports.main = main;
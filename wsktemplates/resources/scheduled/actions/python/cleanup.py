@@ -0,0 +1,9 @@
+"""Runs on the schedule and works out which records are older than retention_days."""
+from datetime import datetime, timedelta, timezone
+
+
+def main(params):
+    days = int(params.get('retention_days') or 30)
+    now = datetime.fromisoformat(params['now'].replace('Z', '+00:00')) if params.get('now') else datetime.now(timezone.utc)
+    cutoff = now - timedelta(days=days)
+    return {'retention_days': days, 'cutoff': cutoff.strftime('%Y-%m-%dT%H:%M:%S.000Z')}
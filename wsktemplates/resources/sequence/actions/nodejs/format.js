@@ -0,0 +1,9 @@
+/**
+ * Joins the words again with the separator.
+ */
+function main(params) {
+  const words = params.words || [];
+  return { text: words.join(params.separator || ' '), count: words.length };
+}
+
+exports.main = main;
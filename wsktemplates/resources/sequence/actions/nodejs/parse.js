@@ -0,0 +1,10 @@
+/**
+ * Splits a text into words by the separator.
+ */
+function main(params) {
+  const separator = params.separator || ' ';
+  const words = (params.text || '').split(separator).filter(word => word.length > 0);
+  return { words: words, separator: separator };
+}
+
+exports.main = main;
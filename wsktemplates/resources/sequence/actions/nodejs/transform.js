@@ -0,0 +1,8 @@
+/**
+ * Changes the words to upper case.
+ */
+function main(params) {
+  return { words: (params.words || []).map(word => word.toUpperCase()), separator: params.separator };
+}
+
+exports.main = main;
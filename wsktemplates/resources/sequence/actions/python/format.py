@@ -0,0 +1,6 @@
+"""Joins the words again with the separator."""
+
+
+def main(params):
+    words = params.get('words') or []
+    return {'text': (params.get('separator') or ' ').join(words), 'count': len(words)}
@@ -0,0 +1,7 @@
+"""Splits a text into words by the separator."""
+
+
+def main(params):
+    separator = params.get('separator') or ' '
+    words = [word for word in (params.get('text') or '').split(separator) if word]
+    return {'words': words, 'separator': separator}
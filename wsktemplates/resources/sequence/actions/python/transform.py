@@ -0,0 +1,5 @@
+"""Changes the words to upper case."""
+
+
+def main(params):
+    return {'words': [word.upper() for word in params.get('words') or []], 'separator': params.get('separator')}
@@ -0,0 +1,11 @@
+/**
+ * Validates a book to add to the catalog, which needs a title and an author.
+ */
+function main(params) {
+  if (!params.title || !params.author) {
+    return { added: false, error: 'a title and an author are required' };
+  }
+  return { added: true, book: { title: params.title, author: params.author } };
+}
+
+exports.main = main;
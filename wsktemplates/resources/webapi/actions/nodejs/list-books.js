@@ -0,0 +1,16 @@
+/**
+ * Lists the books of the catalog, at most max_results of them.
+ */
+const BOOKS = [
+  { id: 1, title: 'The Left Hand of Darkness', author: 'Ursula K. Le Guin' },
+  { id: 2, title: 'Kindred', author: 'Octavia E. Butler' },
+  { id: 3, title: 'Solaris', author: 'Stanislaw Lem' },
+];
+
+function main(params) {
+  const max = parseInt(params.max_results, 10) || BOOKS.length;
+  const books = BOOKS.slice(0, max);
+  return { count: books.length, books: books };
+}
+
+exports.main = main;
@@ -0,0 +1,7 @@
+"""Validates a book to add to the catalog, which needs a title and an author."""
+
+
+def main(params):
+    if not params.get('title') or not params.get('author'):
+        return {'added': False, 'error': 'a title and an author are required'}
+    return {'added': True, 'book': {'title': params['title'], 'author': params['author']}}
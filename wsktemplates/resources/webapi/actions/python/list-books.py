@@ -0,0 +1,13 @@
+"""Lists the books of the catalog, at most max_results of them."""
+
+BOOKS = [
+    {'id': 1, 'title': 'The Left Hand of Darkness', 'author': 'Ursula K. Le Guin'},
+    {'id': 2, 'title': 'Kindred', 'author': 'Octavia E. Butler'},
+    {'id': 3, 'title': 'Solaris', 'author': 'Stanislaw Lem'},
+]
+
+
+def main(params):
+    max_results = int(params.get('max_results') or len(BOOKS))
+    books = BOOKS[:max_results]
+    return {'count': len(books), 'books': books}
@@ -0,0 +1,61 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wsktemplates
+
+import (
+	"sort"
+	"strings"
+)
+
+// Files of the common template are part of every project, unless its template has its own
+const COMMON = "common"
+
+// Names returns the names of the embedded project templates
+func Names() []string {
+	var names []string
+	seen := map[string]bool{COMMON: true}
+	for _, asset := range AssetNames() {
+		name := strings.SplitN(asset, "/", 2)[0]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Files returns the contents of the files of an embedded template by their path within it, or nil
+// when there is no such template
+func Files(name string) (map[string][]byte, error) {
+	var files map[string][]byte
+	for _, asset := range AssetNames() {
+		if !strings.HasPrefix(asset, name+"/") {
+			continue
+		}
+		contents, err := Asset(asset)
+		if err != nil {
+			return nil, err
+		}
+		if files == nil {
+			files = make(map[string][]byte)
+		}
+		files[strings.TrimPrefix(asset, name+"/")] = contents
+	}
+	return files, nil
+}